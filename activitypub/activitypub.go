@@ -0,0 +1,171 @@
+// Package activitypub implements the parts of ActivityPub needed to federate
+// our feeds: actors, notes of entries, WebFinger and HTTP signatures for
+// server to server delivery.
+//
+// See https://www.w3.org/TR/activitypub/
+package activitypub
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	// ContentType of activity documents.
+	ContentType = "application/activity+json"
+	// ContentTypeLD is the alternative content type accepted by servers.
+	ContentTypeLD = `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`
+
+	Context  = "https://www.w3.org/ns/activitystreams"
+	Security = "https://w3id.org/security/v1"
+	// Public addresses activity to everyone.
+	Public = "https://www.w3.org/ns/activitystreams#Public"
+)
+
+// Actor is a person, or our feed.
+type Actor struct {
+	Context           interface{} `json:"@context,omitempty"`
+	Id                string      `json:"id"`
+	Type              string      `json:"type"`
+	PreferredUsername string      `json:"preferredUsername,omitempty"`
+	Name              string      `json:"name,omitempty"`
+	Summary           string      `json:"summary,omitempty"`
+	Url               string      `json:"url,omitempty"`
+	Icon              *Image      `json:"icon,omitempty"`
+	Inbox             string      `json:"inbox"`
+	Outbox            string      `json:"outbox,omitempty"`
+	Followers         string      `json:"followers,omitempty"`
+	Following         string      `json:"following,omitempty"`
+	Endpoints         *Endpoints  `json:"endpoints,omitempty"`
+	PublicKey         *PublicKey  `json:"publicKey,omitempty"`
+}
+
+type Endpoints struct {
+	SharedInbox string `json:"sharedInbox,omitempty"`
+}
+
+type PublicKey struct {
+	Id           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPem string `json:"publicKeyPem"`
+}
+
+type Image struct {
+	Type      string `json:"type"`
+	MediaType string `json:"mediaType,omitempty"`
+	Url       string `json:"url"`
+}
+
+// Activity is an activity of inbox or outbox, object is a id or an object.
+type Activity struct {
+	Context   interface{}     `json:"@context,omitempty"`
+	Id        string          `json:"id"`
+	Type      string          `json:"type"`
+	Actor     string          `json:"actor"`
+	Published string          `json:"published,omitempty"`
+	To        []string        `json:"to,omitempty"`
+	Cc        []string        `json:"cc,omitempty"`
+	Object    json.RawMessage `json:"object"`
+}
+
+// ObjectId returns id of object, whether referenced by id or embedded.
+func (a *Activity) ObjectId() string {
+	return objectId(a.Object)
+}
+
+// ObjectType returns type of embedded object, empty if referenced by id.
+func (a *Activity) ObjectType() string {
+	var obj struct {
+		Type string `json:"type"`
+	}
+	json.Unmarshal(a.Object, &obj)
+	return obj.Type
+}
+
+// Embedded decodes embedded object into v.
+func (a *Activity) Embedded(v interface{}) error {
+	if len(a.Object) == 0 || a.Object[0] != '{' {
+		return fmt.Errorf("activitypub: object not embedded")
+	}
+	return json.Unmarshal(a.Object, v)
+}
+
+// NewActivity returns activity of actor wrapping object.
+func NewActivity(id, typ, actor string, object interface{}) (*Activity, error) {
+	raw, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	return &Activity{
+		Context: Context,
+		Id:      id,
+		Type:    typ,
+		Actor:   actor,
+		Object:  raw,
+	}, nil
+}
+
+// Note is a post, entries of our feeds and replies of remote actors.
+type Note struct {
+	Context      interface{}     `json:"@context,omitempty"`
+	Id           string          `json:"id"`
+	Type         string          `json:"type"`
+	AttributedTo string          `json:"attributedTo,omitempty"`
+	InReplyTo    json.RawMessage `json:"inReplyTo,omitempty"`
+	Published    string          `json:"published,omitempty"`
+	Url          string          `json:"url,omitempty"`
+	To           []string        `json:"to,omitempty"`
+	Cc           []string        `json:"cc,omitempty"`
+	Summary      string          `json:"summary,omitempty"`
+	Content      string          `json:"content"`
+	Attachment   []*Attachment   `json:"attachment,omitempty"`
+}
+
+// InReplyToId returns id of object replied.
+func (n *Note) InReplyToId() string {
+	return objectId(n.InReplyTo)
+}
+
+type Attachment struct {
+	Type      string `json:"type"`
+	MediaType string `json:"mediaType,omitempty"`
+	Url       string `json:"url"`
+	Name      string `json:"name,omitempty"`
+	Width     int32  `json:"width,omitempty"`
+	Height    int32  `json:"height,omitempty"`
+}
+
+// OrderedCollection is a collection, or a page of it.
+type OrderedCollection struct {
+	Context      interface{}   `json:"@context,omitempty"`
+	Id           string        `json:"id"`
+	Type         string        `json:"type"`
+	TotalItems   *int          `json:"totalItems,omitempty"`
+	First        string        `json:"first,omitempty"`
+	Next         string        `json:"next,omitempty"`
+	PartOf       string        `json:"partOf,omitempty"`
+	OrderedItems []interface{} `json:"orderedItems,omitempty"`
+}
+
+func objectId(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var obj struct {
+		Id string `json:"id"`
+	}
+	json.Unmarshal(raw, &obj)
+	return obj.Id
+}
+
+// IsActivity reports whether content type or accept header asks for
+// activity documents.
+func IsActivity(header string) bool {
+	return strings.Contains(header, ContentType) ||
+		strings.Contains(header, "application/ld+json")
+}
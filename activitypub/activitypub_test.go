@@ -0,0 +1,140 @@
+package activitypub
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	pb "github.com/yinhm/friendfeed/proto"
+)
+
+func TestSignature(t *testing.T) {
+	Convey("Given signed request, verify by public key of key id", t, func() {
+		private, public, err := GenerateKey()
+		So(err, ShouldBeNil)
+		key, err := ParsePrivateKey(private)
+		So(err, ShouldBeNil)
+		pub, err := ParsePublicKey(public)
+		So(err, ShouldBeNil)
+
+		keyId := "https://remote.example.com/users/bob#main-key"
+		lookup := func(id string) (*rsa.PublicKey, error) {
+			if id != keyId {
+				return nil, fmt.Errorf("unknown key")
+			}
+			return pub, nil
+		}
+
+		body := []byte(`{"type": "Follow"}`)
+		req, _ := http.NewRequest("POST", "https://ff.example.com/ap/users/yinhm/inbox?x=1", bytes.NewReader(body))
+		So(Sign(req, body, keyId, key), ShouldBeNil)
+		So(req.Header.Get("Digest"), ShouldEqual, Digest(body))
+		So(req.Header.Get("Signature"), ShouldContainSubstring, `headers="(request-target) host date digest"`)
+
+		id, err := Verify(req, body, lookup)
+		So(err, ShouldBeNil)
+		So(id, ShouldEqual, keyId)
+
+		// body tampered
+		_, err = Verify(req, []byte(`{"type": "Like"}`), lookup)
+		So(err, ShouldNotBeNil)
+
+		// request target tampered
+		req.URL.Path = "/ap/users/other/inbox"
+		_, err = Verify(req, body, lookup)
+		So(err, ShouldNotBeNil)
+		req.URL.Path = "/ap/users/yinhm/inbox"
+
+		// stale date
+		req.Header.Set("Date", time.Now().Add(-24*time.Hour).UTC().Format(http.TimeFormat))
+		_, err = Verify(req, body, lookup)
+		So(err, ShouldNotBeNil)
+
+		// signed get, no digest
+		get, _ := http.NewRequest("GET", "https://remote.example.com/users/bob", nil)
+		So(Sign(get, nil, keyId, key), ShouldBeNil)
+		_, err = Verify(get, nil, lookup)
+		So(err, ShouldBeNil)
+
+		params := parseSignature(`keyId="a,b",algorithm="hs2019",headers="date",signature="c="`)
+		So(params["keyId"], ShouldEqual, "a,b")
+		So(params["signature"], ShouldEqual, "c=")
+	})
+}
+
+func TestWebFinger(t *testing.T) {
+	Convey("Given acct resource, link to actor", t, func() {
+		user, host, err := ParseAcct("acct:yinhm@ff.example.com")
+		So(err, ShouldBeNil)
+		So(user, ShouldEqual, "yinhm")
+		So(host, ShouldEqual, "ff.example.com")
+		_, _, err = ParseAcct("acct:yinhm")
+		So(err, ShouldNotBeNil)
+
+		jrd := WebFinger("https://ff.example.com", host, user)
+		So(jrd.Subject, ShouldEqual, "acct:yinhm@ff.example.com")
+		So(jrd.Links[0].Href, ShouldEqual, "https://ff.example.com/ap/users/yinhm")
+		So(jrd.Links[0].Type, ShouldEqual, ContentType)
+	})
+}
+
+func TestEntryActivity(t *testing.T) {
+	Convey("Given entry, map to note of its author", t, func() {
+		base := "https://ff.example.com"
+		entry := &pb.Entry{
+			Id:         "4f6c8a2ad0c04b3a9f1f3d5a6b7c8d94",
+			Date:       "2015-04-09T07:40:22Z",
+			Body:       `hello <a href="http://example.com/">example</a>`,
+			From:       &pb.Feed{Id: "yinhm", Name: "Heming", Type: "user"},
+			Thumbnails: []*pb.Thumbnail{{Url: "http://example.com/1.jpg", Width: 100, Height: 80}},
+			Files:      []*pb.File{{Url: "http://example.com/1.mp3", Type: "audio/mpeg", Name: "1.mp3"}},
+		}
+		activity, err := EntryActivity("Create", entry, base)
+		So(err, ShouldBeNil)
+		So(activity.Actor, ShouldEqual, "https://ff.example.com/ap/users/yinhm")
+		So(activity.ObjectId(), ShouldEqual, "https://ff.example.com/e/4f6c8a2ad0c04b3a9f1f3d5a6b7c8d94")
+		So(activity.ObjectType(), ShouldEqual, "Note")
+
+		note := new(Note)
+		So(activity.Embedded(note), ShouldBeNil)
+		So(note.Content, ShouldEqual, `<p>hello <a href="http://example.com/">example</a></p>`)
+		So(note.To, ShouldResemble, []string{Public})
+		So(len(note.Attachment), ShouldEqual, 2)
+		So(note.Attachment[1].MediaType, ShouldEqual, "audio/mpeg")
+
+		id, ok := EntryId(base, note.Id)
+		So(ok, ShouldBeTrue)
+		So(id, ShouldEqual, entry.Id)
+		_, ok = EntryId(base, "https://other.example.com/e/"+entry.Id)
+		So(ok, ShouldBeFalse)
+
+		activity, err = EntryActivity("Delete", entry, base)
+		So(err, ShouldBeNil)
+		So(activity.ObjectType(), ShouldEqual, "Tombstone")
+		_, err = EntryActivity("Announce", entry, base)
+		So(err, ShouldNotBeNil)
+
+		raw, _ := json.Marshal(map[string]interface{}{"inReplyTo": map[string]string{"id": note.Id}})
+		reply := new(Note)
+		So(json.Unmarshal(raw, reply), ShouldBeNil)
+		So(reply.InReplyToId(), ShouldEqual, note.Id)
+		So(PlainText("<p>a &amp; <span>b</span></p><p>c</p>"), ShouldEqual, "a & b c")
+	})
+
+	Convey("Only entries to own feed or public groups are public", t, func() {
+		entry := &pb.Entry{From: &pb.Feed{Id: "yinhm", Type: "user"}}
+		So(IsPublic(entry), ShouldBeTrue)
+		entry.To = []*pb.Feed{{Id: "yinhm", Type: "user"}, {Id: "golang", Type: "group"}}
+		So(IsPublic(entry), ShouldBeTrue)
+		entry.To = []*pb.Feed{{Id: "golang", Type: "group", Private: true}}
+		So(IsPublic(entry), ShouldBeFalse)
+		// direct message
+		entry.To = []*pb.Feed{{Id: "golang", Type: "group"}, {Id: "friend", Type: "user"}}
+		So(IsPublic(entry), ShouldBeFalse)
+	})
+}
@@ -0,0 +1,77 @@
+package activitypub
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+const maxDocumentSize = 1 << 20
+
+// ErrGone means inbox or actor no longer exists, follower should be removed.
+var ErrGone = fmt.Errorf("activitypub: gone")
+
+// FetchActor fetches actor document, request signed if key given as some
+// servers require signed fetches.
+func FetchActor(ctx context.Context, client *http.Client, actorId, keyId string, key *rsa.PrivateKey) (*Actor, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", actorId, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", ContentType+", "+ContentTypeLD)
+	if key != nil {
+		if err := Sign(req, nil, keyId, key); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusGone || resp.StatusCode == http.StatusNotFound:
+		return nil, ErrGone
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("activitypub: %s returned %s", actorId, resp.Status)
+	}
+
+	actor := new(Actor)
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxDocumentSize)).Decode(actor); err != nil {
+		return nil, err
+	}
+	if actor.Id != actorId || actor.Inbox == "" {
+		return nil, fmt.Errorf("activitypub: bad actor %s", actorId)
+	}
+	return actor, nil
+}
+
+// Deliver posts signed activity to inbox. ErrGone returned if inbox gone.
+func Deliver(ctx context.Context, client *http.Client, inbox, keyId string, key *rsa.PrivateKey, activity []byte) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", inbox, bytes.NewReader(activity))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", ContentTypeLD)
+	if err := Sign(req, activity, keyId, key); err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxDocumentSize))
+	switch {
+	case resp.StatusCode == http.StatusGone:
+		return ErrGone
+	case resp.StatusCode/100 != 2:
+		return fmt.Errorf("activitypub: inbox returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package activitypub
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	pb "github.com/yinhm/friendfeed/proto"
+)
+
+// ActorURL returns actor id of feed, eg: http://example.com/ap/users/foo
+func ActorURL(base, feedId string) string {
+	return base + "/ap/users/" + feedId
+}
+
+// KeyId returns id of the public key of actor.
+func KeyId(actor string) string {
+	return actor + "#main-key"
+}
+
+// EntryURL returns note id of entry, the html page of entry.
+func EntryURL(base, entryId string) string {
+	return base + "/e/" + entryId
+}
+
+// EntryId returns entry id of note id, false if note is not ours.
+func EntryId(base, noteId string) (string, bool) {
+	prefix := EntryURL(base, "")
+	if !strings.HasPrefix(noteId, prefix) {
+		return "", false
+	}
+	id := strings.TrimPrefix(noteId, prefix)
+	return id, id != "" && !strings.ContainsAny(id, "/?#")
+}
+
+// BaseURL returns site url of actor id.
+func BaseURL(actor, feedId string) string {
+	return strings.TrimSuffix(actor, "/ap/users/"+feedId)
+}
+
+// IsPublic reports whether entry may be federated as public note: posted to
+// the author's feed or public groups only, never direct messages.
+func IsPublic(entry *pb.Entry) bool {
+	for _, to := range entry.To {
+		if to == nil || (entry.From != nil && to.Id == entry.From.Id) {
+			continue
+		}
+		if to.Type != "group" || to.Private {
+			return false
+		}
+	}
+	return true
+}
+
+// NoteFromEntry returns public note of entry, entry author is the actor.
+func NoteFromEntry(entry *pb.Entry, base string) *Note {
+	actor := ActorURL(base, entry.From.Id)
+	body := entry.Body
+	if body == "" {
+		body = html.EscapeString(entry.RawBody)
+	}
+	note := &Note{
+		Id:           EntryURL(base, entry.Id),
+		Type:         "Note",
+		AttributedTo: actor,
+		Published:    entry.Date,
+		Url:          EntryURL(base, entry.Id),
+		To:           []string{Public},
+		Cc:           []string{actor + "/followers"},
+		Content:      "<p>" + body + "</p>",
+	}
+	for _, t := range entry.Thumbnails {
+		if t.Url == "" {
+			continue
+		}
+		note.Attachment = append(note.Attachment, &Attachment{
+			Type:   "Image",
+			Url:    t.Url,
+			Width:  t.Width,
+			Height: t.Height,
+		})
+	}
+	for _, f := range entry.Files {
+		if f.Url == "" {
+			continue
+		}
+		note.Attachment = append(note.Attachment, &Attachment{
+			Type:      "Document",
+			MediaType: f.Type,
+			Url:       f.Url,
+			Name:      f.Name,
+		})
+	}
+	return note
+}
+
+// EntryActivity returns Create, Update or Delete activity of entry by its
+// author.
+func EntryActivity(typ string, entry *pb.Entry, base string) (*Activity, error) {
+	note := NoteFromEntry(entry, base)
+
+	var object interface{} = note
+	id := note.Id + "/activity"
+	switch typ {
+	case "Create":
+	case "Update":
+		id = fmt.Sprintf("%s#updates/%d", note.Id, time.Now().Unix())
+	case "Delete":
+		id = note.Id + "#delete"
+		object = map[string]string{"id": note.Id, "type": "Tombstone"}
+	default:
+		return nil, fmt.Errorf("activitypub: unsupported activity %s", typ)
+	}
+	activity, err := NewActivity(id, typ, note.AttributedTo, object)
+	if err != nil {
+		return nil, err
+	}
+	activity.Published = note.Published
+	activity.To = note.To
+	activity.Cc = note.Cc
+	return activity, nil
+}
+
+var (
+	blockRe = regexp.MustCompile(`(?i)</p>|<br\s*/?>`)
+	tagRe   = regexp.MustCompile(`<[^>]*>`)
+)
+
+// PlainText returns text of html content of remote notes.
+func PlainText(content string) string {
+	content = blockRe.ReplaceAllString(content, " ")
+	content = html.UnescapeString(tagRe.ReplaceAllString(content, ""))
+	return strings.Join(strings.Fields(content), " ")
+}
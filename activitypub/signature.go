@@ -0,0 +1,218 @@
+package activitypub
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	keyBits = 2048
+	// signed requests older than maxClockSkew rejected
+	maxClockSkew = 12 * time.Hour
+)
+
+// GenerateKey returns PEM encoded private and public keys.
+func GenerateKey() (privatePem, publicPem string, err error) {
+	key, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return "", "", err
+	}
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", "", err
+	}
+	privatePem = string(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}))
+	publicPem = string(pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: pub,
+	}))
+	return privatePem, publicPem, nil
+}
+
+func ParsePrivateKey(privatePem string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(privatePem))
+	if block == nil {
+		return nil, fmt.Errorf("activitypub: bad private key")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("activitypub: private key is not rsa")
+	}
+	return rsaKey, nil
+}
+
+func ParsePublicKey(publicPem string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(publicPem))
+	if block == nil {
+		return nil, fmt.Errorf("activitypub: bad public key")
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("activitypub: public key is not rsa")
+	}
+	return rsaKey, nil
+}
+
+// Digest returns digest header of body.
+func Digest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// Sign signs request by HTTP signatures, as Mastodon expects. Date, Host and
+// Digest of body are set and signed.
+//
+// See https://datatracker.ietf.org/doc/html/draft-cavage-http-signatures-12
+func Sign(req *http.Request, body []byte, keyId string, key *rsa.PrivateKey) error {
+	req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("Host", req.URL.Host)
+	headers := []string{"(request-target)", "host", "date"}
+	if body != nil {
+		req.Header.Set("Digest", Digest(body))
+		headers = append(headers, "digest")
+	}
+
+	hashed := sha256.Sum256([]byte(signingString(req, headers)))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Signature", fmt.Sprintf(`keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		keyId, strings.Join(headers, " "), base64.StdEncoding.EncodeToString(sig)))
+	return nil
+}
+
+// KeyFunc returns public key of key id.
+type KeyFunc func(keyId string) (*rsa.PublicKey, error)
+
+// Verify verifies signature of request, returns key id signed. Request
+// target, host and date must be signed, and digest if body not empty.
+func Verify(req *http.Request, body []byte, lookup KeyFunc) (string, error) {
+	params := parseSignature(req.Header.Get("Signature"))
+	keyId, signature := params["keyId"], params["signature"]
+	if keyId == "" || signature == "" {
+		return "", fmt.Errorf("activitypub: missing signature")
+	}
+	switch params["algorithm"] {
+	case "", "rsa-sha256", "hs2019":
+	default:
+		return "", fmt.Errorf("activitypub: unsupported algorithm %s", params["algorithm"])
+	}
+	headers := strings.Fields(strings.ToLower(params["headers"]))
+	if len(headers) == 0 {
+		headers = []string{"date"}
+	}
+	signed := make(map[string]bool)
+	for _, h := range headers {
+		signed[h] = true
+	}
+	if !signed["(request-target)"] || !signed["host"] || !signed["date"] {
+		return "", fmt.Errorf("activitypub: request target, host and date must be signed")
+	}
+
+	date, err := http.ParseTime(req.Header.Get("Date"))
+	if err != nil {
+		return "", fmt.Errorf("activitypub: bad date")
+	}
+	if skew := time.Since(date); skew > maxClockSkew || skew < -maxClockSkew {
+		return "", fmt.Errorf("activitypub: date out of range")
+	}
+	if len(body) > 0 {
+		if !signed["digest"] {
+			return "", fmt.Errorf("activitypub: digest must be signed")
+		}
+		if req.Header.Get("Digest") != Digest(body) {
+			return "", fmt.Errorf("activitypub: digest mismatch")
+		}
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return "", fmt.Errorf("activitypub: bad signature")
+	}
+	key, err := lookup(keyId)
+	if err != nil {
+		return "", err
+	}
+	hashed := sha256.Sum256([]byte(signingString(req, headers)))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], sig); err != nil {
+		return "", fmt.Errorf("activitypub: signature mismatch")
+	}
+	return keyId, nil
+}
+
+func signingString(req *http.Request, headers []string) string {
+	lines := make([]string, 0, len(headers))
+	for _, h := range headers {
+		var value string
+		switch h {
+		case "(request-target)":
+			value = strings.ToLower(req.Method) + " " + req.URL.RequestURI()
+		case "host":
+			value = req.Header.Get("Host")
+			if value == "" {
+				value = req.Host
+			}
+		default:
+			value = strings.Join(req.Header.Values(h), ", ")
+		}
+		lines = append(lines, h+": "+value)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseSignature parses key="value" pairs of signature header.
+func parseSignature(header string) map[string]string {
+	params := make(map[string]string)
+	for header != "" {
+		i := strings.Index(header, "=")
+		if i < 0 {
+			break
+		}
+		name := strings.TrimSpace(header[:i])
+		header = strings.TrimSpace(header[i+1:])
+		var value string
+		if strings.HasPrefix(header, `"`) {
+			end := strings.Index(header[1:], `"`)
+			if end < 0 {
+				break
+			}
+			value = header[1 : end+1]
+			header = header[end+2:]
+		} else if end := strings.Index(header, ","); end >= 0 {
+			value = header[:end]
+			header = header[end:]
+		} else {
+			value, header = header, ""
+		}
+		params[name] = value
+		header = strings.TrimLeft(header, ", ")
+	}
+	return params
+}
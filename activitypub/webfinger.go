@@ -0,0 +1,49 @@
+package activitypub
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ContentTypeJRD of webfinger responses.
+const ContentTypeJRD = "application/jrd+json"
+
+// JRD is the webfinger resource descriptor of our feeds.
+//
+// See https://datatracker.ietf.org/doc/html/rfc7033
+type JRD struct {
+	Subject string   `json:"subject"`
+	Aliases []string `json:"aliases,omitempty"`
+	Links   []*Link  `json:"links"`
+}
+
+type Link struct {
+	Rel  string `json:"rel"`
+	Type string `json:"type,omitempty"`
+	Href string `json:"href"`
+}
+
+// ParseAcct returns user and host of acct resource, eg: acct:foo@example.com
+func ParseAcct(resource string) (user, host string, err error) {
+	acct := strings.TrimPrefix(resource, "acct:")
+	acct = strings.TrimPrefix(acct, "@")
+	i := strings.LastIndex(acct, "@")
+	if i <= 0 || i == len(acct)-1 {
+		return "", "", fmt.Errorf("activitypub: bad resource %s", resource)
+	}
+	return acct[:i], acct[i+1:], nil
+}
+
+// WebFinger returns descriptor of feed, linking to its actor and profile page.
+func WebFinger(base, host, feedId string) *JRD {
+	actor := ActorURL(base, feedId)
+	page := base + "/feed/" + feedId
+	return &JRD{
+		Subject: "acct:" + feedId + "@" + host,
+		Aliases: []string{page, actor},
+		Links: []*Link{
+			{Rel: "self", Type: ContentType, Href: actor},
+			{Rel: "http://webfinger.net/rel/profile-page", Type: "text/html", Href: page},
+		},
+	}
+}
@@ -0,0 +1,201 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/yinhm/friendfeed/ff"
+	"github.com/yinhm/friendfeed/importer"
+	pb "github.com/yinhm/friendfeed/proto"
+	"google.golang.org/grpc"
+)
+
+// fakeClient serves queued jobs, records finished and released.
+type fakeClient struct {
+	pb.ApiClient
+
+	mu       sync.Mutex
+	jobs     []*pb.FeedJob
+	finished []string
+	released []string
+}
+
+func (c *fakeClient) GetFeedJob(ctx context.Context, in *pb.Worker, opts ...grpc.CallOption) (*pb.FeedJob, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.jobs) == 0 {
+		return nil, fmt.Errorf("No more job available")
+	}
+	job := c.jobs[0]
+	c.jobs = c.jobs[1:]
+	return job, nil
+}
+
+func (c *fakeClient) FinishJob(ctx context.Context, job *pb.FeedJob, opts ...grpc.CallOption) (*pb.FeedJob, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.finished = append(c.finished, job.Id)
+	return job, nil
+}
+
+func (c *fakeClient) ReleaseJob(ctx context.Context, job *pb.FeedJob, opts ...grpc.CallOption) (*pb.FeedJob, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.released = append(c.released, job.Id)
+	return job, nil
+}
+
+func (c *fakeClient) done() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.finished) + len(c.released)
+}
+
+func TestBucket(t *testing.T) {
+	Convey("Given rate limit, take tokens of burst then wait", t, func() {
+		b := NewBucket(Rate{Limit: 10, Burst: 2})
+		now := b.last
+		So(b.take(now), ShouldEqual, 0)
+		So(b.take(now), ShouldEqual, 0)
+		So(b.take(now), ShouldEqual, 100*time.Millisecond)
+		So(b.take(now.Add(100*time.Millisecond)), ShouldEqual, 0)
+
+		unlimited := NewBucket(Rate{})
+		for i := 0; i < 100; i++ {
+			So(unlimited.take(time.Now()), ShouldEqual, 0)
+		}
+
+		// backoff doubled on consecutive failures, with jitter
+		b.minBackoff = time.Second
+		b.maxBackoff = 4 * time.Second
+		d := b.Fail(0)
+		So(d, ShouldBeBetweenOrEqual, 500*time.Millisecond, time.Second)
+		So(b.take(time.Now()), ShouldBeGreaterThan, 0)
+		d = b.Fail(0)
+		So(d, ShouldBeBetweenOrEqual, time.Second, 2*time.Second)
+		b.Fail(0)
+		d = b.Fail(0)
+		So(d, ShouldBeBetweenOrEqual, 2*time.Second, 4*time.Second)
+		So(b.Fail(time.Minute), ShouldEqual, time.Minute)
+		b.Succeed()
+		So(b.Fail(0), ShouldBeLessThanOrEqualTo, time.Second)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		So(b.Wait(ctx), ShouldEqual, context.Canceled)
+
+		l := NewLimiter(Rate{Limit: 1}, map[string]Rate{"twitter": {Limit: 5, Burst: 3}})
+		So(l.Bucket("twitter").rate.Burst, ShouldEqual, 3)
+		So(l.Bucket("blog").rate.Limit, ShouldEqual, 1)
+		So(l.Bucket("blog"), ShouldEqual, l.Bucket("blog"))
+	})
+}
+
+func TestPool(t *testing.T) {
+	Convey("Given jobs, run concurrently and release throttled", t, func() {
+		client := &fakeClient{
+			jobs: []*pb.FeedJob{
+				{Id: "a"},
+				{Id: "b", Service: &pb.Service{Id: "twitter"}},
+				{Id: "c", Service: &pb.Service{Id: "blog"}},
+				{Id: "d", Service: &pb.Service{Id: "blog"}},
+				{Id: "private"},
+			},
+		}
+		limiter := NewLimiter(Rate{}, nil)
+		limiter.MinBackoff = time.Hour
+
+		var mu sync.Mutex
+		running, concurrent := 0, 0
+		run := func(ctx context.Context, job *pb.FeedJob) (int, error) {
+			mu.Lock()
+			running++
+			if running > concurrent {
+				concurrent = running
+			}
+			mu.Unlock()
+			defer func() {
+				mu.Lock()
+				running--
+				mu.Unlock()
+			}()
+
+			switch job.Id {
+			case "b":
+				return 0, &importer.StatusError{StatusCode: http.StatusTooManyRequests}
+			case "c":
+				return 0, fmt.Errorf("parse error")
+			case "private":
+				resp := &http.Response{StatusCode: http.StatusForbidden}
+				return 0, &ff.ErrorResponse{Response: resp, Message: "forbidden"}
+			}
+			time.Sleep(50 * time.Millisecond)
+			return 1, nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		pool := &Pool{
+			Client:  client,
+			Worker:  &pb.Worker{Id: "test"},
+			Workers: 3,
+			Limiter: limiter,
+			Run:     run,
+			Idle:    10 * time.Millisecond,
+			Grace:   time.Second,
+		}
+		served := make(chan struct{})
+		go func() {
+			pool.Serve(ctx)
+			close(served)
+		}()
+		for client.done() < 4 {
+			time.Sleep(10 * time.Millisecond)
+		}
+		cancel()
+		<-served
+
+		So(concurrent, ShouldBeGreaterThan, 1)
+		So(client.finished, ShouldContain, "a")
+		So(client.finished, ShouldContain, "d")
+		So(client.finished, ShouldContain, "private")
+		So(client.released, ShouldResemble, []string{"b"})
+		So(limiter.Bucket("twitter").take(time.Now()), ShouldBeGreaterThan, 10*time.Minute)
+		So(limiter.Bucket("blog").take(time.Now()), ShouldEqual, 0)
+	})
+
+	Convey("Given shutdown, release in-flight jobs after grace period", t, func() {
+		client := &fakeClient{jobs: []*pb.FeedJob{{Id: "slow"}, {Id: "fast"}}}
+		started := make(chan struct{}, 2)
+		run := func(ctx context.Context, job *pb.FeedJob) (int, error) {
+			started <- struct{}{}
+			if job.Id == "fast" {
+				time.Sleep(20 * time.Millisecond)
+				return 1, nil
+			}
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		pool := &Pool{
+			Client:  client,
+			Worker:  &pb.Worker{Id: "test"},
+			Workers: 2,
+			Run:     run,
+			Grace:   100 * time.Millisecond,
+		}
+		go func() {
+			<-started
+			<-started
+			cancel()
+		}()
+		pool.Serve(ctx)
+
+		So(client.finished, ShouldResemble, []string{"fast"})
+		So(client.released, ShouldResemble, []string{"slow"})
+	})
+}
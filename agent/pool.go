@@ -0,0 +1,198 @@
+// Package agent runs feed jobs of server concurrently, rate limited by
+// service.
+package agent
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/yinhm/friendfeed/importer"
+	pb "github.com/yinhm/friendfeed/proto"
+)
+
+// ServiceFriendFeed is the service of jobs archiving friendfeed feeds, which
+// have no job.Service.
+const ServiceFriendFeed = "friendfeed"
+
+const (
+	defaultIdle    = time.Second
+	defaultMaxIdle = time.Minute
+	releaseTimeout = 10 * time.Second
+)
+
+// RunFunc runs job, returns number of entries archived. ctx canceled when
+// grace period of shutdown is over.
+type RunFunc func(ctx context.Context, job *pb.FeedJob) (int, error)
+
+// Pool runs jobs of server by Workers goroutines.
+//
+// Jobs throttled by service, rate limited or server errors, pause the
+// service and are released back to the queue. Jobs failed permanently, eg:
+// feed private, are finished. Jobs failed otherwise are left running, to be
+// redone by server.
+type Pool struct {
+	Client  pb.ApiClient
+	Worker  *pb.Worker
+	Workers int
+	Limiter *Limiter
+	Run     RunFunc
+	// Grace is time in-flight jobs given to finish on shutdown, jobs not
+	// finished by then canceled and released.
+	Grace time.Duration
+	// Idle is wait before polling again when no job available, doubled up to
+	// MaxIdle while idle.
+	Idle    time.Duration
+	MaxIdle time.Duration
+}
+
+// Service returns service of job for rate limiting.
+func Service(job *pb.FeedJob) string {
+	if job.Service == nil || job.Service.Id == "" {
+		return ServiceFriendFeed
+	}
+	return job.Service.Id
+}
+
+// Serve runs jobs until ctx done, then waits in-flight jobs.
+func (p *Pool) Serve(ctx context.Context) {
+	if p.Limiter == nil {
+		p.Limiter = &Limiter{}
+	}
+	workers := p.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx, jobCtx)
+		}()
+	}
+	<-ctx.Done()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	timer := time.NewTimer(p.Grace)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		log.Printf("Grace period over, releasing in-flight jobs")
+		cancel()
+		<-done
+	}
+}
+
+// work polls jobs until ctx done, jobs run by jobCtx.
+func (p *Pool) work(ctx, jobCtx context.Context) {
+	idle := time.Duration(0)
+	for ctx.Err() == nil {
+		job, err := p.Client.GetFeedJob(ctx, p.Worker)
+		if err != nil {
+			idle = p.nextIdle(idle)
+			if !sleep(ctx, idle) {
+				return
+			}
+			continue
+		}
+		idle = 0
+		p.process(jobCtx, job)
+	}
+}
+
+func (p *Pool) nextIdle(idle time.Duration) time.Duration {
+	min, max := p.Idle, p.MaxIdle
+	if min <= 0 {
+		min = defaultIdle
+	}
+	if max < min {
+		max = defaultMaxIdle
+		if max < min {
+			max = min
+		}
+	}
+	if idle < min {
+		return min
+	}
+	if idle *= 2; idle > max {
+		return max
+	}
+	return idle
+}
+
+func (p *Pool) process(ctx context.Context, job *pb.FeedJob) {
+	service := Service(job)
+	if err := p.Limiter.Wait(ctx, service); err != nil {
+		p.release(job)
+		return
+	}
+
+	log.Printf("Start fetching entries for: %s", job.Id)
+	total, err := p.Run(ctx, job)
+	if err == nil {
+		p.Limiter.Succeed(service)
+		if p.finish(job) {
+			log.Printf("Job done for %s, %d entries", job.Id, total)
+		}
+		return
+	}
+	if importer.Permanent(err) {
+		if p.finish(job) {
+			log.Printf("Job dropped for %s: %v", job.Id, err)
+		}
+		return
+	}
+	if ctx.Err() != nil {
+		p.release(job)
+		return
+	}
+	if wait, ok := importer.Throttled(err); ok {
+		pause := p.Limiter.Fail(service, wait)
+		log.Printf("Service %s throttled, paused %v: %v", service, pause, err)
+		p.release(job)
+		return
+	}
+	log.Printf("Archive failed: %v", err)
+}
+
+func (p *Pool) finish(job *pb.FeedJob) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+	if _, err := p.Client.FinishJob(ctx, job); err != nil {
+		log.Printf("Finish job %s failed: %v", job.Id, err)
+		return false
+	}
+	return true
+}
+
+// release requeues job, resumed from its last checkpoint.
+func (p *Pool) release(job *pb.FeedJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+	if _, err := p.Client.ReleaseJob(ctx, job); err != nil {
+		log.Printf("Release job %s failed: %v", job.Id, err)
+	}
+}
+
+// sleep returns false if ctx done before d.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
@@ -0,0 +1,189 @@
+package agent
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMinBackoff = 5 * time.Second
+	defaultMaxBackoff = 30 * time.Minute
+)
+
+// Rate is requests per second sustained and requests at most in a burst, zero
+// Limit for no limit.
+type Rate struct {
+	Limit float64
+	Burst int
+}
+
+// Bucket is a token bucket of a service, paused by exponential backoff after
+// consecutive failures.
+type Bucket struct {
+	mu         sync.Mutex
+	rate       Rate
+	tokens     float64
+	last       time.Time
+	paused     time.Time
+	failures   int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+func NewBucket(rate Rate) *Bucket {
+	if rate.Burst < 1 {
+		rate.Burst = 1
+	}
+	return &Bucket{
+		rate:       rate,
+		tokens:     float64(rate.Burst),
+		last:       time.Now(),
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+	}
+}
+
+// Wait blocks until a token taken or ctx done.
+func (b *Bucket) Wait(ctx context.Context) error {
+	for {
+		d := b.take(time.Now())
+		if d <= 0 {
+			return nil
+		}
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// take takes a token, returns wait before trying again if none available.
+func (b *Bucket) take(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if now.Before(b.paused) {
+		return b.paused.Sub(now)
+	}
+	if b.rate.Limit <= 0 {
+		return 0
+	}
+	if now.After(b.last) {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate.Limit
+		if burst := float64(b.rate.Burst); b.tokens > burst {
+			b.tokens = burst
+		}
+		b.last = now
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate.Limit * float64(time.Second))
+}
+
+// Fail pauses bucket after a throttled request, by backoff doubled on every
+// consecutive failure or retryAfter suggested by service if longer. Returns
+// the pause.
+func (b *Bucket) Fail(retryAfter time.Duration) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	d := b.backoff()
+	if retryAfter > d {
+		d = retryAfter
+	}
+	now := time.Now()
+	if until := now.Add(d); until.After(b.paused) {
+		b.paused = until
+	}
+	b.tokens = 0
+	b.last = now
+	return d
+}
+
+// Succeed resets backoff.
+func (b *Bucket) Succeed() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+}
+
+// backoff is min backoff doubled on every failure, up to max backoff, with
+// jitter so that workers throttled together do not retry together.
+func (b *Bucket) backoff() time.Duration {
+	d := b.maxBackoff
+	if shift := uint(b.failures - 1); shift < 32 && b.minBackoff<<shift < d {
+		d = b.minBackoff << shift
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// Limiter holds buckets of services, buckets created on first use by rate of
+// the service or Default.
+type Limiter struct {
+	Default Rate
+	// MinBackoff and MaxBackoff bound the pause after failures, defaults used
+	// if zero.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	mu      sync.Mutex
+	rates   map[string]Rate
+	buckets map[string]*Bucket
+}
+
+func NewLimiter(def Rate, rates map[string]Rate) *Limiter {
+	return &Limiter{
+		Default: def,
+		rates:   rates,
+		buckets: make(map[string]*Bucket),
+	}
+}
+
+// Bucket returns bucket of service.
+func (l *Limiter) Bucket(service string) *Bucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, ok := l.buckets[service]; ok {
+		return b
+	}
+	rate, ok := l.rates[service]
+	if !ok {
+		rate = l.Default
+	}
+	b := NewBucket(rate)
+	if l.buckets == nil {
+		l.buckets = make(map[string]*Bucket)
+	}
+	if l.MinBackoff > 0 {
+		b.minBackoff = l.MinBackoff
+	}
+	if l.MaxBackoff > 0 {
+		b.maxBackoff = l.MaxBackoff
+	}
+	if b.minBackoff > b.maxBackoff {
+		b.minBackoff = b.maxBackoff
+	}
+	l.buckets[service] = b
+	return b
+}
+
+func (l *Limiter) Wait(ctx context.Context, service string) error {
+	return l.Bucket(service).Wait(ctx)
+}
+
+func (l *Limiter) Fail(service string, retryAfter time.Duration) time.Duration {
+	return l.Bucket(service).Fail(retryAfter)
+}
+
+func (l *Limiter) Succeed(service string) {
+	l.Bucket(service).Succeed()
+}
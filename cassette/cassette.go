@@ -0,0 +1,284 @@
+// Package cassette records http interactions into files and replays them, so
+// that clients, ff.Client or media fetcher, are tested against responses
+// captured from live servers without network.
+//
+// Capture fixtures from our own v2 compatible server:
+//
+//	rec, _ := cassette.New("testdata/cassettes/yinhm", cassette.Record)
+//	client := ff.NewClient(rec.Client(), "yinhm", remoteKey)
+//	client.BaseURL, _ = url.Parse("http://localhost:8080/v2")
+//	// ... requests
+//	rec.Stop()
+//
+// Requests are matched by method, url and body. Credentials are never
+// saved, request headers are not recorded.
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode of recorder.
+type Mode int
+
+const (
+	// Replay serves recorded responses, requests not recorded fail.
+	Replay Mode = iota
+	// Record sends requests and saves interactions, cassette overwritten.
+	Record
+	// ReplayOrRecord serves recorded responses, records requests not
+	// recorded.
+	ReplayOrRecord
+)
+
+// ModeFromEnv returns mode of env CASSETTE: "record", "update" for
+// ReplayOrRecord, Replay otherwise.
+func ModeFromEnv() Mode {
+	switch os.Getenv("CASSETTE") {
+	case "record":
+		return Record
+	case "update":
+		return ReplayOrRecord
+	}
+	return Replay
+}
+
+// Request is the recorded request.
+type Request struct {
+	Method string `json:"method"`
+	Url    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is the recorded response, Body is base64 encoded if binary.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	Base64     bool        `json:"base64,omitempty"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+
+	replayed bool
+}
+
+// Cassette is interactions of a file.
+type Cassette struct {
+	Path         string         `json:"-"`
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Load loads cassette of path, ".json" appended if no extension.
+func Load(path string) (*Cassette, error) {
+	path = filename(path)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Cassette{Path: path}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("cassette: %s: %v", path, err)
+	}
+	return c, nil
+}
+
+// Save writes cassette into its path, directories created if not exist.
+func (c *Cassette) Save() error {
+	// bodies kept readable in diffs, html not escaped
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.Path, buf.Bytes(), 0644)
+}
+
+// match returns the first interaction of req not replayed, or the last
+// replayed if all replayed, so that polling the same url works.
+func (c *Cassette) match(r *Request) *Interaction {
+	var last *Interaction
+	for _, i := range c.Interactions {
+		if i.Request.Method != r.Method || i.Request.Url != r.Url || i.Request.Body != r.Body {
+			continue
+		}
+		if !i.replayed {
+			return i
+		}
+		last = i
+	}
+	return last
+}
+
+func filename(path string) string {
+	if filepath.Ext(path) == "" {
+		return path + ".json"
+	}
+	return path
+}
+
+// Recorder is a http.RoundTripper recording into or replaying from cassette.
+type Recorder struct {
+	// Transport sends requests when recording, http.DefaultTransport if nil.
+	Transport http.RoundTripper
+
+	mu       sync.Mutex
+	mode     Mode
+	cassette *Cassette
+	changed  bool
+}
+
+// New returns recorder of cassette path. Cassette must exist in Replay
+// mode.
+func New(path string, mode Mode) (*Recorder, error) {
+	c, err := Load(path)
+	switch {
+	case err == nil && mode == Record:
+		c.Interactions = nil
+	case os.IsNotExist(err) && mode != Replay:
+		c = &Cassette{Path: filename(path)}
+	case err != nil:
+		return nil, err
+	}
+	return &Recorder{mode: mode, cassette: c}, nil
+}
+
+// Client returns http client of recorder.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Cassette returns cassette of recorder.
+func (r *Recorder) Cassette() *Cassette {
+	return r.cassette
+}
+
+// Stop saves cassette if interactions recorded.
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.changed {
+		return nil
+	}
+	r.changed = false
+	return r.cassette.Save()
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := newRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode != Record {
+		r.mu.Lock()
+		i := r.cassette.match(recorded)
+		if i != nil {
+			i.replayed = true
+		}
+		r.mu.Unlock()
+		if i != nil {
+			return i.Response.response(req)
+		}
+		if r.mode == Replay {
+			return nil, fmt.Errorf("cassette: %s %s not recorded in %s", req.Method, req.URL, r.cassette.Path)
+		}
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	i := &Interaction{
+		Request:  *recorded,
+		Response: newResponse(resp, body),
+		replayed: true,
+	}
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, i)
+	r.changed = true
+	r.mu.Unlock()
+	return resp, nil
+}
+
+func newRequest(req *http.Request) (*Request, error) {
+	r := &Request{Method: req.Method, Url: req.URL.String()}
+	if req.Body == nil || req.Body == http.NoBody {
+		return r, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	r.Body = string(body)
+	return r, nil
+}
+
+func newResponse(resp *http.Response, body []byte) Response {
+	header := resp.Header.Clone()
+	// cookies of live servers not saved
+	header.Del("Set-Cookie")
+	header.Del("Date")
+	r := Response{StatusCode: resp.StatusCode, Header: header}
+	if utf8.Valid(body) {
+		r.Body = string(body)
+	} else {
+		r.Body = base64.StdEncoding.EncodeToString(body)
+		r.Base64 = true
+	}
+	return r
+}
+
+func (r *Response) response(req *http.Request) (*http.Response, error) {
+	body := []byte(r.Body)
+	if r.Base64 {
+		var err error
+		if body, err = base64.StdEncoding.DecodeString(r.Body); err != nil {
+			return nil, err
+		}
+	}
+	header := r.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,113 @@
+package cassette
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRecorder(t *testing.T) {
+	Convey("Given live server, record interactions then replay without it", t, func() {
+		hits := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits++
+			r.ParseForm()
+			switch r.URL.Path {
+			case "/feed":
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Set-Cookie", "session=secret")
+				fmt.Fprintf(w, `{"id": "yinhm", "hits": %d}`, hits)
+			case "/like":
+				fmt.Fprintf(w, `{"entry": %q}`, r.PostForm.Get("entry"))
+			case "/picture":
+				w.Write([]byte{0xff, 0xd8, 0xff, 0xe0})
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+
+		dir, err := ioutil.TempDir("", "cassette")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "feed")
+
+		_, err = New(path, Replay)
+		So(os.IsNotExist(err), ShouldBeTrue)
+
+		get := func(client *http.Client, path string) (int, string) {
+			resp, err := client.Get(ts.URL + path)
+			if err != nil {
+				return 0, err.Error()
+			}
+			defer resp.Body.Close()
+			body, _ := ioutil.ReadAll(resp.Body)
+			return resp.StatusCode, string(body)
+		}
+		like := func(client *http.Client, entry string) string {
+			resp, err := client.PostForm(ts.URL+"/like", url.Values{"entry": {entry}})
+			if err != nil {
+				return err.Error()
+			}
+			defer resp.Body.Close()
+			body, _ := ioutil.ReadAll(resp.Body)
+			return string(body)
+		}
+
+		rec, err := New(path, Record)
+		So(err, ShouldBeNil)
+		client := rec.Client()
+		_, body := get(client, "/feed")
+		So(body, ShouldEqual, `{"id": "yinhm", "hits": 1}`)
+		get(client, "/feed")
+		So(like(client, "e/1"), ShouldEqual, `{"entry": "e/1"}`)
+		like(client, "e/2")
+		code, _ := get(client, "/missing")
+		So(code, ShouldEqual, http.StatusNotFound)
+		get(client, "/picture")
+		So(rec.Stop(), ShouldBeNil)
+		So(len(rec.Cassette().Interactions), ShouldEqual, 6)
+
+		data, _ := ioutil.ReadFile(path + ".json")
+		So(string(data), ShouldNotContainSubstring, "secret")
+		So(string(data), ShouldContainSubstring, `"base64": true`)
+
+		ts.Close()
+		rec, err = New(path, Replay)
+		So(err, ShouldBeNil)
+		client = rec.Client()
+
+		// same url replayed in order, the last repeated
+		_, body = get(client, "/feed")
+		So(body, ShouldEqual, `{"id": "yinhm", "hits": 1}`)
+		_, body = get(client, "/feed")
+		So(body, ShouldEqual, `{"id": "yinhm", "hits": 2}`)
+		_, body = get(client, "/feed")
+		So(body, ShouldEqual, `{"id": "yinhm", "hits": 2}`)
+
+		// matched by body
+		So(like(client, "e/2"), ShouldEqual, `{"entry": "e/2"}`)
+		So(like(client, "e/3"), ShouldContainSubstring, "not recorded")
+
+		code, _ = get(client, "/missing")
+		So(code, ShouldEqual, http.StatusNotFound)
+		_, body = get(client, "/picture")
+		So(body, ShouldEqual, "\xff\xd8\xff\xe0")
+
+		// recorded requests replayed, new ones recorded
+		rec, err = New(path, ReplayOrRecord)
+		So(err, ShouldBeNil)
+		_, body = get(rec.Client(), "/feed")
+		So(body, ShouldEqual, `{"id": "yinhm", "hits": 1}`)
+		So(rec.Stop(), ShouldBeNil)
+		// sent to the closed server
+		code, _ = get(rec.Client(), "/new")
+		So(code, ShouldEqual, 0)
+	})
+}
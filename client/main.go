@@ -7,6 +7,13 @@
 //
 // Mark deletion
 // go run main.go --cmd="MarkDelete" --arg1="foobar"
+//
+// Import twitter archive or mastodon outbox.json
+// go run main.go -u=foobar -archive=twitter-2022-11-01.zip
+// go run main.go -u=foobar -outbox=outbox.json
+//
+// Run jobs by 8 workers, in-flight jobs given 1 minute to finish on shutdown
+// go run main.go -workers=8 -grace=1m
 package main
 
 import (
@@ -15,16 +22,17 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"flag"
-	"fmt"
 	"io/ioutil"
 	"log"
-	"net/url"
-	"strings"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/ChimeraCoder/anaconda"
-	ttext "github.com/cupcake/text-entities-go"
-	uuid "github.com/satori/go.uuid"
+	"github.com/yinhm/friendfeed/agent"
+	"github.com/yinhm/friendfeed/importer"
+	"github.com/yinhm/friendfeed/media"
 	pb "github.com/yinhm/friendfeed/proto"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
@@ -37,6 +45,19 @@ var config struct {
 	command  string
 	arg1     string
 	debug    bool
+	archive  string
+	outbox   string
+	workers  int
+	grace    time.Duration
+}
+
+// serviceRates are request rates of services, shared by all workers of the
+// agent, throttled responses back off further.
+var serviceRates = map[string]agent.Rate{
+	agent.ServiceFriendFeed:    {Limit: 1, Burst: 2},
+	importer.TwitterServiceId:  {Limit: 0.5, Burst: 2},
+	importer.MastodonServiceId: {Limit: 0.5, Burst: 2},
+	importer.FeedServiceId:     {Limit: 2, Burst: 4},
 }
 
 type TwitterConfig struct {
@@ -51,6 +72,10 @@ func init() {
 	flag.StringVar(&config.arg1, "arg1", "", "pass argument to command")
 	flag.StringVar(&config.username, "u", "", "debug user feed")
 	flag.BoolVar(&config.debug, "d", false, "Enable debug info.")
+	flag.StringVar(&config.archive, "archive", "", "import twitter archive zip into feed of -u")
+	flag.StringVar(&config.outbox, "outbox", "", "import activitypub outbox.json into feed of -u")
+	flag.IntVar(&config.workers, "workers", 4, "jobs run concurrently")
+	flag.DurationVar(&config.grace, "grace", 30*time.Second, "time in-flight jobs given to finish on shutdown")
 }
 
 func NewConfigFromJSON(filename string) (*TwitterConfig, error) {
@@ -76,8 +101,9 @@ func randhash() string {
 }
 
 type FeedAgent struct {
-	client pb.ApiClient
-	worker *pb.Worker
+	client  pb.ApiClient
+	worker  *pb.Worker
+	limiter *agent.Limiter
 }
 
 func NewFeedAgent(conn *grpc.ClientConn) *FeedAgent {
@@ -86,8 +112,9 @@ func NewFeedAgent(conn *grpc.ClientConn) *FeedAgent {
 		Id: randhash(),
 	}
 	return &FeedAgent{
-		client: c,
-		worker: worker,
+		client:  c,
+		worker:  worker,
+		limiter: agent.NewLimiter(agent.Rate{Limit: 1, Burst: 1}, serviceRates),
 	}
 }
 
@@ -110,6 +137,19 @@ func (fa *FeedAgent) Start() {
 		return
 	}
 
+	if config.archive != "" && config.username != "" {
+		if err := fa.ImportTwitterArchive(config.archive, config.username); err != nil {
+			log.Fatalf("Import archive failed: %s", err)
+		}
+		return
+	}
+	if config.outbox != "" && config.username != "" {
+		if err := fa.ImportOutbox(config.outbox, config.username); err != nil {
+			log.Fatalf("Import outbox failed: %s", err)
+		}
+		return
+	}
+
 	log.Print("start processing...")
 
 	// lazy init
@@ -120,21 +160,28 @@ func (fa *FeedAgent) Start() {
 	anaconda.SetConsumerKey(tc.ApiKey)
 	anaconda.SetConsumerSecret(tc.ApiSecret)
 
-	// run feed mirror job forever
-	for {
-		job, err := fa.newJob()
-		if err != nil {
-			log.Printf("Get job failed: %v", err)
-			time.Sleep(5 * time.Second)
-			continue
-		}
-
-		if err := fa.process(job); err != nil {
-			log.Printf("Archive failed: %v", err)
-			time.Sleep(1 * time.Second)
-			continue
-		}
+	// run feed mirror jobs until interrupted
+	ctx, cancel := context.WithCancel(context.Background())
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-sigs
+		log.Printf("%v received, shutting down...", sig)
+		cancel()
+	}()
+
+	pool := &agent.Pool{
+		Client:  fa.client,
+		Worker:  fa.worker,
+		Workers: config.workers,
+		Limiter: fa.limiter,
+		Run:     fa.fetchService,
+		Grace:   config.grace,
+		Idle:    5 * time.Second,
+		MaxIdle: time.Minute,
 	}
+	pool.Serve(ctx)
+	log.Print("stopped")
 }
 
 func (fa *FeedAgent) Debug(name string) error {
@@ -156,130 +203,123 @@ func (fa *FeedAgent) Debug(name string) error {
 	return nil
 }
 
-func (fa *FeedAgent) newJob() (*pb.FeedJob, error) {
-	feedjob, err := fa.client.GetFeedJob(context.Background(), fa.worker)
+// ImportTwitterArchive imports tweets of archive into feed of name, media of
+// archive uploaded to storage configured in config file.
+func (fa *FeedAgent) ImportTwitterArchive(filename, name string) error {
+	archive, err := importer.OpenTwitterArchive(filename)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return feedjob, nil
+	defer archive.Close()
+	log.Printf("archive of @%s: %d tweets", archive.Username, len(archive.Tweets))
+
+	mc, err := media.NewConfigFromJSON(config.file)
+	if err != nil {
+		return err
+	}
+	fs, err := media.NewStorage(mc)
+	if err != nil {
+		return err
+	}
+	return fa.importInto(name, importer.NewTwitterArchiveImporter(archive, fs))
 }
 
-func (fa *FeedAgent) process(job *pb.FeedJob) error {
-	log.Printf("Start fetching entries for: %s", job.Id)
-	total, err := fa.fetchService(job)
+// ImportOutbox imports public notes of exported activitypub outbox into feed
+// of name.
+func (fa *FeedAgent) ImportOutbox(filename, name string) error {
+	outbox, err := importer.OpenOutbox(filename)
 	if err != nil {
 		return err
 	}
+	return fa.importInto(name, importer.NewOutboxImporter(outbox))
+}
 
-	job, err = fa.client.FinishJob(context.Background(), job)
+// importInto archives entries of imp into feed of name.
+func (fa *FeedAgent) importInto(name string, imp importer.Importer) error {
+	ctx := context.Background()
+	profile, err := fa.client.FetchProfile(ctx, &pb.ProfileRequest{Id: name})
 	if err != nil {
 		return err
 	}
 
-	log.Printf("Job done for %s, %d entries", job.Id, total)
+	stream, err := fa.client.ArchiveFeed(ctx)
+	if err != nil {
+		return err
+	}
+	job := &pb.FeedJob{
+		Uuid:    profile.Uuid,
+		Id:      profile.Id,
+		Profile: profile,
+	}
+	n, err := imp.Import(ctx, job, stream.Send)
+	if err != nil {
+		stream.CloseAndRecv()
+		return err
+	}
+	summary, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	log.Printf("Imported into %s, %d entries, %d archived", name, n, summary.EntryCount)
 	return nil
 }
 
-func (fa *FeedAgent) fetchService(job *pb.FeedJob) (int, error) {
-	stream, err := fa.client.ArchiveFeed(context.Background())
-	defer stream.CloseAndRecv()
+func (fa *FeedAgent) fetchService(ctx context.Context, job *pb.FeedJob) (int, error) {
+	if job.Service == nil {
+		return fa.archiveFriendFeed(ctx, job)
+	}
+	stream, err := fa.client.ArchiveFeed(ctx)
 	if err != nil {
 		return 0, err
 	}
+	defer stream.CloseAndRecv()
+	return importer.Import(ctx, job, stream.Send)
+}
 
-	updated := time.Unix(job.Service.Updated, 0)
-	authinfo := job.Service.Oauth
-	if authinfo == nil {
-		return 0, fmt.Errorf("skip job: no authinfo")
-	}
-	api := anaconda.NewTwitterApi(authinfo.AccessToken, authinfo.AccessTokenSecret)
-	defer api.Close()
-
-	v := url.Values{}
-	v.Set("screen_name", authinfo.NickName) // goth user.NickName == screen_name
-	tweets, _ := api.GetUserTimeline(v)
-
-	n := 0
-	for i := len(tweets) - 1; i >= 0; i-- {
-		tweet := tweets[i]
-
-		// skip reply status
-		if tweet.InReplyToStatusID != 0 {
-			continue
-		}
-
-		url := "https://twitter.com/" + tweet.User.ScreenName + "/status/" + tweet.IdStr
-		// deterministic uuid or feed will be polluted
-		uuid1 := uuid.NewV5(uuid.NamespaceURL, url)
-		tt, err := tweet.CreatedAtTime()
-		if err != nil || tt.Before(updated) {
-			continue
-		}
-
-		from := &pb.Feed{
-			Id:   job.Profile.Id,
-			Name: job.Profile.Name,
-			Type: job.Profile.Type,
-		}
-
-		var thumbnails []*pb.Thumbnail
-		for _, media := range tweet.Entities.Media {
-			if media.Type != "photo" {
-				continue
-			}
-
-			url := ""
-			if media.Media_url_https != "" {
-				url = media.Media_url_https
-			} else {
-				url = media.Media_url
-			}
-			thumb := &pb.Thumbnail{
-				Url:    url,
-				Link:   media.Expanded_url,
-				Width:  int32(media.Sizes.Small.W),
-				Height: int32(media.Sizes.Small.H),
-			}
-			thumbnails = append(thumbnails, thumb)
-		}
+// archiveStream is the client stream of ArchiveFeed or ForceArchiveFeed.
+type archiveStream interface {
+	Send(*pb.Entry) error
+	CloseAndRecv() (*pb.FeedSummary, error)
+}
 
-		body := tweet.Text
-		tags := ttext.ExtractHashtags(body)
-		for _, tag := range tags {
-			new := fmt.Sprintf("<a href=\"https://twitter.com/hashtag/%s\">%s</a>", tag, tag)
-			body = strings.Replace(body, tag, new, -1)
-		}
-		urls := ttext.ExtractURLs(tweet.Text)
-		for _, url := range urls {
-			new := fmt.Sprintf("<a href=\"%s\">%s</a>", url, url)
-			body = strings.Replace(body, url, new, -1)
-		}
+func (fa *FeedAgent) openArchiveStream(ctx context.Context, force bool) (archiveStream, error) {
+	if force {
+		return fa.client.ForceArchiveFeed(ctx)
+	}
+	return fa.client.ArchiveFeed(ctx)
+}
 
-		entry := &pb.Entry{
-			Id:      uuid1.String(),
-			Url:     url,
-			Date:    tt.Format(time.RFC3339),
-			Body:    body,
-			RawBody: tweet.Text,
-			RawLink: url,
-			From:    from,
-			// To:         []*pb.Feed{from},
-			Thumbnails: thumbnails,
-			Via: &pb.Via{
-				Name: "Twitter",
-				Url:  url,
-			},
-			ProfileUuid: job.Profile.Uuid,
+// archiveFriendFeed archives friendfeed feed of job page by page. Entries of
+// a page are flushed to server before start of job checkpointed, so that the
+// job resumes from the last page archived if interrupted.
+func (fa *FeedAgent) archiveFriendFeed(ctx context.Context, job *pb.FeedJob) (int, error) {
+	stream, err := fa.openArchiveStream(ctx, job.ForceUpdate)
+	if err != nil {
+		return 0, err
+	}
+	send := func(entry *pb.Entry) error {
+		return stream.Send(entry)
+	}
+	checkpoint := func(job *pb.FeedJob) error {
+		if _, err := stream.CloseAndRecv(); err != nil {
+			return err
 		}
-
-		if err := stream.Send(entry); err != nil {
-			log.Printf("%v.Send(%v) = %v", stream, entry, err)
-			return n, err
+		if _, err := fa.client.UpdateJob(ctx, job); err != nil {
+			return err
 		}
+		stream, err = fa.openArchiveStream(ctx, job.ForceUpdate)
+		return err
+	}
 
-		n++
+	archiver := importer.NewFriendFeedArchiver(checkpoint)
+	archiver.Wait = func(ctx context.Context) error {
+		return fa.limiter.Wait(ctx, agent.ServiceFriendFeed)
+	}
+	n, err := archiver.Import(ctx, job, send)
+	if stream != nil {
+		stream.CloseAndRecv()
 	}
-	return n, nil
+	return n, err
 }
 
 func main() {
@@ -291,6 +331,6 @@ func main() {
 	}
 	defer conn.Close()
 
-	agent := NewFeedAgent(conn)
-	agent.Start()
+	fa := NewFeedAgent(conn)
+	fa.Start()
 }
@@ -3,14 +3,20 @@ package ff
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/go-querystring/query"
 	pb "github.com/yinhm/friendfeed/proto"
@@ -21,6 +27,22 @@ const (
 	apiV1URL  = "http://friendfeed.com"
 	apiV2URL  = "http://friendfeed-api.com/v2"
 	userAgent = "lastff/" + version
+
+	defaultMaxRetries = 3
+	defaultMinBackoff = time.Second
+	defaultMaxBackoff = 30 * time.Second
+)
+
+var (
+	// ErrNotFound is returned when feed or entry does not exist.
+	ErrNotFound = errors.New("ff: not found")
+	// ErrPrivate is returned when feed is not visible to the authed user.
+	ErrPrivate = errors.New("ff: private feed")
+	// ErrAuth is returned when username or remote key is wrong.
+	ErrAuth = errors.New("ff: unauthorized")
+	// ErrRateLimited is returned when requests exceed the limit of api, to be
+	// retried after RetryAfter of the ErrorResponse.
+	ErrRateLimited = errors.New("ff: rate limit exceeded")
 )
 
 // A Client manages communication with the GitHub API.
@@ -30,6 +52,17 @@ type Client struct {
 	BaseURL   *url.URL
 	UserAgent string
 
+	// MaxRetries is retries of a request failed by network error, rate limit
+	// or server error, zero for no retry. Network and server errors of
+	// requests other than GET not retried.
+	MaxRetries int
+	// MinBackoff is wait before the first retry, doubled on every retry up to
+	// MaxBackoff, jittered. Rate limited longer than MaxBackoff not retried.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// Logger logs requests and retries if not nil.
+	Logger *log.Logger
+
 	username string
 	authKey  string
 }
@@ -42,11 +75,14 @@ func NewClient(httpClient *http.Client, username, authKey string) *Client {
 	baseURL, _ := url.Parse(apiV2URL)
 
 	return &Client{
-		client:    httpClient,
-		BaseURL:   baseURL,
-		UserAgent: userAgent,
-		username:  username,
-		authKey:   authKey,
+		client:     httpClient,
+		BaseURL:    baseURL,
+		UserAgent:  userAgent,
+		MaxRetries: defaultMaxRetries,
+		MinBackoff: defaultMinBackoff,
+		MaxBackoff: defaultMaxBackoff,
+		username:   username,
+		authKey:    authKey,
 	}
 }
 
@@ -57,15 +93,22 @@ func NewV1Client(httpClient *http.Client, username, authKey string) *Client {
 	baseURL, _ := url.Parse(apiV1URL)
 
 	return &Client{
-		client:    httpClient,
-		BaseURL:   baseURL,
-		UserAgent: userAgent,
-		username:  username,
-		authKey:   authKey,
+		client:     httpClient,
+		BaseURL:    baseURL,
+		UserAgent:  userAgent,
+		MaxRetries: defaultMaxRetries,
+		MinBackoff: defaultMinBackoff,
+		MaxBackoff: defaultMaxBackoff,
+		username:   username,
+		authKey:    authKey,
 	}
 }
 
 func (c *Client) Feed(feedId string, opt *FeedOptions) (*pb.Feed, *http.Response, error) {
+	return c.FeedContext(context.Background(), feedId, opt)
+}
+
+func (c *Client) FeedContext(ctx context.Context, feedId string, opt *FeedOptions) (*pb.Feed, *http.Response, error) {
 	path := fmt.Sprintf("/feed/%v", feedId)
 	path, err := addOptions(path, opt)
 	if err != nil {
@@ -73,7 +116,7 @@ func (c *Client) Feed(feedId string, opt *FeedOptions) (*pb.Feed, *http.Response
 	}
 
 	feed := new(pb.Feed)
-	resp, err := c.fetch(path, feed)
+	resp, err := c.fetch(ctx, path, feed)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -82,9 +125,13 @@ func (c *Client) Feed(feedId string, opt *FeedOptions) (*pb.Feed, *http.Response
 }
 
 func (c *Client) Feedinfo(feedId string) (*pb.Feedinfo, *http.Response, error) {
+	return c.FeedinfoContext(context.Background(), feedId)
+}
+
+func (c *Client) FeedinfoContext(ctx context.Context, feedId string) (*pb.Feedinfo, *http.Response, error) {
 	path := fmt.Sprintf("/feedinfo/%v", feedId)
 	info := new(pb.Feedinfo)
-	resp, err := c.fetch(path, info)
+	resp, err := c.fetch(ctx, path, info)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -93,6 +140,10 @@ func (c *Client) Feedinfo(feedId string) (*pb.Feedinfo, *http.Response, error) {
 }
 
 func (c *Client) Entry(eid string, opt *FeedOptions) (*pb.Entry, *http.Response, error) {
+	return c.EntryContext(context.Background(), eid, opt)
+}
+
+func (c *Client) EntryContext(ctx context.Context, eid string, opt *FeedOptions) (*pb.Entry, *http.Response, error) {
 	path := fmt.Sprintf("/entry/%v", eid)
 	path, err := addOptions(path, opt)
 	if err != nil {
@@ -100,7 +151,7 @@ func (c *Client) Entry(eid string, opt *FeedOptions) (*pb.Entry, *http.Response,
 	}
 
 	entry := new(pb.Entry)
-	resp, err := c.fetch(path, entry)
+	resp, err := c.fetch(ctx, path, entry)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -109,12 +160,12 @@ func (c *Client) Entry(eid string, opt *FeedOptions) (*pb.Entry, *http.Response,
 }
 
 // Restrict to GET method. No POST Call.
-func (c *Client) fetch(path string, v interface{}) (*http.Response, error) {
+func (c *Client) fetch(ctx context.Context, path string, v interface{}) (*http.Response, error) {
 	req, err := c.NewRequest("GET", path, nil)
 	if err != nil {
 		return nil, err
 	}
-	return c.Do(req, v)
+	return c.Do(req.WithContext(ctx), v)
 }
 
 // v1 api
@@ -123,12 +174,16 @@ func (c *Client) fetch(path string, v interface{}) (*http.Response, error) {
 // eg:
 // http://friendfeed.com/api/user/bret/profile
 func (c *Client) V1Profile(feedId string, feedType string) (*pb.V1Profile, *http.Response, error) {
+	return c.V1ProfileContext(context.Background(), feedId, feedType)
+}
+
+func (c *Client) V1ProfileContext(ctx context.Context, feedId string, feedType string) (*pb.V1Profile, *http.Response, error) {
 	path := fmt.Sprintf("/api/user/%s/profile", feedId)
 	if feedType == "group" {
 		path = fmt.Sprintf("/api/room/%s/profile", feedId)
 	}
 	profile := new(pb.V1Profile)
-	resp, err := c.fetch(path, profile)
+	resp, err := c.fetch(ctx, path, profile)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -148,7 +203,6 @@ func (c *Client) NewRequest(method, path string, body interface{}) (*http.Reques
 	}
 
 	u := c.BaseURL.String() + path
-	log.Printf("request: %s", u)
 
 	var buf io.ReadWriter
 	if body != nil {
@@ -174,7 +228,85 @@ func (c *Client) NewRequest(method, path string, body interface{}) (*http.Reques
 // error if an API error has occurred.  If v implements the io.Writer
 // interface, the raw response body will be written to v, without attempting to
 // first decode it.
+//
+// Failed requests retried as configured by MaxRetries, canceled by context of
+// req.
 func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		c.logf("request: %s %s", req.Method, req.URL)
+		resp, err := c.do(req, v)
+		if err == nil || attempt >= c.MaxRetries || ctx.Err() != nil {
+			return resp, err
+		}
+		wait, ok := c.retryWait(req, resp, err, attempt)
+		if !ok {
+			return resp, err
+		}
+		c.logf("retry %s in %v: %v", req.URL, wait, err)
+
+		next := req.Clone(ctx)
+		if req.GetBody != nil {
+			if next.Body, err = req.GetBody(); err != nil {
+				return resp, err
+			}
+		}
+		req = next
+
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return resp, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// retryWait returns wait before retrying req failed by err, false if err not
+// retryable.
+func (c *Client) retryWait(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	wait := c.backoff(attempt)
+	er, ok := err.(*ErrorResponse)
+	switch {
+	case !ok:
+		// network error if no response, otherwise response not decoded
+		return wait, resp == nil && req.Method == "GET"
+	case errors.Is(er, ErrRateLimited):
+		if er.RetryAfter > c.MaxBackoff {
+			return 0, false
+		}
+		if er.RetryAfter > wait {
+			wait = er.RetryAfter
+		}
+		return wait, true
+	case er.Temporary():
+		return wait, req.Method == "GET"
+	}
+	return 0, false
+}
+
+// backoff is MinBackoff doubled on every attempt up to MaxBackoff, jittered
+// between half and full.
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.MaxBackoff
+	if attempt < 32 && c.MinBackoff<<uint(attempt) < d {
+		d = c.MinBackoff << uint(attempt)
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+func (c *Client) logf(format string, v ...interface{}) {
+	if c.Logger != nil {
+		c.Logger.Printf(format, v...)
+	}
+}
+
+// do sends req once.
+func (c *Client) do(req *http.Request, v interface{}) (*http.Response, error) {
 	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
@@ -205,6 +337,8 @@ An ErrorResponse reports one or more errors caused by an API request.
 type ErrorResponse struct {
 	Response *http.Response // HTTP response that caused this error
 	Message  string         `json:"errorCode"` // error message
+	// RetryAfter is wait suggested by Retry-After header, zero if not given
+	RetryAfter time.Duration `json:"-"`
 }
 
 func (r *ErrorResponse) Error() string {
@@ -213,6 +347,35 @@ func (r *ErrorResponse) Error() string {
 		r.Response.StatusCode, r.Message)
 }
 
+// Is reports whether r is one of ErrNotFound, ErrPrivate, ErrAuth and
+// ErrRateLimited, by status code or error code of api.
+func (r *ErrorResponse) Is(target error) bool {
+	code := 0
+	if r.Response != nil {
+		code = r.Response.StatusCode
+	}
+	switch target {
+	case ErrNotFound:
+		return code == http.StatusNotFound || strings.HasSuffix(r.Message, "-not-found")
+	case ErrPrivate:
+		return code == http.StatusForbidden || r.Message == "forbidden"
+	case ErrAuth:
+		return code == http.StatusUnauthorized || r.Message == "unauthorized"
+	case ErrRateLimited:
+		return code == http.StatusTooManyRequests || r.Message == "limit-exceeded"
+	}
+	return false
+}
+
+// Temporary reports whether request may succeed later, rate limited or
+// server error.
+func (r *ErrorResponse) Temporary() bool {
+	if r.Is(ErrRateLimited) {
+		return true
+	}
+	return r.Response != nil && r.Response.StatusCode >= 500
+}
+
 // CheckResponse checks the API response for errors, and returns them if
 // present.  A response is considered an error if it has a status code outside
 // the 200 range.  API error responses are expected to have either no response
@@ -227,9 +390,24 @@ func CheckResponse(r *http.Response) error {
 	if err == nil && data != nil {
 		json.Unmarshal(data, errorResponse)
 	}
+	errorResponse.RetryAfter = retryAfter(r.Header.Get("Retry-After"))
 	return errorResponse
 }
 
+// retryAfter parses Retry-After header in seconds or http date.
+func retryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(time.Now()) {
+		return time.Until(t)
+	}
+	return 0
+}
+
 // All feeds support the following optional arguments:
 
 // start=index - Return entries starting with the given index, e.g., start=30
@@ -239,11 +417,11 @@ func CheckResponse(r *http.Response) error {
 // hidden=1 - If specified, include hidden entries in the response. By default, hidden entries are excluded from the response. Hidden entries include the additional property hidden indicating the entry should be hidden based on the user's preferences.
 // fof=1 - Include "friend-of-friend" entries in the response. By default, friend-of-friend entries are excluded from the response. See Friend-of-friend entries
 // raw=1 - Include raw text entry and comment bodies in addition to the HTML bodies included by default. The raw text bodies are available as rawBody on all returned entries and comments. This also adds rawLink on all entries.
+//
+// MaxComments and MaxLikes are "0", "auto" or a number, default if empty.
 type FeedOptions struct {
 	Start       int    `url:"start,omitempty"`
 	Num         int    `url:"num,omitempty"`
-	Maxcomments int    `url:"maxcomments,omitempty"`
-	Maxlikes    int    `url:"maxlikes,omitempty"`
 	Hidden      int    `url:"hidden,omitempty"`
 	FoF         int    `url:"fof,omitempty"`
 	RawBody     int    `url:"raw,omitempty"`
@@ -1,12 +1,15 @@
 package ff
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -279,6 +282,73 @@ func TestV1Profile(t *testing.T) {
 	})
 }
 
+func TestRetry(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.MinBackoff = time.Millisecond
+	client.MaxBackoff = 10 * time.Millisecond
+
+	attempts := 0
+	mux.HandleFunc("/v2/feed/flaky", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"id": "flaky"}`)
+	})
+	mux.HandleFunc("/v2/feed/down", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	mux.HandleFunc("/v2/feed/busy", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"errorCode": "limit-exceeded"}`)
+	})
+	mux.HandleFunc("/v2/feed/private", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"errorCode": "forbidden"}`)
+	})
+	mux.HandleFunc("/v2/feed/nobody", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"errorCode": "feed-not-found"}`)
+	})
+
+	Convey("Given transient errors, retry with backoff", t, func() {
+		feed, _, err := client.Feed("flaky", nil)
+		So(err, ShouldBeNil)
+		So(feed.Id, ShouldEqual, "flaky")
+		So(attempts, ShouldEqual, 3)
+
+		_, resp, err := client.Feed("down", nil)
+		So(resp.StatusCode, ShouldEqual, http.StatusBadGateway)
+		er, ok := err.(*ErrorResponse)
+		So(ok, ShouldBeTrue)
+		So(er.Temporary(), ShouldBeTrue)
+
+		// rate limited longer than max backoff, left to caller
+		attempts = 0
+		start := time.Now()
+		_, _, err = client.Feed("busy", nil)
+		So(errors.Is(err, ErrRateLimited), ShouldBeTrue)
+		So(err.(*ErrorResponse).RetryAfter, ShouldEqual, time.Minute)
+		So(time.Since(start), ShouldBeLessThan, time.Second)
+
+		_, _, err = client.Feed("private", nil)
+		So(errors.Is(err, ErrPrivate), ShouldBeTrue)
+		So(errors.Is(err, ErrNotFound), ShouldBeFalse)
+		So(err.(*ErrorResponse).Temporary(), ShouldBeFalse)
+		_, _, err = client.Feed("nobody", nil)
+		So(errors.Is(err, ErrNotFound), ShouldBeTrue)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, _, err = client.FeedContext(ctx, "flaky", nil)
+		So(errors.Is(err, context.Canceled), ShouldBeTrue)
+	})
+}
+
 func TestIsMediaServer(t *testing.T) {
 	Convey("Given media url, should identify is it from ff media serer", t, func() {
 		ok := IsMediaServer("i.friendfeed.com")
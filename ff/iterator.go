@@ -0,0 +1,165 @@
+package ff
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/yinhm/friendfeed/proto"
+)
+
+const defaultPageSize = 100
+
+// PageOptions specifies pages of a feed iterated.
+type PageOptions struct {
+	// FeedOptions of every page, Start is the first page, Num is entries per
+	// page, 100 by default.
+	FeedOptions
+	// MaxEntries stops iterating after entries yielded, zero for no limit.
+	MaxEntries int
+	// Since stops iterating at the first entry older than it, entries are
+	// newest first.
+	Since time.Time
+}
+
+// FeedIterator yields entries of a feed across pages, newest first.
+//
+// Entries shifted by new posts while paging, or returned again by deep start
+// offsets, are yielded once. Iterating stops at an empty page, a page shorter
+// than Num, or a page of entries all yielded before.
+//
+//	it := client.FeedPages(ctx, "yinhm", nil)
+//	for it.Next() {
+//		entry := it.Entry()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type FeedIterator struct {
+	client *Client
+	ctx    context.Context
+	feedId string
+	opt    PageOptions
+
+	// feed of the last page, without entries
+	feed  *pb.Feed
+	start int
+	page  []*pb.Entry
+	index int
+	seen  map[string]bool
+	count int
+	done  bool
+	err   error
+}
+
+// FeedPages returns iterator of feedId, opt may be nil.
+func (c *Client) FeedPages(ctx context.Context, feedId string, opt *PageOptions) *FeedIterator {
+	it := &FeedIterator{
+		client: c,
+		ctx:    ctx,
+		feedId: feedId,
+		seen:   make(map[string]bool),
+	}
+	if opt != nil {
+		it.opt = *opt
+	}
+	if it.opt.Num <= 0 {
+		it.opt.Num = defaultPageSize
+	}
+	it.start = it.opt.Start
+	return it
+}
+
+// NextPage fetches entries of the next page not yielded before, returns
+// false when iterating stopped or failed.
+func (it *FeedIterator) NextPage() bool {
+	it.page, it.index = nil, 0
+	for !it.done && len(it.page) == 0 {
+		it.fetch()
+	}
+	return len(it.page) > 0
+}
+
+func (it *FeedIterator) fetch() {
+	opt := it.opt.FeedOptions
+	opt.Start = it.start
+	feed, _, err := it.client.FeedContext(it.ctx, it.feedId, &opt)
+	if err != nil {
+		it.err, it.done = err, true
+		return
+	}
+	entries := feed.Entries
+	feed.Entries = nil
+	it.feed = feed
+
+	fresh := false
+	for i, entry := range entries {
+		if it.seen[entry.Id] {
+			continue
+		}
+		fresh = true
+		if !it.opt.Since.IsZero() {
+			if date, err := time.Parse(time.RFC3339, entry.Date); err == nil && date.Before(it.opt.Since) {
+				it.start += i
+				it.done = true
+				return
+			}
+		}
+		it.seen[entry.Id] = true
+		it.page = append(it.page, entry)
+		it.count++
+		if it.opt.MaxEntries > 0 && it.count >= it.opt.MaxEntries {
+			it.start += i + 1
+			it.done = true
+			return
+		}
+	}
+	it.start += len(entries)
+	if !fresh || len(entries) < it.opt.Num {
+		it.done = true
+	}
+}
+
+// Page returns entries of the current page.
+func (it *FeedIterator) Page() []*pb.Entry {
+	return it.page
+}
+
+// Next advances to the next entry, fetching pages as needed, returns false
+// when iterating stopped or failed.
+func (it *FeedIterator) Next() bool {
+	if it.index < len(it.page) {
+		it.index++
+		if it.index < len(it.page) {
+			return true
+		}
+	}
+	if !it.NextPage() {
+		return false
+	}
+	it.index = 0
+	return true
+}
+
+// Entry returns the current entry of Next.
+func (it *FeedIterator) Entry() *pb.Entry {
+	if it.index < len(it.page) {
+		return it.page[it.index]
+	}
+	return nil
+}
+
+// Feed returns feed of the last page fetched, without entries.
+func (it *FeedIterator) Feed() *pb.Feed {
+	return it.feed
+}
+
+// Start returns start of the page after entries fetched, iterating resumes
+// from it.
+func (it *FeedIterator) Start() int {
+	return it.start
+}
+
+// Err returns the error stopped iterating, nil if stopped by end of feed or
+// options.
+func (it *FeedIterator) Err() error {
+	return it.err
+}
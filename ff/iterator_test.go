@@ -0,0 +1,90 @@
+package ff
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFeedPages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// feed.json is the first 30 entries of feed1.json, friendfeed returns
+	// page of start 500 again for deeper offsets
+	pages := map[string]string{
+		"":    "testdata/feed1.json",
+		"100": "testdata/feed5.json",
+		"200": "testdata/feed.json",
+		"500": "testdata/feed5.json",
+		"600": "testdata/feed5.json",
+	}
+	var starts []string
+	mux.HandleFunc("/v2/feed/yinhm", func(w http.ResponseWriter, r *http.Request) {
+		start := r.URL.Query().Get("start")
+		starts = append(starts, start)
+		data, err := ioutil.ReadFile(pages[start])
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+
+	Convey("Given feed pages, yield entries once until the end", t, func() {
+		ctx := context.Background()
+		it := client.FeedPages(ctx, "yinhm", nil)
+		n := 0
+		ids := make(map[string]bool)
+		for it.Next() {
+			ids[it.Entry().Id] = true
+			n++
+		}
+		So(it.Err(), ShouldBeNil)
+		So(n, ShouldEqual, 200)
+		So(len(ids), ShouldEqual, 200)
+		So(it.Start(), ShouldEqual, 230)
+		So(it.Feed().Id, ShouldEqual, "yinhm")
+		So(starts, ShouldResemble, []string{"", "100", "200"})
+
+		// deep offset returning the same page
+		starts = nil
+		it = client.FeedPages(ctx, "yinhm", &PageOptions{FeedOptions: FeedOptions{Start: 500}})
+		pageCount := 0
+		for it.NextPage() {
+			So(len(it.Page()), ShouldEqual, 100)
+			pageCount++
+		}
+		So(it.Err(), ShouldBeNil)
+		So(pageCount, ShouldEqual, 1)
+		So(starts, ShouldResemble, []string{"500", "600"})
+
+		it = client.FeedPages(ctx, "yinhm", &PageOptions{MaxEntries: 150})
+		n = 0
+		for it.Next() {
+			n++
+		}
+		So(n, ShouldEqual, 150)
+		So(it.Start(), ShouldEqual, 150)
+
+		since := time.Date(2014, 10, 1, 0, 0, 0, 0, time.UTC)
+		it = client.FeedPages(ctx, "yinhm", &PageOptions{Since: since})
+		n = 0
+		for it.Next() {
+			So(it.Entry().Date, ShouldBeGreaterThanOrEqualTo, "2014-10-01")
+			n++
+		}
+		So(n, ShouldEqual, 41)
+		So(it.Start(), ShouldEqual, 41)
+
+		it = client.FeedPages(ctx, "yinhm", &PageOptions{FeedOptions: FeedOptions{Start: 300}})
+		So(it.Next(), ShouldBeFalse)
+		So(errors.Is(it.Err(), ErrNotFound), ShouldBeTrue)
+	})
+}
@@ -0,0 +1,233 @@
+package ff
+
+// The rest of friendfeed v2 api, http://friendfeed.com/api/documentation
+//
+// Write methods POST form encoded parameters, as friendfeed does. Our own
+// server speaks a subset of the api under /v2, see httpd/src/api.go;
+// Feedlist, Picture, Comment, Subscribe and Unsubscribe are of friendfeed
+// only.
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-querystring/query"
+	pb "github.com/yinhm/friendfeed/proto"
+)
+
+// SearchOptions of /search, feed options apply to the result feed.
+//
+// Query supports friendfeed search operators, eg: "golang from:yinhm".
+type SearchOptions struct {
+	FeedOptions
+	Query string `url:"q"`
+}
+
+// URLOptions of /url, entries linking to Url are returned.
+//
+// Subscribed limits entries to feeds subscribed by the authed user, From
+// limits entries to feeds of comma separated ids.
+type URLOptions struct {
+	FeedOptions
+	Url        string `url:"url,omitempty"`
+	Subscribed int    `url:"subscribed,omitempty"`
+	From       string `url:"from,omitempty"`
+}
+
+// Picture sizes of /picture.
+const (
+	PictureSmall  = "small"
+	PictureMedium = "medium"
+	PictureLarge  = "large"
+)
+
+// FeedlistSection is a section of the sidebar on friendfeed.com, eg: Home,
+// Lists, Groups.
+type FeedlistSection struct {
+	Id    string     `json:"id,omitempty"`
+	Name  string     `json:"name"`
+	Feeds []*pb.Feed `json:"feeds"`
+}
+
+// Feedlist is the response of /feedlist.
+type Feedlist struct {
+	Sections []*FeedlistSection `json:"sections"`
+}
+
+// EntryPost is the parameters of POST /entry, Id set to edit body of entry.
+//
+// To is feed ids posted to, "me" by default. Link, To and ImageUrl apply to
+// new entries only, ImageUrl are urls of images attached.
+type EntryPost struct {
+	Id       string   `url:"id,omitempty"`
+	Body     string   `url:"body"`
+	Link     string   `url:"link,omitempty"`
+	Comment  string   `url:"comment,omitempty"`
+	To       []string `url:"to,comma,omitempty"`
+	ImageUrl []string `url:"image_url,omitempty"`
+}
+
+// CommentPost is the parameters of POST /comment, Id set to edit comment.
+type CommentPost struct {
+	Id    string `url:"id,omitempty"`
+	Entry string `url:"entry"`
+	Body  string `url:"body"`
+}
+
+// Search returns entries matched by opt.Query.
+func (c *Client) Search(ctx context.Context, opt *SearchOptions) (*pb.Feed, *http.Response, error) {
+	if opt == nil || opt.Query == "" {
+		return nil, nil, fmt.Errorf("ff: search query required")
+	}
+	path, err := addOptions("/search", opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	feed := new(pb.Feed)
+	resp, err := c.fetch(ctx, path, feed)
+	if err != nil {
+		return nil, resp, err
+	}
+	return feed, resp, err
+}
+
+// URL returns entries linking to opt.Url.
+func (c *Client) URL(ctx context.Context, opt *URLOptions) (*pb.Feed, *http.Response, error) {
+	path, err := addOptions("/url", opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	feed := new(pb.Feed)
+	resp, err := c.fetch(ctx, path, feed)
+	if err != nil {
+		return nil, resp, err
+	}
+	return feed, resp, err
+}
+
+// Feedlist returns feeds in the sidebar of the authed user, friendfeed only.
+func (c *Client) Feedlist(ctx context.Context) (*Feedlist, *http.Response, error) {
+	list := new(Feedlist)
+	resp, err := c.fetch(ctx, "/feedlist", list)
+	if err != nil {
+		return nil, resp, err
+	}
+	return list, resp, err
+}
+
+// Picture writes picture of feedId in size into w, size is one of
+// PictureSmall, PictureMedium and PictureLarge, small by default. Friendfeed
+// only.
+func (c *Client) Picture(ctx context.Context, feedId, size string, w io.Writer) (*http.Response, error) {
+	path := fmt.Sprintf("/picture/%v", feedId)
+	if size != "" {
+		path += "?size=" + url.QueryEscape(size)
+	}
+	return c.fetch(ctx, path, w)
+}
+
+// Comment returns comment of commentId, in form of "c/" prefixed uuid.
+// Friendfeed only.
+func (c *Client) Comment(ctx context.Context, commentId string) (*pb.Comment, *http.Response, error) {
+	path := fmt.Sprintf("/comment/%v", commentId)
+	comment := new(pb.Comment)
+	resp, err := c.fetch(ctx, path, comment)
+	if err != nil {
+		return nil, resp, err
+	}
+	return comment, resp, err
+}
+
+// PostEntry posts new entry, or edits entry of post.Id.
+func (c *Client) PostEntry(ctx context.Context, post *EntryPost) (*pb.Entry, *http.Response, error) {
+	entry := new(pb.Entry)
+	resp, err := c.post(ctx, "/entry", post, entry)
+	if err != nil {
+		return nil, resp, err
+	}
+	return entry, resp, err
+}
+
+// DeleteEntry deletes entry of entryId.
+func (c *Client) DeleteEntry(ctx context.Context, entryId string) (*http.Response, error) {
+	return c.post(ctx, "/entry/delete", url.Values{"id": {entryId}}, nil)
+}
+
+// PostComment comments on post.Entry, or edits comment of post.Id.
+func (c *Client) PostComment(ctx context.Context, post *CommentPost) (*pb.Comment, *http.Response, error) {
+	comment := new(pb.Comment)
+	resp, err := c.post(ctx, "/comment", post, comment)
+	if err != nil {
+		return nil, resp, err
+	}
+	return comment, resp, err
+}
+
+// DeleteComment deletes comment of commentId on entry of entryId, entry is
+// required by our server only.
+func (c *Client) DeleteComment(ctx context.Context, entryId, commentId string) (*http.Response, error) {
+	return c.post(ctx, "/comment/delete", url.Values{"entry": {entryId}, "id": {commentId}}, nil)
+}
+
+// Like likes entry of entryId. Response body, the like, is not decoded.
+func (c *Client) Like(ctx context.Context, entryId string) (*http.Response, error) {
+	return c.post(ctx, "/like", url.Values{"entry": {entryId}}, nil)
+}
+
+// Unlike removes like of entry of entryId.
+func (c *Client) Unlike(ctx context.Context, entryId string) (*http.Response, error) {
+	return c.post(ctx, "/like/delete", url.Values{"entry": {entryId}}, nil)
+}
+
+// Hide hides entry of entryId from the authed user, unhides if hide is false.
+func (c *Client) Hide(ctx context.Context, entryId string, hide bool) (*http.Response, error) {
+	form := url.Values{"entry": {entryId}}
+	if !hide {
+		form.Set("unhide", "1")
+	}
+	return c.post(ctx, "/hide", form, nil)
+}
+
+// Subscribe subscribes the authed user to feed of feedId, friendfeed only.
+func (c *Client) Subscribe(ctx context.Context, feedId string) (*http.Response, error) {
+	return c.post(ctx, "/subscribe", url.Values{"feed": {feedId}}, nil)
+}
+
+// Unsubscribe unsubscribes the authed user from feed of feedId, friendfeed
+// only.
+func (c *Client) Unsubscribe(ctx context.Context, feedId string) (*http.Response, error) {
+	return c.post(ctx, "/unsubscribe", url.Values{"feed": {feedId}}, nil)
+}
+
+// post sends form, url.Values or struct with "url" tags, to path.
+func (c *Client) post(ctx context.Context, path string, form interface{}, v interface{}) (*http.Response, error) {
+	values, ok := form.(url.Values)
+	if !ok {
+		var err error
+		if values, err = query.Values(form); err != nil {
+			return nil, err
+		}
+	}
+	req, err := c.NewFormRequest("POST", path, values)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req.WithContext(ctx), v)
+}
+
+// NewFormRequest creates an API request of form encoded values, path is
+// relative to the BaseURL of the Client as NewRequest.
+func (c *Client) NewFormRequest(method, path string, values url.Values) (*http.Request, error) {
+	req, err := http.NewRequest(method, c.BaseURL.String()+path, strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("User-Agent", c.UserAgent)
+	req.SetBasicAuth(c.username, c.authKey)
+	return req, nil
+}
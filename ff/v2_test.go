@@ -0,0 +1,153 @@
+package ff
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestV2Read(t *testing.T) {
+	setup()
+	defer teardown()
+
+	feed, err := ioutil.ReadFile("testdata/feed.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var queries []url.Values
+	mux.HandleFunc("/v2/search", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.Write(feed)
+	})
+	mux.HandleFunc("/v2/url", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.Write(feed)
+	})
+	mux.HandleFunc("/v2/feedlist", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"sections": [{"id": "home", "name": "Home", "feeds": [{"id": "home", "name": "Home", "type": "special"}]}, {"name": "Groups", "feeds": [{"id": "golang", "type": "group"}]}]}`)
+	})
+	mux.HandleFunc("/v2/picture/yinhm", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("size") != PictureLarge {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write([]byte("jpeg"))
+	})
+	mux.HandleFunc("/v2/comment/c/f2bb24b49e6e4a5c8ed7e67f1d6b9a29", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "c/f2bb24b49e6e4a5c8ed7e67f1d6b9a29", "body": "nice", "from": {"id": "bret"}}`)
+	})
+
+	Convey("Given v2 read endpoints, decode typed responses", t, func() {
+		ctx := context.Background()
+		opt := &SearchOptions{Query: "golang from:yinhm", FeedOptions: FeedOptions{Num: 10, MaxComments: "0"}}
+		result, _, err := client.Search(ctx, opt)
+		So(err, ShouldBeNil)
+		So(len(result.Entries), ShouldEqual, 30)
+		So(queries[0].Get("q"), ShouldEqual, "golang from:yinhm")
+		So(queries[0].Get("num"), ShouldEqual, "10")
+		So(queries[0]["maxcomments"], ShouldResemble, []string{"0"})
+		_, _, err = client.Search(ctx, &SearchOptions{})
+		So(err, ShouldNotBeNil)
+
+		_, _, err = client.URL(ctx, &URLOptions{Url: "http://golang.org/", Subscribed: 1})
+		So(err, ShouldBeNil)
+		So(queries[1].Get("url"), ShouldEqual, "http://golang.org/")
+		So(queries[1].Get("subscribed"), ShouldEqual, "1")
+
+		list, _, err := client.Feedlist(ctx)
+		So(err, ShouldBeNil)
+		So(len(list.Sections), ShouldEqual, 2)
+		So(list.Sections[1].Feeds[0].Id, ShouldEqual, "golang")
+
+		var buf bytes.Buffer
+		resp, err := client.Picture(ctx, "yinhm", PictureLarge, &buf)
+		So(err, ShouldBeNil)
+		So(resp.Header.Get("Content-Type"), ShouldEqual, "image/jpeg")
+		So(buf.String(), ShouldEqual, "jpeg")
+
+		comment, _, err := client.Comment(ctx, "c/f2bb24b49e6e4a5c8ed7e67f1d6b9a29")
+		So(err, ShouldBeNil)
+		So(comment.Body, ShouldEqual, "nice")
+		So(comment.From.Id, ShouldEqual, "bret")
+	})
+}
+
+func TestV2Write(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var forms []url.Values
+	var paths []string
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		user, key, _ := r.BasicAuth()
+		if r.Method != "POST" || user != "user" || key != "pwd" ||
+			r.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"errorCode": "unauthorized"}`)
+			return
+		}
+		r.ParseForm()
+		forms = append(forms, r.PostForm)
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/v2/entry":
+			fmt.Fprintf(w, `{"id": "e/1", "rawBody": %q}`, r.PostForm.Get("body"))
+		case "/v2/comment":
+			fmt.Fprintf(w, `{"id": "c/1", "rawBody": %q}`, r.PostForm.Get("body"))
+		case "/v2/like":
+			fmt.Fprint(w, `{"from": {"id": "user"}}`)
+		default:
+			fmt.Fprint(w, `{"success": true}`)
+		}
+	})
+
+	Convey("Given v2 write endpoints, post form values", t, func() {
+		ctx := context.Background()
+		entry, _, err := client.PostEntry(ctx, &EntryPost{
+			Body: "hello",
+			Link: "http://golang.org/",
+			To:   []string{"me", "golang"},
+		})
+		So(err, ShouldBeNil)
+		So(entry.Id, ShouldEqual, "e/1")
+		So(entry.RawBody, ShouldEqual, "hello")
+		So(forms[0].Get("to"), ShouldEqual, "me,golang")
+		So(forms[0].Get("link"), ShouldEqual, "http://golang.org/")
+		So(forms[0]["id"], ShouldBeNil)
+
+		comment, _, err := client.PostComment(ctx, &CommentPost{Entry: "e/1", Body: "nice"})
+		So(err, ShouldBeNil)
+		So(comment.RawBody, ShouldEqual, "nice")
+		So(forms[1].Get("entry"), ShouldEqual, "e/1")
+
+		_, err = client.Like(ctx, "e/1")
+		So(err, ShouldBeNil)
+		_, err = client.Unlike(ctx, "e/1")
+		So(err, ShouldBeNil)
+		_, err = client.Hide(ctx, "e/1", false)
+		So(err, ShouldBeNil)
+		So(forms[4].Get("unhide"), ShouldEqual, "1")
+		_, err = client.DeleteComment(ctx, "e/1", "c/1")
+		So(err, ShouldBeNil)
+		So(forms[5].Get("entry"), ShouldEqual, "e/1")
+		So(forms[5].Get("id"), ShouldEqual, "c/1")
+		_, err = client.DeleteEntry(ctx, "e/1")
+		So(err, ShouldBeNil)
+		_, err = client.Subscribe(ctx, "golang")
+		So(err, ShouldBeNil)
+		_, err = client.Unsubscribe(ctx, "golang")
+		So(err, ShouldBeNil)
+		So(paths, ShouldResemble, []string{
+			"/v2/entry", "/v2/comment", "/v2/like", "/v2/like/delete", "/v2/hide",
+			"/v2/comment/delete", "/v2/entry/delete", "/v2/subscribe", "/v2/unsubscribe",
+		})
+		So(forms[8].Get("feed"), ShouldEqual, "golang")
+	})
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/gin-gonic/contrib/sessions"
 	"github.com/gin-gonic/gin"
 	server "github.com/yinhm/friendfeed/httpd/src"
+	"github.com/yinhm/friendfeed/media"
 	"google.golang.org/grpc"
 
 	"github.com/markbates/goth"
@@ -155,6 +156,7 @@ func Serve(s *server.Server) {
 		authorized.GET("/import/", s.ImportHandler)
 		// authorized.POST("/ffimport/", s.FriendFeedImportHandler)
 		authorized.GET("/import/twitter", s.TwitterImportHandler)
+		authorized.POST("/import/:service", s.ServiceImportHandler)
 		// TODO: fix get
 		authorized.GET("/service/:service/delete", s.DeleteServiceHandler)
 	}
@@ -173,6 +175,12 @@ func Serve(s *server.Server) {
 	action := r.Group("/a", server.LoginRequired())
 	{
 		action.POST("/share", s.EntryPostHandler)
+		action.POST("/entry/edit", s.EntryEditHandler)
+		action.POST("/entry/delete", s.EntryDeleteHandler)
+		action.POST("/entry/hide", s.EntryHideHandler)
+		action.POST("/entry/unhide", s.EntryUnhideHandler)
+		action.POST("/author/hide", s.AuthorHideHandler)
+		action.POST("/author/unhide", s.AuthorUnhideHandler)
 		action.POST("/like", s.LikeHandler)
 		action.POST("/like/delete", s.LikeDeleteHandler)
 		action.POST("/comment", s.CommentHandler)
@@ -180,6 +188,43 @@ func Serve(s *server.Server) {
 	}
 
 	r.GET("/public", s.PublicHandler)
+	r.GET("/hashtag/:tag", s.HashtagHandler)
+	r.GET("/search", s.SearchHandler)
+	r.GET("/sup.json", s.SupHandler)
+
+	// media kept locally, see media.LocalStorage
+	if mc, err := media.NewConfigFromJSON(options.ConfigFile); err == nil && mc.IsLocal() {
+		r.GET("/media/*path", server.MediaHandler(media.NewLocalStorage(mc)))
+	}
+	r.GET("/push/callback", s.PushVerifyHandler)
+	r.POST("/push/callback", s.PushNotifyHandler)
+	r.POST("/push/hub", s.HubHandler)
+
+	// activitypub
+	r.GET("/.well-known/webfinger", s.WebFingerHandler)
+	r.GET("/ap/users/:id", s.ActorHandler)
+	r.GET("/ap/users/:id/outbox", s.OutboxHandler)
+	r.GET("/ap/users/:id/followers", s.FollowersHandler)
+	r.POST("/ap/users/:id/inbox", s.InboxHandler)
+
+	// friendfeed v2 api
+	v2 := r.Group("/v2", s.ApiAuth())
+	{
+		v2.GET("/feed/:id", s.ApiFeedHandler)
+		v2.GET("/entry/*id", s.ApiEntryHandler)
+		v2.GET("/feedinfo/:id", s.ApiFeedinfoHandler)
+		v2.GET("/search", s.ApiSearchHandler)
+	}
+	v2auth := r.Group("/v2", s.ApiAuth(), server.ApiLoginRequired())
+	{
+		v2auth.POST("/entry", s.ApiEntryPostHandler)
+		v2auth.POST("/entry/delete", s.ApiEntryDeleteHandler)
+		v2auth.POST("/hide", s.ApiHideHandler)
+		v2auth.POST("/comment", s.ApiCommentHandler)
+		v2auth.POST("/comment/delete", s.ApiCommentDeleteHandler)
+		v2auth.POST("/like", s.ApiLikeHandler)
+		v2auth.POST("/like/delete", s.ApiLikeDeleteHandler)
+	}
 
 	r.NoRoute(NotFoundHandler)
 
@@ -0,0 +1,252 @@
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yinhm/friendfeed/activitypub"
+	pb "github.com/yinhm/friendfeed/proto"
+	"google.golang.org/grpc"
+)
+
+const (
+	outboxPageSize = 20
+	maxInboxBody   = 1 << 20
+)
+
+// renderActivity writes v as activity document.
+func renderActivity(c *gin.Context, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	c.Data(http.StatusOK, activitypub.ContentType, body)
+}
+
+// activityError writes status of rpc error, returns false if err is nil.
+func activityError(c *gin.Context, err error) bool {
+	if err == nil {
+		return false
+	}
+	desc := grpc.ErrorDesc(err)
+	switch {
+	case desc == "404":
+		c.String(http.StatusNotFound, "feed not found")
+	case strings.HasPrefix(desc, "403"):
+		c.String(http.StatusForbidden, desc)
+	case strings.HasPrefix(desc, "401"):
+		c.String(http.StatusUnauthorized, desc)
+	case strings.HasPrefix(desc, "bad request"):
+		c.String(http.StatusBadRequest, desc)
+	default:
+		c.String(http.StatusInternalServerError, "server error")
+	}
+	return true
+}
+
+// GET /.well-known/webfinger?resource=acct:foo@example.com
+func (s *Server) WebFingerHandler(c *gin.Context) {
+	user, host, err := activitypub.ParseAcct(c.Query("resource"))
+	if err != nil || host != c.Request.Host {
+		c.String(http.StatusNotFound, "resource not found")
+		return
+	}
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	actor, err := s.client.FetchActor(ctx, &pb.ActorRequest{Id: user})
+	if activityError(c, err) {
+		return
+	}
+	body, err := json.Marshal(activitypub.WebFinger(baseURL(c), host, actor.Profile.Id))
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	c.Data(http.StatusOK, activitypub.ContentTypeJRD, body)
+}
+
+// GET /ap/users/:id
+func (s *Server) ActorHandler(c *gin.Context) {
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	actor, err := s.client.FetchActor(ctx, &pb.ActorRequest{Id: c.Param("id")})
+	if activityError(c, err) {
+		return
+	}
+
+	base := baseURL(c)
+	profile := actor.Profile
+	id := activitypub.ActorURL(base, profile.Id)
+	typ := "Person"
+	if profile.Type == "group" {
+		typ = "Group"
+	}
+	doc := &activitypub.Actor{
+		Context:           []string{activitypub.Context, activitypub.Security},
+		Id:                id,
+		Type:              typ,
+		PreferredUsername: profile.Id,
+		Name:              profile.Name,
+		Summary:           profile.Description,
+		Url:               base + "/feed/" + profile.Id,
+		Inbox:             id + "/inbox",
+		Outbox:            id + "/outbox",
+		Followers:         id + "/followers",
+		PublicKey: &activitypub.PublicKey{
+			Id:           activitypub.KeyId(id),
+			Owner:        id,
+			PublicKeyPem: actor.PublicKey,
+		},
+	}
+	if profile.Picture != "" {
+		doc.Icon = &activitypub.Image{Type: "Image", Url: profile.Picture}
+	}
+	renderActivity(c, doc)
+}
+
+// GET /ap/users/:id/outbox, Create activities of entries authored by feed,
+// paged by ?page=.
+func (s *Server) OutboxHandler(c *gin.Context) {
+	feedId := c.Param("id")
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	if _, err := s.client.FetchActor(ctx, &pb.ActorRequest{Id: feedId}); activityError(c, err) {
+		return
+	}
+
+	base := baseURL(c)
+	outbox := activitypub.ActorURL(base, feedId) + "/outbox"
+	page, _ := strconv.Atoi(c.Query("page"))
+	if page <= 0 {
+		renderActivity(c, &activitypub.OrderedCollection{
+			Context: activitypub.Context,
+			Id:      outbox,
+			Type:    "OrderedCollection",
+			First:   outbox + "?page=1",
+		})
+		return
+	}
+
+	req := &pb.FeedRequest{
+		Id:          feedId,
+		Start:       int32((page - 1) * outboxPageSize),
+		PageSize:    outboxPageSize,
+		MaxComments: pb.MaxNone,
+		MaxLikes:    pb.MaxNone,
+	}
+	feed, err := s.client.FetchFeed(ctx, req)
+	if activityError(c, err) {
+		return
+	}
+	if feed.Private {
+		c.String(http.StatusForbidden, "private feed")
+		return
+	}
+
+	doc := &activitypub.OrderedCollection{
+		Context:      activitypub.Context,
+		Id:           outbox + "?page=" + strconv.Itoa(page),
+		Type:         "OrderedCollectionPage",
+		PartOf:       outbox,
+		OrderedItems: []interface{}{},
+	}
+	for _, entry := range feed.Entries {
+		if entry.From == nil || entry.From.Id != feedId || !activitypub.IsPublic(entry) {
+			continue
+		}
+		activity, err := activitypub.EntryActivity("Create", entry, base)
+		if err != nil {
+			continue
+		}
+		activity.Context = nil
+		doc.OrderedItems = append(doc.OrderedItems, activity)
+	}
+	if len(feed.Entries) == outboxPageSize {
+		doc.Next = outbox + "?page=" + strconv.Itoa(page+1)
+	}
+	renderActivity(c, doc)
+}
+
+// GET /ap/users/:id/followers, only the number of followers is public.
+func (s *Server) FollowersHandler(c *gin.Context) {
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	actor, err := s.client.FetchActor(ctx, &pb.ActorRequest{Id: c.Param("id")})
+	if activityError(c, err) {
+		return
+	}
+	total := int(actor.Followers)
+	renderActivity(c, &activitypub.OrderedCollection{
+		Context:    activitypub.Context,
+		Id:         activitypub.ActorURL(baseURL(c), actor.Profile.Id) + "/followers",
+		Type:       "OrderedCollection",
+		TotalItems: &total,
+	})
+}
+
+// POST /ap/users/:id/inbox, signature verified by rpc server.
+func (s *Server) InboxHandler(c *gin.Context) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxInboxBody))
+	if err != nil {
+		c.AbortWithStatus(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	feedId := c.Param("id")
+	req := &pb.InboxRequest{
+		FeedId:  feedId,
+		Actor:   activitypub.ActorURL(baseURL(c), feedId),
+		Method:  c.Request.Method,
+		Path:    c.Request.URL.RequestURI(),
+		Host:    c.Request.Host,
+		Headers: make(map[string]string),
+		Body:    body,
+	}
+	for k, v := range c.Request.Header {
+		req.Headers[k] = strings.Join(v, ", ")
+	}
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	if _, err := s.client.PostInbox(ctx, req); activityError(c, err) {
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// entryNote renders entry as note if activity document asked, returns false
+// if html page should be rendered instead.
+func (s *Server) entryNote(c *gin.Context, uuid string) bool {
+	if !activitypub.IsActivity(c.Request.Header.Get("Accept")) {
+		return false
+	}
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	feed, err := s.client.FetchEntry(ctx, &pb.EntryRequest{Uuid: uuid, MaxComments: pb.MaxNone, MaxLikes: pb.MaxNone})
+	if activityError(c, err) {
+		return true
+	}
+	entry := feed.Entries[0]
+	if feed.Private || entry.From == nil || !activitypub.IsPublic(entry) {
+		c.String(http.StatusNotFound, "entry not found")
+		return true
+	}
+	note := activitypub.NoteFromEntry(entry, baseURL(c))
+	note.Context = activitypub.Context
+	renderActivity(c, note)
+	return true
+}
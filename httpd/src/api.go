@@ -0,0 +1,576 @@
+package server
+
+// FriendFeed v2 compatible api, http://friendfeed.com/api/documentation
+//
+// Existing FriendFeed clients, ff.Client as well, can talk to this server
+// by pointing to /v2. Authentication is the same as friendfeed: HTTP basic
+// auth with username and remote key, logged in session works as well.
+
+import (
+	"crypto/subtle"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	pb "github.com/yinhm/friendfeed/proto"
+	"github.com/yinhm/friendfeed/util"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	apiDefaultNum = 30
+	apiMaxNum     = 100
+)
+
+// ApiAuth authenticates api request with username and remote key.
+func (s *Server) ApiAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		username, remoteKey, ok := c.Request.BasicAuth()
+		if !ok {
+			return // fallback to session
+		}
+
+		ctx, cancel := DefaultTimeoutContext()
+		defer cancel()
+
+		profile, err := s.client.FetchProfile(ctx, &pb.ProfileRequest{Id: username})
+		if err != nil || profile.RemoteKey == "" ||
+			subtle.ConstantTimeCompare([]byte(profile.RemoteKey), []byte(remoteKey)) != 1 {
+			apiAbort(c, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		c.Set("user_id", profile.Id)
+		c.Set("uuid", profile.Uuid)
+	}
+}
+
+func ApiLoginRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if CurrentUserUuid(c) == "" {
+			apiAbort(c, http.StatusUnauthorized, "unauthorized")
+		}
+	}
+}
+
+func apiAbort(c *gin.Context, code int, errorCode string) {
+	c.AbortWithStatusJSON(code, gin.H{"errorCode": errorCode})
+}
+
+// ApiError writes friendfeed style error response.
+func ApiError(c *gin.Context, err error) bool {
+	if err == nil {
+		return false
+	}
+	switch {
+	case grpc.Code(err) == codes.DeadlineExceeded:
+		apiAbort(c, http.StatusServiceUnavailable, "limit-exceeded")
+	case grpc.ErrorDesc(err) == "404":
+		apiAbort(c, http.StatusNotFound, "error-not-found")
+	case strings.HasPrefix(grpc.ErrorDesc(err), "403"):
+		apiAbort(c, http.StatusForbidden, "forbidden")
+	default:
+		apiAbort(c, http.StatusInternalServerError, "internal-server-error")
+	}
+	return true
+}
+
+// parseMax parses maxcomments and maxlikes: auto, 0 or limit. All comments
+// and likes are returned if not specified. Limit of 1 collides with
+// pb.MaxAll, all fetched then collapsed by apiEntry.
+func parseMax(value string) int32 {
+	switch value {
+	case "", "1":
+		return pb.MaxAll
+	case "auto":
+		return pb.MaxAuto
+	case "0":
+		return pb.MaxNone
+	}
+	max, err := strconv.Atoi(value)
+	if err != nil || max < 0 {
+		return pb.MaxAuto
+	}
+	return int32(max)
+}
+
+func parseFeedRequest(c *gin.Context, id string) *pb.FeedRequest {
+	query := c.Request.URL.Query()
+	num, err := strconv.Atoi(query.Get("num"))
+	if err != nil || num <= 0 {
+		num = apiDefaultNum
+	}
+	if num > apiMaxNum {
+		num = apiMaxNum
+	}
+	return &pb.FeedRequest{
+		Id:          id,
+		Start:       int32(ParseStart(c.Request)),
+		PageSize:    int32(num),
+		Raw:         query.Get("raw") == "1",
+		MaxComments: parseMax(query.Get("maxcomments")),
+		MaxLikes:    parseMax(query.Get("maxlikes")),
+		User:        CurrentUserUuid(c),
+		Hidden:      query.Get("hidden") == "1",
+	}
+}
+
+// apiFeed strips non friendfeed fields, raw bodies only returned if asked.
+func (s *Server) apiFeed(c *gin.Context, feed *pb.Feed, req *pb.FeedRequest) *pb.Feed {
+	if len(feed.Entries) > int(req.PageSize) {
+		feed.Entries = feed.Entries[:req.PageSize]
+	}
+	feed.Uuid = ""
+	for _, e := range feed.Entries {
+		s.apiEntry(c, e, req.Raw)
+	}
+	return feed
+}
+
+func (s *Server) apiEntry(c *gin.Context, e *pb.Entry, raw bool) *pb.Entry {
+	e.ProfileUuid = ""
+	if e.From != nil {
+		e.From.Uuid = ""
+	}
+	for _, to := range e.To {
+		to.Uuid = ""
+	}
+	query := c.Request.URL.Query()
+	if query.Get("maxcomments") == "1" {
+		e.CollapseComments(1)
+	}
+	if query.Get("maxlikes") == "1" {
+		e.CollapseLikes(1)
+	}
+	if !raw {
+		e.RawBody = ""
+		e.RawLink = ""
+		for _, cmt := range e.Comments {
+			cmt.RawBody = ""
+		}
+	}
+
+	profile, err := s.CurrentUser(c)
+	if err != nil || profile.Id == "" {
+		return e
+	}
+	graph, err := s.CurrentGraph(c)
+	if err != nil {
+		return e
+	}
+	e.RebuildCommand(profile, graph)
+	e.RebuildCommentsCommand(profile, graph)
+	return e
+}
+
+// GET /v2/feed/:id
+func (s *Server) ApiFeedHandler(c *gin.Context) {
+	req := parseFeedRequest(c, c.Params.ByName("id"))
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	feed, err := s.client.FetchFeed(ctx, req)
+	if ApiError(c, err) {
+		return
+	}
+	if feed.Private && !s.feedReadable(c, feed.Id) {
+		apiAbort(c, http.StatusForbidden, "forbidden")
+		return
+	}
+	setSupHeader(c, feed)
+	c.JSON(200, s.apiFeed(c, feed, req))
+}
+
+// GET /v2/entry/:id, id could be prefixed with "e/".
+func (s *Server) ApiEntryHandler(c *gin.Context) {
+	id := strings.TrimPrefix(c.Params.ByName("id"), "/")
+	id = strings.TrimPrefix(id, "e/")
+	query := c.Request.URL.Query()
+	req := &pb.EntryRequest{
+		Uuid:        id,
+		MaxComments: parseMax(query.Get("maxcomments")),
+		MaxLikes:    parseMax(query.Get("maxlikes")),
+	}
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	feed, err := s.client.FetchEntry(ctx, req)
+	if ApiError(c, err) {
+		return
+	}
+	if (feed.Private && !s.feedReadable(c, feed.Id)) || !s.entryVisible(c, feed.Entries[0]) {
+		apiAbort(c, http.StatusForbidden, "forbidden")
+		return
+	}
+	c.JSON(200, s.apiEntry(c, feed.Entries[0], query.Get("raw") == "1"))
+}
+
+// GET /v2/feedinfo/:id
+func (s *Server) ApiFeedinfoHandler(c *gin.Context) {
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	req := &pb.ProfileRequest{Id: c.Params.ByName("id")}
+	feedinfo, err := s.client.FetchFeedinfo(ctx, req)
+	if ApiError(c, err) {
+		return
+	}
+	if feedinfo.Id == "" {
+		apiAbort(c, http.StatusNotFound, "error-not-found")
+		return
+	}
+	if feedinfo.Private && !s.feedReadable(c, feedinfo.Id) {
+		apiAbort(c, http.StatusForbidden, "forbidden")
+		return
+	}
+	// never leak remote key
+	feedinfo.RemoteKey = ""
+	feedinfo.Uuid = ""
+	c.JSON(200, feedinfo)
+}
+
+// parseSearchQuery splits feed to search in from search words, public feed
+// is searched if from: or in: not specified.
+func parseSearchQuery(q string) (feedId, query string) {
+	feedId = "public"
+	var words []string
+	for _, word := range strings.Fields(q) {
+		if strings.HasPrefix(word, "from:") || strings.HasPrefix(word, "in:") {
+			feedId = word[strings.Index(word, ":")+1:]
+			continue
+		}
+		words = append(words, word)
+	}
+	return feedId, strings.Join(words, " ")
+}
+
+// GET /v2/search?q=golang from:yinhm
+//
+// Entries contain all the words are returned, from: or in: limits search to
+// the feed, public feed is searched by default.
+func (s *Server) ApiSearchHandler(c *gin.Context) {
+	feedId, query := parseSearchQuery(c.Request.URL.Query().Get("q"))
+	if query == "" {
+		apiAbort(c, http.StatusBadRequest, "bad-query")
+		return
+	}
+
+	req := parseFeedRequest(c, feedId)
+	req.Query = query
+	if s.syndicate(c, req, "Search: "+req.Query) {
+		return
+	}
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	feed, err := s.client.FetchFeed(ctx, req)
+	if ApiError(c, err) {
+		return
+	}
+	if feed.Private && !s.feedReadable(c, feed.Id) {
+		apiAbort(c, http.StatusForbidden, "forbidden")
+		return
+	}
+	feed.Id = "search"
+	feed.Name = "Search"
+	feed.Description = ""
+	c.JSON(200, s.apiFeed(c, feed, req))
+}
+
+// POST /v2/entry
+//
+// body - required, the text of the entry
+// link - the link of the entry
+// to - comma separated feed ids to post to, defaults to "me"
+// id - the entry to edit body of, a new entry is posted if empty
+func (s *Server) ApiEntryPostHandler(c *gin.Context) {
+	c.Request.ParseForm()
+	rawBody := c.Request.Form.Get("body")
+	if rawBody == "" {
+		apiAbort(c, http.StatusBadRequest, "body-required")
+		return
+	}
+
+	profile, err := s.CurrentUser(c)
+	if ApiError(c, err) {
+		return
+	}
+	if id := strings.TrimPrefix(c.Request.Form.Get("id"), "e/"); id != "" {
+		s.apiEditEntry(c, profile, id, rawBody)
+		return
+	}
+
+	var to []*pb.Feed
+	for _, feedId := range strings.Split(c.Request.Form.Get("to"), ",") {
+		feedId = strings.TrimSpace(feedId)
+		if feedId == "" || feedId == "me" || feedId == profile.Id {
+			continue
+		}
+		if !s.feedWritable(c, feedId) {
+			apiAbort(c, http.StatusForbidden, "forbidden")
+			return
+		}
+		to = append(to, &pb.Feed{Id: feedId})
+	}
+
+	entry := newEntry(profile, rawBody, to)
+	if link := c.Request.Form.Get("link"); link != "" {
+		if !strings.HasPrefix(link, "http://") && !strings.HasPrefix(link, "https://") {
+			apiAbort(c, http.StatusBadRequest, "bad-link")
+			return
+		}
+		entry.RawLink = link
+		entry.Body = "<a rel=\"nofollow\" href=\"" + html.EscapeString(link) + "\">" + entry.Body + "</a>"
+	}
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	entry, err = s.client.PostEntry(ctx, entry)
+	if ApiError(c, err) {
+		return
+	}
+	c.JSON(200, s.apiEntry(c, entry, true))
+}
+
+// apiEditEntry edits body of entry of id, by the author or group admins.
+func (s *Server) apiEditEntry(c *gin.Context, profile *pb.Profile, id, rawBody string) {
+	body := util.DefaultSanitize(rawBody)
+	body = util.EntityToLink(body)
+	req := &pb.EntryEditRequest{
+		Entry:   id,
+		User:    profile.Id,
+		Body:    body,
+		RawBody: rawBody,
+	}
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	entry, err := s.client.EditEntry(ctx, req)
+	if ApiError(c, err) {
+		return
+	}
+	c.JSON(200, s.apiEntry(c, entry, true))
+}
+
+// POST /v2/like
+func (s *Server) ApiLikeHandler(c *gin.Context) {
+	s.apiLike(c, true)
+}
+
+// POST /v2/like/delete
+func (s *Server) ApiLikeDeleteHandler(c *gin.Context) {
+	s.apiLike(c, false)
+}
+
+func (s *Server) apiLike(c *gin.Context, like bool) {
+	c.Request.ParseForm()
+	entryId := strings.TrimPrefix(c.Request.Form.Get("entry"), "e/")
+	if entryId == "" {
+		apiAbort(c, http.StatusBadRequest, "entry-required")
+		return
+	}
+
+	req := &pb.LikeRequest{
+		Entry: entryId,
+		User:  CurrentUserUuid(c),
+		Like:  like,
+	}
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	entry, err := s.client.LikeEntry(ctx, req)
+	if ApiError(c, err) {
+		return
+	}
+	if !like {
+		c.JSON(200, gin.H{"success": true})
+		return
+	}
+
+	userId := CurrentUserId(c)
+	for _, l := range entry.Likes {
+		if l.From != nil && l.From.Id == userId {
+			c.JSON(200, l)
+			return
+		}
+	}
+	c.JSON(200, gin.H{"success": true})
+}
+
+// POST /v2/comment
+//
+// entry - required, the entry to comment on
+// body - required, the text of the comment
+// id - the comment to edit, a new comment is posted if empty
+func (s *Server) ApiCommentHandler(c *gin.Context) {
+	c.Request.ParseForm()
+	entryId := strings.TrimPrefix(c.Request.Form.Get("entry"), "e/")
+	if entryId == "" {
+		apiAbort(c, http.StatusBadRequest, "entry-required")
+		return
+	}
+	rawBody := c.Request.Form.Get("body")
+	if rawBody == "" {
+		apiAbort(c, http.StatusBadRequest, "body-required")
+		return
+	}
+
+	profile, err := s.CurrentUser(c)
+	if ApiError(c, err) {
+		return
+	}
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	comment := newComment(profile, entryId, rawBody)
+	if id := c.Request.Form.Get("id"); id != "" {
+		old, err := s.apiComment(ctx, entryId, id)
+		if ApiError(c, err) {
+			return
+		}
+		if old.From == nil || old.From.Id != profile.Id {
+			apiAbort(c, http.StatusForbidden, "forbidden")
+			return
+		}
+		comment.Id = old.Id
+		comment.Date = old.Date
+	}
+
+	req := &pb.CommentRequest{
+		Entry:   entryId,
+		Comment: comment,
+	}
+	if _, err := s.client.CommentEntry(ctx, req); ApiError(c, err) {
+		return
+	}
+	comment.Commands = []string{"edit", "delete"}
+	c.JSON(200, comment)
+}
+
+// POST /v2/comment/delete
+//
+// entry - required, the entry commented on
+// id - required, the comment to delete
+func (s *Server) ApiCommentDeleteHandler(c *gin.Context) {
+	c.Request.ParseForm()
+	entryId := strings.TrimPrefix(c.Request.Form.Get("entry"), "e/")
+	if entryId == "" {
+		apiAbort(c, http.StatusBadRequest, "entry-required")
+		return
+	}
+	id := c.Request.Form.Get("id")
+	if id == "" {
+		apiAbort(c, http.StatusBadRequest, "id-required")
+		return
+	}
+
+	profile, err := s.CurrentUser(c)
+	if ApiError(c, err) {
+		return
+	}
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	comment, err := s.apiComment(ctx, entryId, id)
+	if ApiError(c, err) {
+		return
+	}
+	if comment.From == nil || comment.From.Id != profile.Id {
+		apiAbort(c, http.StatusForbidden, "forbidden")
+		return
+	}
+
+	req := &pb.CommentDeleteRequest{
+		Entry:   entryId,
+		Comment: comment.Id,
+		User:    profile.Id,
+	}
+	if _, err := s.client.DeleteComment(ctx, req); ApiError(c, err) {
+		return
+	}
+	c.JSON(200, gin.H{"success": true})
+}
+
+// apiComment returns comment of id on entry, ids are either the comment
+// uuid or e/:entry/c/:uuid as friendfeed.
+func (s *Server) apiComment(ctx context.Context, entryId, id string) (*pb.Comment, error) {
+	feed, err := s.client.FetchEntry(ctx, &pb.EntryRequest{Uuid: entryId, MaxComments: pb.MaxAll, MaxLikes: pb.MaxNone})
+	if err != nil {
+		return nil, err
+	}
+	for _, cmt := range feed.Entries[0].Comments {
+		if cmt.Id == id || strings.HasSuffix(cmt.Id, "/c/"+id) || strings.HasSuffix(id, "/c/"+cmt.Id) {
+			return cmt, nil
+		}
+	}
+	return nil, grpc.Errorf(codes.NotFound, "404")
+}
+
+// POST /v2/entry/delete
+//
+// id - required, the entry to delete
+func (s *Server) ApiEntryDeleteHandler(c *gin.Context) {
+	c.Request.ParseForm()
+	entryId := strings.TrimPrefix(c.Request.Form.Get("id"), "e/")
+	if entryId == "" {
+		apiAbort(c, http.StatusBadRequest, "id-required")
+		return
+	}
+
+	profile, err := s.CurrentUser(c)
+	if ApiError(c, err) {
+		return
+	}
+	req := &pb.EntryDeleteRequest{
+		Entry: entryId,
+		User:  profile.Id,
+	}
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	_, err = s.client.DeleteEntry(ctx, req)
+	if ApiError(c, err) {
+		return
+	}
+	c.JSON(200, gin.H{"success": true})
+}
+
+// POST /v2/hide
+//
+// entry - required, the entry to hide
+// unhide - unhides the entry if 1
+func (s *Server) ApiHideHandler(c *gin.Context) {
+	c.Request.ParseForm()
+	entryId := strings.TrimPrefix(c.Request.Form.Get("entry"), "e/")
+	if entryId == "" {
+		apiAbort(c, http.StatusBadRequest, "entry-required")
+		return
+	}
+
+	req := &pb.HideRequest{
+		User:   CurrentUserUuid(c),
+		Target: entryId,
+		Hide:   c.Request.Form.Get("unhide") != "1",
+	}
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	_, err := s.client.HideEntry(ctx, req)
+	if ApiError(c, err) {
+		return
+	}
+	c.JSON(200, gin.H{"success": true})
+}
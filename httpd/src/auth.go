@@ -134,6 +134,10 @@ func GoogleAuthConfig(keyPath string, debug bool) *oauth2.Config {
 }
 
 func CurrentUserId(c *gin.Context) string {
+	// api request authed by ApiAuth
+	if id, ok := c.Get("user_id"); ok {
+		return id.(string)
+	}
 	sess := sessions.Default(c)
 	if sess.Get("user_id") == nil {
 		return ""
@@ -142,6 +146,9 @@ func CurrentUserId(c *gin.Context) string {
 }
 
 func CurrentUserUuid(c *gin.Context) string {
+	if uuid, ok := c.Get("uuid"); ok {
+		return uuid.(string)
+	}
 	sess := sessions.Default(c)
 	if sess.Get("uuid") == nil {
 		return ""
@@ -173,6 +180,8 @@ func (s *Server) GraphFrom(uuid string) (*pb.Graph, error) {
 	return graph, nil
 }
 
+// feedWritable reports whether current user can post to the feed: own feed,
+// groups subscribed to, or direct message to users who subscribe to us.
 func (s *Server) feedWritable(c *gin.Context, feedId string) bool {
 	// owner feed
 	user, err := s.CurrentUser(c)
@@ -183,23 +192,17 @@ func (s *Server) feedWritable(c *gin.Context, feedId string) bool {
 		return true
 	}
 
-	ctx, cancel := DefaultTimeoutContext()
-	defer cancel()
-
-	// group feed
-	profile, err := s.client.FetchProfile(ctx, &pb.ProfileRequest{Uuid: feedId})
-	if err != nil || profile == nil {
-		return false
-	}
-	if profile.Type != "group" {
-		return false
-	}
-
 	graph, err := s.CurrentGraph(c)
 	if err != nil || graph == nil {
 		return false
 	}
-	if _, ok := graph.Subscriptions[feedId]; ok {
+
+	// group feed
+	if group, ok := graph.Subscriptions[feedId]; ok && group.Type == "group" {
+		return true
+	}
+	// direct message
+	if _, ok := graph.Subscribers[feedId]; ok {
 		return true
 	}
 	return false
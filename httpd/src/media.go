@@ -0,0 +1,39 @@
+package server
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yinhm/friendfeed/media"
+)
+
+// MediaHandler serves media of local storage at /media/*path.
+func MediaHandler(fs *media.LocalStorage) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Params.ByName("path")
+		f, mimeType, err := fs.Open(name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				c.AbortWithStatus(http.StatusNotFound)
+			} else {
+				c.AbortWithStatus(http.StatusBadRequest)
+			}
+			return
+		}
+		defer f.Close()
+
+		fi, err := f.Stat()
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		if mimeType != "" {
+			c.Writer.Header().Set("Content-Type", mimeType)
+		}
+		// media of a path never changes
+		c.Writer.Header().Set("Cache-Control", "public, max-age=31536000")
+		c.Writer.Header().Set("X-Content-Type-Options", "nosniff")
+		http.ServeContent(c.Writer, c.Request, name, fi.ModTime(), f)
+	}
+}
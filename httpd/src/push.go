@@ -0,0 +1,163 @@
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	pb "github.com/yinhm/friendfeed/proto"
+	"github.com/yinhm/friendfeed/websub"
+	"google.golang.org/grpc"
+)
+
+// maxPushBody limits content distributed by hubs.
+const maxPushBody = 4 << 20
+
+// pushCallbackURL returns websub callback of our site, callback of a topic
+// is websub.CallbackURL of it.
+func pushCallbackURL(c *gin.Context) string {
+	return baseURL(c) + "/push/callback"
+}
+
+// GET /push/callback, intent verification from hub.
+func (s *Server) PushVerifyHandler(c *gin.Context) {
+	v, err := websub.ParseVerification(c.Request.URL.Query())
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	req := &pb.PushRequest{
+		Topic:        v.Topic,
+		Mode:         v.Mode,
+		Challenge:    v.Challenge,
+		LeaseSeconds: int64(v.Lease.Seconds()),
+		Callback:     websub.CallbackURL(pushCallbackURL(c), c.Query("topic")),
+	}
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	resp, err := s.client.PushNotify(ctx, req)
+	if err != nil {
+		// unknown topic or no request pending, hub should give up
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+	c.String(200, resp.Challenge)
+}
+
+// POST /push/callback?topic=, content distribution from hub.
+func (s *Server) PushNotifyHandler(c *gin.Context) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPushBody))
+	if err != nil {
+		c.AbortWithStatus(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	req := &pb.PushRequest{
+		Topic:     c.Request.URL.Query().Get("topic"),
+		Body:      body,
+		Signature: c.Request.Header.Get(websub.SignatureHeader),
+	}
+	if req.Topic == "" {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	_, err = s.client.PushNotify(ctx, req)
+	switch {
+	case err == nil:
+	case strings.HasPrefix(grpc.ErrorDesc(err), "403"):
+		// bad signature, acknowledged but ignored as websub requires
+	case grpc.ErrorDesc(err) == "404":
+		c.AbortWithStatus(http.StatusGone)
+		return
+	default:
+		// hubs retry on non 2xx
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// hubURL returns url of our websub hub.
+func hubURL(c *gin.Context) string {
+	return baseURL(c) + "/push/hub"
+}
+
+// setHubHeader advertises hub and topic of syndicated feed.
+func setHubHeader(c *gin.Context, topic string) {
+	c.Writer.Header().Add("Link", "<"+hubURL(c)+`>; rel="hub"`)
+	c.Writer.Header().Add("Link", "<"+topic+`>; rel="self"`)
+}
+
+// hubTopicFeed returns feed id of topic, topics are atom or rss urls of
+// feeds on this site: /feed/:id or /public.
+func hubTopicFeed(c *gin.Context, topic string) (string, bool) {
+	u, err := url.Parse(topic)
+	if err != nil || u.Host != c.Request.Host {
+		return "", false
+	}
+	if format := u.Query().Get("format"); format != "atom" && format != "rss" {
+		return "", false
+	}
+	switch {
+	case u.Path == "/" || u.Path == "/public":
+		return "public", true
+	case strings.HasPrefix(u.Path, "/feed/"):
+		feedId := strings.TrimPrefix(u.Path, "/feed/")
+		return feedId, feedId != "" && !strings.Contains(feedId, "/")
+	}
+	return "", false
+}
+
+// POST /push/hub, subscribe or unsubscribe to our feeds.
+func (s *Server) HubHandler(c *gin.Context) {
+	c.Request.ParseForm()
+	form := c.Request.PostForm
+
+	callback := form.Get("hub.callback")
+	if u, err := url.Parse(callback); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		c.String(http.StatusBadRequest, "bad hub.callback")
+		return
+	}
+	topic := form.Get("hub.topic")
+	feedId, ok := hubTopicFeed(c, topic)
+	if !ok {
+		c.String(http.StatusBadRequest, "bad hub.topic")
+		return
+	}
+	lease, _ := strconv.Atoi(form.Get("hub.lease_seconds"))
+
+	req := &pb.HubSubscription{
+		Mode:         form.Get("hub.mode"),
+		FeedId:       feedId,
+		Topic:        topic,
+		Callback:     callback,
+		Secret:       form.Get("hub.secret"),
+		LeaseSeconds: int64(lease),
+		Hub:          hubURL(c),
+	}
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	_, err := s.client.HubSubscribe(ctx, req)
+	switch {
+	case err == nil:
+		c.Status(http.StatusAccepted)
+	case grpc.ErrorDesc(err) == "404":
+		c.String(http.StatusNotFound, "feed not found")
+	case strings.HasPrefix(grpc.ErrorDesc(err), "403"):
+		c.String(http.StatusForbidden, "private feed")
+	default:
+		c.String(http.StatusBadRequest, grpc.ErrorDesc(err))
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/sha1"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strings"
@@ -19,6 +20,7 @@ import (
 	"github.com/gin-gonic/gin/binding"
 	"github.com/golang/protobuf/proto"
 	uuid "github.com/satori/go.uuid"
+	"github.com/yinhm/friendfeed/activitypub"
 	"github.com/yinhm/friendfeed/ff"
 	"github.com/yinhm/friendfeed/httpd/src/react"
 	pb "github.com/yinhm/friendfeed/proto"
@@ -193,16 +195,44 @@ func (s *Server) feedReadable(c *gin.Context, feedId string) bool {
 	return false
 }
 
+// entryVisible reports whether entry is visible to current user, same as in
+// feeds: direct messages and entries to private groups are visible to the
+// author, recipients and readers of the groups only.
+func (s *Server) entryVisible(c *gin.Context, entry *pb.Entry) bool {
+	if activitypub.IsPublic(entry) {
+		return true
+	}
+	user := CurrentUserUuid(c)
+	if user != "" && user == entry.ProfileUuid {
+		return true
+	}
+	for _, to := range entry.To {
+		if to == nil {
+			continue
+		}
+		if user != "" && to.Uuid == user {
+			return true
+		}
+		if to.Type == "group" && s.feedReadable(c, to.Id) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Server) FetchFeed(c *gin.Context, req proto.Message) (profile *pb.Profile, feed *pb.Feed, err error) {
 	ctx, cancel := DefaultTimeoutContext()
 	defer cancel()
 
-	var format = false
-
 	switch req.(type) {
 	case *pb.FeedRequest:
-		feed, err = s.client.FetchFeed(ctx, req.(*pb.FeedRequest))
-		format = true
+		freq := req.(*pb.FeedRequest)
+		freq.User = CurrentUserUuid(c)
+		freq.Hidden = c.Request.URL.Query().Get("hidden") == "1"
+		feed, err = s.client.FetchFeed(ctx, freq)
+		if err == nil && freq.Query == "" {
+			setSupHeader(c, feed)
+		}
 	case *pb.EntryRequest:
 		feed, err = s.client.FetchEntry(ctx, req.(*pb.EntryRequest))
 	}
@@ -222,11 +252,6 @@ func (s *Server) FetchFeed(c *gin.Context, req proto.Message) (profile *pb.Profi
 		e.RebuildCommand(profile, graph)
 		basetime, _ = time.Parse(time.RFC3339, e.Date)
 		e.Date = util.FormatTime(basetime)
-
-		if format {
-			e.FormatComments(int32(0))
-			e.FormatLikes(int32(0))
-		}
 		e.RebuildCommentsCommand(profile, graph)
 	}
 	return
@@ -262,6 +287,30 @@ func (s *Server) TwitterImportHandler(c *gin.Context) {
 	c.Redirect(http.StatusFound, "/auth/twitter")
 }
 
+func (s *Server) ServiceImportHandler(c *gin.Context) {
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	uuid := CurrentUserUuid(c)
+	if uuid == "" {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+	req := &pb.ServiceRequest{
+		User:    uuid,
+		Service: c.Params.ByName("service"),
+		Url:     strings.TrimSpace(c.PostForm("url")),
+		Name:    strings.TrimSpace(c.PostForm("name")),
+		// hub advertised by feed subscribed
+		PushCallback: pushCallbackURL(c),
+	}
+	_, err := s.client.AddService(ctx, req)
+	if RequestError(c, err) {
+		return
+	}
+	c.Redirect(http.StatusFound, "/account/import")
+}
+
 func (s *Server) DeleteServiceHandler(c *gin.Context) {
 	service := c.Params.ByName("service")
 	ctx, cancel := DefaultTimeoutContext()
@@ -275,6 +324,7 @@ func (s *Server) DeleteServiceHandler(c *gin.Context) {
 	req := &pb.ServiceRequest{
 		User:    uuid,
 		Service: service,
+		Url:     c.Query("url"),
 	}
 	_, err := s.client.DeleteService(ctx, req)
 	if err != nil {
@@ -296,9 +346,16 @@ func (s *Server) FriendFeedImportHandler(c *gin.Context) {
 
 	// group feed not supported
 	apiv1 := ff.NewV1Client(s.httpclient, username, remoteKey)
-	v1profile, resp, err := apiv1.V1Profile(username, "user")
-	if err != nil {
-		c.String(resp.StatusCode, err.Error())
+	v1profile, _, err := apiv1.V1ProfileContext(c.Request.Context(), username, "user")
+	switch {
+	case errors.Is(err, ff.ErrAuth):
+		c.String(http.StatusUnauthorized, "Wrong username or remote key")
+		return
+	case errors.Is(err, ff.ErrNotFound):
+		c.String(http.StatusNotFound, "Unknown feed")
+		return
+	case err != nil:
+		c.String(http.StatusBadGateway, err.Error())
 		return
 	}
 
@@ -348,6 +405,9 @@ func (s *Server) HomeHandler(c *gin.Context) {
 		Start:    int32(start),
 		PageSize: 30,
 	}
+	if s.syndicate(c, req, "") {
+		return
+	}
 
 	profile, feed, err := s.FetchFeed(c, req)
 	if RequestError(c, err) {
@@ -382,6 +442,9 @@ func (s *Server) FeedHandler(c *gin.Context) {
 		Start:    int32(start),
 		PageSize: 30,
 	}
+	if s.syndicate(c, req, "") {
+		return
+	}
 	_, feed, err := s.FetchFeed(c, req)
 	if RequestError(c, err) {
 		return
@@ -424,7 +487,17 @@ func contains(slice []string, item string) bool {
 
 func (s *Server) EntryHandler(c *gin.Context) {
 	uuid := c.Params.ByName("uuid")
-	req := &pb.EntryRequest{Uuid: uuid}
+	if s.entryNote(c, uuid) {
+		return
+	}
+	req := &pb.EntryRequest{
+		Uuid:        uuid,
+		MaxComments: pb.MaxAll,
+		MaxLikes:    pb.MaxAll,
+	}
+	if s.syndicate(c, req, "") {
+		return
+	}
 	_, feed, err := s.FetchFeed(c, req)
 	if RequestError(c, err) {
 		return
@@ -445,29 +518,65 @@ func (s *Server) EntryHandler(c *gin.Context) {
 	s.HTML(c, 200, "feed.html", data)
 }
 
-// TODO: allow cross post to multiply feeds
 func (s *Server) EntryPostHandler(c *gin.Context) {
 	var form struct {
-		FeedId string `form:"feedid" binding:"required"`
-		Body   string `form:"body" binding:"required"`
+		FeedIds []string `form:"feedid" binding:"required"`
+		Body    string   `form:"body" binding:"required"`
 	}
 	c.MustBindWith(&form, binding.FormMultipart)
 
-	if !s.feedWritable(c, form.FeedId) {
-		c.AbortWithStatus(401)
+	profile, err := s.CurrentUser(c)
+	if RequestError(c, err) {
 		return
 	}
 
-	body := util.DefaultSanitize(form.Body)
-	body = util.EntityToLink(body)
+	// cross post to multiply feeds, chosen or typed comma separated
+	var feedIds []string
+	for _, ids := range form.FeedIds {
+		feedIds = append(feedIds, strings.Split(ids, ",")...)
+	}
+	var to []*pb.Feed
+	seen := map[string]bool{}
+	for _, feedId := range feedIds {
+		feedId = strings.TrimSpace(feedId)
+		if feedId == "" || seen[feedId] {
+			continue
+		}
+		seen[feedId] = true
+
+		if !s.feedWritable(c, feedId) {
+			c.AbortWithStatus(401)
+			return
+		}
+		if feedId != profile.Id {
+			to = append(to, &pb.Feed{Id: feedId})
+		}
+	}
+	if len(seen) == 0 {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
 
 	ctx, cancel := DefaultTimeoutContext()
 	defer cancel()
 
-	profile, _ := s.CurrentUser(c)
+	entry := newEntry(profile, form.Body, to)
+	entry, err = s.client.PostEntry(ctx, entry)
+	if RequestError(c, err) {
+		return
+	}
+	// c.JSON(200, gin.H{"entry": entry})
+	c.Redirect(http.StatusFound, "/")
+}
+
+// newEntry builds entry posted by profile to feeds.
+func newEntry(profile *pb.Profile, rawBody string, to []*pb.Feed) *pb.Entry {
+	body := util.DefaultSanitize(rawBody)
+	body = util.EntityToLink(body)
+
+	// random, entries posted within a second never collide
 	dt := time.Now().UTC()
-	name := profile.Uuid + "/" + dt.Format(time.RFC3339)
-	uuid1 := uuid.NewV5(uuid.NamespaceURL, name)
+	uuid1 := uuid.NewV4()
 
 	from := &pb.Feed{
 		Id:   profile.Id,
@@ -475,28 +584,155 @@ func (s *Server) EntryPostHandler(c *gin.Context) {
 		Type: profile.Type,
 	}
 
-	entry := &pb.Entry{
+	return &pb.Entry{
 		Id:      uuid1.String(),
 		Date:    dt.Format(time.RFC3339),
 		Body:    body,
-		RawBody: form.Body,
+		RawBody: rawBody,
 		From:    from,
-		// To:      []*pb.Feed{from},
+		To:      to,
 		// Thumbnails: thumbnails,
 		ProfileUuid: profile.Uuid,
 	}
+}
+
+// newComment returns new comment of profile on entry.
+func newComment(profile *pb.Profile, entryId, rawBody string) *pb.Comment {
+	body := util.DefaultSanitize(rawBody)
+	body = util.EntityToLink(body)
+
+	date := time.Now().UTC().Format(time.RFC3339)
+	uuid1 := uuid.NewV5(uuid.NamespaceURL, entryId+profile.Uuid+date)
+	return &pb.Comment{
+		Id:      uuid1.String(),
+		Date:    date,
+		Body:    body,
+		RawBody: rawBody,
+		From: &pb.Feed{
+			Id:   profile.Id,
+			Name: profile.Name,
+			Type: profile.Type,
+		},
+	}
+}
+
+// /a/entry/edit
+func (s *Server) EntryEditHandler(c *gin.Context) {
+	var form struct {
+		Entry string `form:"entry" binding:"required"`
+		Body  string `form:"body" binding:"required"`
+	}
+	c.MustBindWith(&form, binding.Form)
+
+	body := util.DefaultSanitize(form.Body)
+	body = util.EntityToLink(body)
+
+	profile, _ := s.CurrentUser(c)
+	graph, _ := s.CurrentGraph(c)
+	req := &pb.EntryEditRequest{
+		Entry:   form.Entry,
+		User:    profile.Id,
+		Body:    body,
+		RawBody: form.Body,
+	}
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	entry, err := s.client.EditEntry(ctx, req)
+	if RequestError(c, err) {
+		return
+	}
+
+	basetime, _ := time.Parse(time.RFC3339, entry.Date)
+	entry.Date = util.FormatTime(basetime)
+	entry.RebuildCommand(profile, graph)
+	entry.FormatComments(pb.MaxAuto)
+	entry.FormatLikes(pb.MaxAuto)
+	entry.RebuildCommentsCommand(profile, graph)
+	c.JSON(200, entry)
+}
+
+// /a/entry/delete
+func (s *Server) EntryDeleteHandler(c *gin.Context) {
+	var form struct {
+		Entry string `form:"entry" binding:"required"`
+	}
+	c.MustBindWith(&form, binding.Form)
+
+	profile, _ := s.CurrentUser(c)
+	req := &pb.EntryDeleteRequest{
+		Entry: form.Entry,
+		User:  profile.Id,
+	}
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
 
-	entry, err := s.client.PostEntry(ctx, entry)
+	_, err := s.client.DeleteEntry(ctx, req)
 	if RequestError(c, err) {
 		return
 	}
-	// c.JSON(200, gin.H{"entry": entry})
-	c.Redirect(http.StatusFound, "/")
+	c.JSON(200, gin.H{"status": "ok"})
+}
+
+// /a/entry/hide
+func (s *Server) EntryHideHandler(c *gin.Context) {
+	s.hide(c, "entry", true)
+}
+
+// /a/entry/unhide
+func (s *Server) EntryUnhideHandler(c *gin.Context) {
+	s.hide(c, "entry", false)
+}
+
+// /a/author/hide
+func (s *Server) AuthorHideHandler(c *gin.Context) {
+	s.hide(c, "author", true)
+}
+
+// /a/author/unhide
+func (s *Server) AuthorUnhideHandler(c *gin.Context) {
+	s.hide(c, "author", false)
+}
+
+// hide hides or unhides entry or author from current user's feeds.
+func (s *Server) hide(c *gin.Context, field string, hide bool) {
+	c.Request.ParseForm()
+	target := c.Request.Form.Get(field)
+	if target == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "bad request"})
+		return
+	}
+
+	req := &pb.HideRequest{
+		User:   CurrentUserUuid(c),
+		Target: target,
+		Hide:   hide,
+	}
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	var err error
+	if field == "author" {
+		_, err = s.client.HideAuthor(ctx, req)
+	} else {
+		_, err = s.client.HideEntry(ctx, req)
+	}
+	if RequestError(c, err) {
+		return
+	}
+	c.JSON(200, gin.H{"status": "ok"})
 }
 
 func (s *Server) ExpandCommentHandler(c *gin.Context) {
 	uuid := c.Params.ByName("uuid")
-	req := &pb.EntryRequest{Uuid: uuid}
+	req := &pb.EntryRequest{
+		Uuid:        uuid,
+		MaxComments: pb.MaxAll,
+		MaxLikes:    pb.MaxAll,
+	}
 
 	ctx, cancel := DefaultTimeoutContext()
 	defer cancel()
@@ -514,7 +750,11 @@ func (s *Server) ExpandCommentHandler(c *gin.Context) {
 
 func (s *Server) ExpandLikeHandler(c *gin.Context) {
 	uuid := c.Params.ByName("uuid")
-	req := &pb.EntryRequest{Uuid: uuid}
+	req := &pb.EntryRequest{
+		Uuid:        uuid,
+		MaxComments: pb.MaxAll,
+		MaxLikes:    pb.MaxAll,
+	}
 
 	ctx, cancel := DefaultTimeoutContext()
 	defer cancel()
@@ -549,7 +789,7 @@ func (s *Server) LikeHandler(c *gin.Context) {
 		return
 	}
 
-	entry.FormatLikes(int32(0))
+	entry.FormatLikes(pb.MaxAuto)
 	c.JSON(200, entry.Likes)
 }
 
@@ -576,7 +816,7 @@ func (s *Server) LikeDeleteHandler(c *gin.Context) {
 		return
 	}
 
-	entry.FormatLikes(int32(0))
+	entry.FormatLikes(pb.MaxAuto)
 	c.JSON(200, entry.Likes)
 }
 
@@ -591,35 +831,17 @@ func (s *Server) CommentHandler(c *gin.Context) {
 		return
 	}
 
-	body := util.DefaultSanitize(rawBody)
-	body = util.EntityToLink(body)
-
 	profile, _ := s.CurrentUser(c)
-	from := &pb.Feed{
-		Id:   profile.Id,
-		Name: profile.Name,
-		Type: profile.Type,
-	}
-	comment := &pb.Comment{
-		Body:    body,
-		RawBody: rawBody,
-		From:    from,
-	}
-
-	var err error
-	var uuid1 uuid.UUID
+	comment := newComment(profile, entryId, rawBody)
 	if id != "" {
-		uuid1, err = uuid.FromString(id)
+		uuid1, err := uuid.FromString(id)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"status": "bad request"})
 			return
 		}
-	} else {
-		comment.Date = time.Now().UTC().Format(time.RFC3339)
-		name := entryId + profile.Uuid + comment.Date
-		uuid1 = uuid.NewV5(uuid.NamespaceURL, name)
+		comment.Id = uuid1.String()
+		comment.Date = ""
 	}
-	comment.Id = uuid1.String()
 
 	req := &pb.CommentRequest{
 		Entry:   entryId,
@@ -629,7 +851,7 @@ func (s *Server) CommentHandler(c *gin.Context) {
 	ctx, cancel := DefaultTimeoutContext()
 	defer cancel()
 
-	_, err = s.client.CommentEntry(ctx, req)
+	_, err := s.client.CommentEntry(ctx, req)
 	if RequestError(c, err) {
 		return
 	}
@@ -662,7 +884,7 @@ func (s *Server) CommentDeleteHandler(c *gin.Context) {
 		return
 	}
 
-	entry.FormatComments(int32(0))
+	entry.FormatComments(pb.MaxAuto)
 	entry.RebuildCommentsCommand(profile, graph)
 	c.JSON(200, entry.Comments)
 }
@@ -674,6 +896,9 @@ func (s *Server) PublicHandler(c *gin.Context) {
 		Start:    int32(start),
 		PageSize: 30,
 	}
+	if s.syndicate(c, req, "") {
+		return
+	}
 
 	profile, feed, err := s.FetchFeed(c, req)
 	if RequestError(c, err) {
@@ -698,6 +923,60 @@ func (s *Server) PublicHandler(c *gin.Context) {
 	s.renderFeed(c, data)
 }
 
+// /hashtag/:tag
+func (s *Server) HashtagHandler(c *gin.Context) {
+	tag := "#" + strings.TrimPrefix(c.Params.ByName("tag"), "#")
+	s.searchFeed(c, "public", tag, tag)
+}
+
+// /search?q=golang from:yinhm
+func (s *Server) SearchHandler(c *gin.Context) {
+	q := c.Request.URL.Query().Get("q")
+	feedId, query := parseSearchQuery(q)
+	if query == "" {
+		c.Redirect(http.StatusFound, "/public")
+		return
+	}
+	s.searchFeed(c, feedId, query, "Search: "+q)
+}
+
+func (s *Server) searchFeed(c *gin.Context, feedId, query, title string) {
+	start := ParseStart(c.Request)
+	req := &pb.FeedRequest{
+		Id:       feedId,
+		Start:    int32(start),
+		PageSize: 30,
+		Query:    query,
+	}
+	if s.syndicate(c, req, title) {
+		return
+	}
+
+	profile, feed, err := s.FetchFeed(c, req)
+	if RequestError(c, err) {
+		return
+	}
+	if feed.Private && !s.feedReadable(c, feed.Id) {
+		c.HTML(http.StatusForbidden, "403.html", pongo2.Context{})
+		return
+	}
+
+	prevStart := req.Start - req.PageSize
+	if prevStart < 0 {
+		prevStart = 0
+	}
+	data := pongo2.Context{
+		"show_share":  profile.Uuid != "",
+		"title":       title,
+		"name":        title,
+		"feed":        feed,
+		"prev_start":  prevStart,
+		"next_start":  req.Start + req.PageSize,
+		"show_paging": true,
+	}
+	s.renderFeed(c, data)
+}
+
 func RequestError(c *gin.Context, err error) bool {
 	if err != nil {
 		if grpc.Code(err) == codes.DeadlineExceeded {
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	pb "github.com/yinhm/friendfeed/proto"
+	"github.com/yinhm/friendfeed/sup"
+)
+
+// supURL returns url of sup feed.
+func supURL(c *gin.Context) string {
+	return baseURL(c) + "/sup.json"
+}
+
+// setSupHeader announces sup id of feed to consumers.
+func setSupHeader(c *gin.Context, feed *pb.Feed) {
+	if feed == nil || feed.SupId == "" {
+		return
+	}
+	c.Header(sup.Header, sup.Url(supURL(c), feed.SupId))
+}
+
+// GET /sup.json?period=60
+func (s *Server) SupHandler(c *gin.Context) {
+	period, _ := strconv.Atoi(c.Request.URL.Query().Get("period"))
+	period = sup.ValidPeriod(period)
+
+	now := time.Now()
+	req := &pb.SupRequest{Since: sup.Since(now, period).Unix()}
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	resp, err := s.client.FetchSupUpdates(ctx, req)
+	if RequestError(c, err) {
+		return
+	}
+
+	updates := make([]sup.Update, 0, len(resp.Updates))
+	for _, u := range resp.Updates {
+		updates = append(updates, sup.Update{SupId: u.SupId, Updated: time.Unix(u.Updated, 0)})
+	}
+	feed := sup.NewFeed(now, period, updates)
+	feed.SetAvailablePeriods(supURL(c) + "?period=%d")
+
+	c.Header("Expires", feed.Expires().UTC().Format(http.TimeFormat))
+	c.JSON(200, feed)
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/protobuf/proto"
+	pb "github.com/yinhm/friendfeed/proto"
+	"github.com/yinhm/friendfeed/sup"
+	"github.com/yinhm/friendfeed/syndication"
+)
+
+// baseURL returns site url of current request, eg: http://example.com
+func baseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.Request.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+// syndicate renders feed as atom or rss if asked by ?format=, returns false
+// if html page should be rendered instead. Feed name is replaced if name is
+// not empty.
+func (s *Server) syndicate(c *gin.Context, req proto.Message, name string) bool {
+	format := c.Request.URL.Query().Get("format")
+	if format != "atom" && format != "rss" {
+		return false
+	}
+
+	ctx, cancel := DefaultTimeoutContext()
+	defer cancel()
+
+	var feed *pb.Feed
+	var err error
+	switch req.(type) {
+	case *pb.FeedRequest:
+		freq := req.(*pb.FeedRequest)
+		freq.User = CurrentUserUuid(c)
+		feed, err = s.client.FetchFeed(ctx, freq)
+	case *pb.EntryRequest:
+		feed, err = s.client.FetchEntry(ctx, req.(*pb.EntryRequest))
+	}
+	if RequestError(c, err) {
+		return true
+	}
+	if feed.Private && !s.feedReadable(c, feed.Id) {
+		c.AbortWithStatus(http.StatusForbidden)
+		return true
+	}
+	if name != "" {
+		feed.Name = name
+	}
+
+	base := baseURL(c)
+	link := *c.Request.URL
+	query := link.Query()
+	query.Del("format")
+	link.RawQuery = query.Encode()
+	opt := &syndication.Options{
+		BaseURL: base,
+		Link:    base + link.RequestURI(),
+		Self:    base + c.Request.URL.RequestURI(),
+	}
+	// feeds advertise sup and websub hub, search results do not
+	if freq, ok := req.(*pb.FeedRequest); ok && freq.Query == "" {
+		if feed.SupId != "" {
+			opt.Sup = sup.Url(supURL(c), feed.SupId)
+			setSupHeader(c, feed)
+		}
+		if !feed.Private {
+			opt.Hub = hubURL(c)
+			setHubHeader(c, opt.Self)
+		}
+	}
+
+	var data []byte
+	contentType := syndication.ContentTypeAtom
+	if format == "rss" {
+		contentType = syndication.ContentTypeRSS
+		data, err = syndication.RSS(feed, opt)
+	} else {
+		data, err = syndication.Atom(feed, opt)
+	}
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return true
+	}
+	c.Data(200, contentType, data)
+	return true
+}
@@ -34,7 +34,10 @@ var Entry = React.createClass({
       new_comment_form: false,
       expanded_likes: false,
       expanded_comments: false,
-      comment_preserve: null
+      comment_preserve: null,
+      is_editing: false,
+      deleted: false,
+      hidden: false
     };
   },
 
@@ -161,6 +164,59 @@ var Entry = React.createClass({
     return null;
   },
 
+  editEntry: function() {
+    this.setState({is_editing: true});
+  },
+
+  submitEntry: function(id, body) {
+    var self = this;
+    var args = {
+      entry: this.state.entry.id,
+      body: body
+    };
+    $.postJSON("/a/entry/edit", args, function(entry) {
+      self.setState({
+        entry: entry,
+        is_editing: false
+      });
+    });
+  },
+
+  cancelEntry: function(id, body) {
+    this.setState({is_editing: false});
+  },
+
+  deleteEntry: function() {
+    if (!confirm("Delete this entry?")) {
+      return;
+    }
+    var self = this;
+    $.postJSON("/a/entry/delete", {entry: this.state.entry.id}, function(data) {
+      self.setState({deleted: true});
+    });
+  },
+
+  hideEntry: function() {
+    var self = this;
+    $.postJSON("/a/entry/hide", {entry: this.state.entry.id}, function(data) {
+      self.setState({hidden: true});
+    });
+  },
+
+  unhideEntry: function() {
+    var self = this;
+    var entry = this.state.entry;
+    $.postJSON("/a/entry/unhide", {entry: entry.id}, function(data) {
+      entry.commands.map(function(cmd, index) {
+        if (cmd == "unhide") {
+          entry.commands[index] = "hide";
+        }
+      });
+      entry.hidden = false;
+      self.setState({entry: entry});
+    });
+  },
+
   handleLike: function() {
     var self = this;
     var entry = this.state.entry;
@@ -189,6 +245,17 @@ var Entry = React.createClass({
 
   render: function() {
     var entry = this.state.entry;
+    if (this.state.deleted || this.state.hidden) {
+      return null;
+    }
+
+    var title = <EntryTitle body={entry.body} />;
+    if (this.state.is_editing) {
+      title = <EntryCommentForm commentId={entry.id}
+                                commentBody={entry.rawBody}
+                                onSubmitComment={this.submitEntry}
+                                onCancelComment={this.cancelEntry}/>;
+    }
 
     var medias = "";
     if (entry.thumbnails) {
@@ -231,9 +298,13 @@ var Entry = React.createClass({
         <EntryPicture feed={entry.from} />
         <div className="body">
           <EntryAuthor from={entry.from} to={entry.to} />
-          <EntryTitle body={entry.body} />
+          {title}
           {medias}
           <EntryInfo entry={entry}
+                     onEdit={this.editEntry}
+                     onDelete={this.deleteEntry}
+                     onHide={this.hideEntry}
+                     onUnhide={this.unhideEntry}
                      onNewComment={this.handleNewComment}
                      onLike={this.handleLike}
                      onUnlike={this.handleUnlike}/>
@@ -386,10 +457,16 @@ var EntryInfo = React.createClass({
             btn = <EntryCommandLike onUnlike={self.props.onUnlike} liked={liked} />;
             break;
           case "edit":
-            btn = <EntryCommandEdit />;
+            btn = <EntryCommandEdit onEdit={self.props.onEdit} />;
             break;
           case "delete":
-            btn = <EntryCommandDelete />;
+            btn = <EntryCommandDelete onDelete={self.props.onDelete} />;
+            break;
+          case "hide":
+            btn = <EntryCommandHide onClick={self.props.onHide} label="Hide" />;
+            break;
+          case "unhide":
+            btn = <EntryCommandHide onClick={self.props.onUnhide} label="Unhide" />;
             break;
           default:
             break;
@@ -456,17 +533,43 @@ var EntryCommandComment = React.createClass({
 });
 
 var EntryCommandEdit = React.createClass({
+
+  handleClick: function(event) {
+    event.preventDefault();
+    this.props.onEdit();
+  },
+
   render: function() {
     return (
-      <a href="#" className="editcommand">Edit</a>
+      <a href="#" className="editcommand" onClick={this.handleClick}>Edit</a>
     );
   }
 });
 
 var EntryCommandDelete = React.createClass({
+
+  handleClick: function(event) {
+    event.preventDefault();
+    this.props.onDelete();
+  },
+
+  render: function() {
+    return (
+      <a href="#" className="deletecommand" onClick={this.handleClick}>Delete</a>
+    );
+  }
+});
+
+var EntryCommandHide = React.createClass({
+
+  handleClick: function(event) {
+    event.preventDefault();
+    this.props.onClick();
+  },
+
   render: function() {
     return (
-      <a href="#" className="deletecommand">Delete</a>
+      <a href="#" className="hidecommand" onClick={this.handleClick}>{this.props.label}</a>
     );
   }
 });
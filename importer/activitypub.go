@@ -0,0 +1,443 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	ttext "github.com/cupcake/text-entities-go"
+	uuid "github.com/satori/go.uuid"
+	pb "github.com/yinhm/friendfeed/proto"
+)
+
+// MastodonServiceId is service id of activitypub accounts, eg: mastodon.
+// Service.Url is url of actor or its outbox.
+const MastodonServiceId = "mastodon"
+
+const (
+	activityContentType = "application/activity+json"
+	publicCollection    = "https://www.w3.org/ns/activitystreams#Public"
+	// pages of outbox fetched at most per job
+	maxOutboxPages = 10
+)
+
+func init() {
+	Register(&Source{
+		Id:       MastodonServiceId,
+		Interval: 15 * time.Minute,
+		Url:      true,
+		New:      func() Importer { return NewMastodonImporter() },
+	})
+}
+
+// Outbox is an ordered collection of activities, or a page of it.
+type Outbox struct {
+	Id           string          `json:"id"`
+	Type         string          `json:"type"`
+	Outbox       string          `json:"outbox"`
+	First        json.RawMessage `json:"first"`
+	Next         json.RawMessage `json:"next"`
+	OrderedItems []*Activity     `json:"orderedItems"`
+}
+
+// Activity is an activity of outbox, only Create of Note imported.
+type Activity struct {
+	Id     string          `json:"id"`
+	Type   string          `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+// Note is the object of Create activity.
+type Note struct {
+	Id         string          `json:"id"`
+	Type       string          `json:"type"`
+	Url        json.RawMessage `json:"url"`
+	Published  string          `json:"published"`
+	InReplyTo  json.RawMessage `json:"inReplyTo"`
+	Summary    string          `json:"summary"`
+	Content    string          `json:"content"`
+	To         json.RawMessage `json:"to"`
+	Cc         json.RawMessage `json:"cc"`
+	Attachment json.RawMessage `json:"attachment"`
+	Tag        json.RawMessage `json:"tag"`
+}
+
+type apAttachment struct {
+	Type      string          `json:"type"`
+	MediaType string          `json:"mediaType"`
+	Url       json.RawMessage `json:"url"`
+	Name      string          `json:"name"`
+	Width     int32           `json:"width"`
+	Height    int32           `json:"height"`
+}
+
+type apTag struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	Href string `json:"href"`
+}
+
+// textLink is a link of text[start:end].
+type textLink struct {
+	start, end int
+	href       string
+}
+
+// linkText returns html of text, urls and hashtags linked. Links are of
+// positions in text, links overlapped and links not of http never made.
+func linkText(text string, tags []apTag) string {
+	var links []textLink
+	for _, m := range ttext.ExtractURLMatches(text) {
+		if href := resolve(nil, m.Text); href != "" {
+			links = append(links, textLink{m.Indices[0], m.Indices[1], href})
+		}
+	}
+	hrefs := make(map[string]string)
+	for _, tag := range tags {
+		if tag.Type == "Hashtag" && tag.Name != "" {
+			hrefs[strings.ToLower(tag.Name)] = resolve(nil, tag.Href)
+		}
+	}
+	for _, m := range ttext.ExtractHashtagMatches(text) {
+		name := text[m.Indices[0]:m.Indices[1]]
+		if href := hrefs[strings.ToLower(name)]; href != "" {
+			links = append(links, textLink{m.Indices[0], m.Indices[1], href})
+		}
+	}
+	sort.SliceStable(links, func(i, j int) bool {
+		return links[i].start < links[j].start
+	})
+
+	var buf bytes.Buffer
+	pos := 0
+	for _, l := range links {
+		if l.start < pos {
+			continue
+		}
+		buf.WriteString(html.EscapeString(text[pos:l.start]))
+		fmt.Fprintf(&buf, "<a href=\"%s\">%s</a>", html.EscapeString(l.href), html.EscapeString(text[l.start:l.end]))
+		pos = l.end
+	}
+	buf.WriteString(html.EscapeString(text[pos:]))
+	return buf.String()
+}
+
+// ReadOutbox decodes outbox, eg: outbox.json of mastodon export.
+func ReadOutbox(r io.Reader) (*Outbox, error) {
+	outbox := new(Outbox)
+	if err := json.NewDecoder(r).Decode(outbox); err != nil {
+		return nil, err
+	}
+	return outbox, nil
+}
+
+// OpenOutbox reads exported outbox file.
+func OpenOutbox(name string) (*Outbox, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadOutbox(f)
+}
+
+// Notes returns public notes of Create activities, replies skipped.
+func (o *Outbox) Notes() []*Note {
+	var notes []*Note
+	for _, activity := range o.OrderedItems {
+		if activity.Type != "Create" {
+			continue
+		}
+		note := new(Note)
+		if err := json.Unmarshal(activity.Object, note); err != nil {
+			// object referenced by id only
+			continue
+		}
+		if note.Type != "Note" || note.Id == "" {
+			continue
+		}
+		if apString(note.InReplyTo) != "" || !note.Public() {
+			continue
+		}
+		notes = append(notes, note)
+	}
+	return notes
+}
+
+// Public reports whether note addressed to public, unlisted notes included.
+func (n *Note) Public() bool {
+	for _, to := range append(apStrings(n.To), apStrings(n.Cc)...) {
+		if to == publicCollection || to == "as:Public" || to == "Public" {
+			return true
+		}
+	}
+	return false
+}
+
+// Entry maps note to entry of profile. Entry id is uuid v5 of note id.
+func (n *Note) Entry(service *pb.Service, profile *pb.Profile) (*pb.Entry, error) {
+	published, err := time.Parse(time.RFC3339, n.Published)
+	if err != nil {
+		return nil, err
+	}
+	link := apString(n.Url)
+	if link == "" {
+		link = n.Id
+	}
+
+	text := noteText(n.Content)
+	if n.Summary != "" {
+		// content warning
+		text = "[" + noteText(n.Summary) + "] " + text
+	}
+	var tags []apTag
+	apUnmarshalList(n.Tag, &tags)
+	body := linkText(text, tags)
+
+	name := service.Name
+	if name == "" {
+		name = "Mastodon"
+	}
+	entry := &pb.Entry{
+		Id:      uuid.NewV5(uuid.NamespaceURL, n.Id).String(),
+		Url:     link,
+		Date:    published.UTC().Format(time.RFC3339),
+		Body:    body,
+		RawBody: text,
+		RawLink: link,
+		From:    from(profile),
+		Via: &pb.Via{
+			Name: name,
+			Url:  link,
+		},
+		ProfileUuid: profile.Uuid,
+	}
+
+	var attachments []apAttachment
+	apUnmarshalList(n.Attachment, &attachments)
+	for _, a := range attachments {
+		u := apString(a.Url)
+		if u == "" {
+			continue
+		}
+		if a.Type == "Image" || strings.HasPrefix(a.MediaType, "image/") {
+			entry.Thumbnails = append(entry.Thumbnails, &pb.Thumbnail{
+				Url:    u,
+				Link:   u,
+				Width:  a.Width,
+				Height: a.Height,
+			})
+			continue
+		}
+		name := a.Name
+		if name == "" || len(name) > 100 {
+			// name is alt text of media
+			name = fileName(u)
+		}
+		entry.Files = append(entry.Files, &pb.File{
+			Url:  u,
+			Type: a.MediaType,
+			Name: name,
+		})
+	}
+	return entry, nil
+}
+
+// MastodonImporter imports public notes of outbox at job.Service.Url, the
+// actor url is followed to its outbox.
+type MastodonImporter struct {
+	Client *http.Client
+}
+
+func NewMastodonImporter() *MastodonImporter {
+	return &MastodonImporter{Client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Import sends notes published since job.Service.Updated, oldest first.
+func (mi *MastodonImporter) Import(ctx context.Context, job *pb.FeedJob, send SendFunc) (int, error) {
+	if job.Service == nil || job.Service.Url == "" || job.Profile == nil {
+		return 0, fmt.Errorf("skip job: no outbox url")
+	}
+	updated := time.Unix(job.Service.Updated, 0)
+
+	doc, err := mi.get(ctx, job.Service.Url)
+	if err != nil {
+		return 0, err
+	}
+	if doc.Outbox != "" {
+		// actor
+		if doc, err = mi.get(ctx, doc.Outbox); err != nil {
+			return 0, err
+		}
+	}
+
+	// outbox newest first, pages fetched until notes older than updated
+	var notes []*Note
+	page := doc
+	if first := apString(doc.First); first != "" && len(doc.OrderedItems) == 0 {
+		if page, err = mi.page(ctx, doc.First); err != nil {
+			return 0, err
+		}
+	}
+	for i := 0; page != nil && i < maxOutboxPages; i++ {
+		older := false
+		for _, note := range page.Notes() {
+			published, err := time.Parse(time.RFC3339, note.Published)
+			if err != nil {
+				continue
+			}
+			if published.Before(updated) {
+				older = true
+				continue
+			}
+			notes = append(notes, note)
+		}
+		if older || apString(page.Next) == "" {
+			break
+		}
+		if page, err = mi.page(ctx, page.Next); err != nil {
+			return 0, err
+		}
+	}
+
+	return sendNotes(notes, job, send, true)
+}
+
+// page returns embedded page, or fetches it by url.
+func (mi *MastodonImporter) page(ctx context.Context, ref json.RawMessage) (*Outbox, error) {
+	page := new(Outbox)
+	if err := json.Unmarshal(ref, page); err == nil && page.Type != "" {
+		return page, nil
+	}
+	return mi.get(ctx, apString(ref))
+}
+
+func (mi *MastodonImporter) get(ctx context.Context, u string) (*Outbox, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", activityContentType+`, application/ld+json; profile="https://www.w3.org/ns/activitystreams"`)
+	resp, err := mi.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(u, resp)
+	}
+	return ReadOutbox(io.LimitReader(resp.Body, maxFeedSize))
+}
+
+// OutboxImporter imports all public notes of exported outbox.
+type OutboxImporter struct {
+	Outbox *Outbox
+}
+
+func NewOutboxImporter(outbox *Outbox) *OutboxImporter {
+	return &OutboxImporter{Outbox: outbox}
+}
+
+func (oi *OutboxImporter) Import(ctx context.Context, job *pb.FeedJob, send SendFunc) (int, error) {
+	if job.Profile == nil {
+		return 0, fmt.Errorf("skip job: no profile")
+	}
+	// mastodon exports oldest first
+	return sendNotes(oi.Outbox.Notes(), job, send, false)
+}
+
+// sendNotes sends notes as entries, in reverse order if reversed.
+func sendNotes(notes []*Note, job *pb.FeedJob, send SendFunc, reversed bool) (int, error) {
+	service := job.Service
+	if service == nil {
+		service = &pb.Service{Id: MastodonServiceId}
+	}
+	n := 0
+	for i := range notes {
+		note := notes[i]
+		if reversed {
+			note = notes[len(notes)-1-i]
+		}
+		entry, err := note.Entry(service, job.Profile)
+		if err != nil {
+			continue
+		}
+		if err := send(entry); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+var blockRe = regexp.MustCompile(`(?i)</p>|<br\s*/?>`)
+
+// noteText returns plain text of note content, inline tags of mentions and
+// hashtags removed without spaces.
+func noteText(content string) string {
+	content = blockRe.ReplaceAllString(content, " ")
+	content = html.UnescapeString(tagRe.ReplaceAllString(content, ""))
+	return strings.Join(strings.Fields(content), " ")
+}
+
+// apString returns string value, or id or href of object, or the first
+// one of array.
+func apString(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var obj struct {
+		Id   string `json:"id"`
+		Href string `json:"href"`
+	}
+	if err := json.Unmarshal(raw, &obj); err == nil {
+		return firstNonEmpty(obj.Href, obj.Id)
+	}
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err == nil && len(list) > 0 {
+		return apString(list[0])
+	}
+	return ""
+}
+
+// apStrings returns string values of single value or array.
+func apStrings(raw json.RawMessage) []string {
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err != nil {
+		if s := apString(raw); s != "" {
+			return []string{s}
+		}
+		return nil
+	}
+	var values []string
+	for _, item := range list {
+		if s := apString(item); s != "" {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// apUnmarshalList decodes single object or array of objects into list.
+func apUnmarshalList(raw json.RawMessage, list interface{}) {
+	if len(raw) == 0 {
+		return
+	}
+	if raw[0] != '[' {
+		raw = append(append(json.RawMessage{'['}, raw...), ']')
+	}
+	json.Unmarshal(raw, list)
+}
@@ -0,0 +1,185 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	. "github.com/smartystreets/goconvey/convey"
+	pb "github.com/yinhm/friendfeed/proto"
+)
+
+// exported outbox, oldest first
+const exportedOutbox = `{
+  "@context": "https://www.w3.org/ns/activitystreams",
+  "id": "outbox.json",
+  "type": "OrderedCollection",
+  "totalItems": 5,
+  "orderedItems": [{
+    "id": "https://social.example.com/users/yinhm/statuses/1/activity",
+    "type": "Create",
+    "object": {
+      "id": "https://social.example.com/users/yinhm/statuses/1",
+      "type": "Note",
+      "url": "https://social.example.com/@yinhm/1",
+      "published": "2022-11-01T10:00:00Z",
+      "inReplyTo": null,
+      "to": ["https://www.w3.org/ns/activitystreams#Public"],
+      "cc": ["https://social.example.com/users/yinhm/followers"],
+      "content": "<p>Hello <a href=\"https://social.example.com/tags/fediverse\" class=\"mention hashtag\" rel=\"tag\">#<span>fediverse</span></a> see https://example.com/a?b=1&amp;c=2</p>",
+      "tag": [{"type": "Hashtag", "href": "https://social.example.com/tags/fediverse", "name": "#fediverse"}],
+      "attachment": [
+        {"type": "Document", "mediaType": "image/png", "url": "https://files.example.com/1.png", "width": 640, "height": 480},
+        {"type": "Document", "mediaType": "video/mp4", "url": "https://files.example.com/2.mp4", "name": "cat"}
+      ]
+    }
+  }, {
+    "id": "https://social.example.com/users/yinhm/statuses/2/activity",
+    "type": "Create",
+    "object": {
+      "id": "https://social.example.com/users/yinhm/statuses/2",
+      "type": "Note",
+      "published": "2022-11-02T10:00:00Z",
+      "inReplyTo": "https://other.example.com/statuses/9",
+      "to": ["https://www.w3.org/ns/activitystreams#Public"],
+      "content": "<p>reply</p>"
+    }
+  }, {
+    "id": "https://social.example.com/users/yinhm/statuses/3/activity",
+    "type": "Create",
+    "object": {
+      "id": "https://social.example.com/users/yinhm/statuses/3",
+      "type": "Note",
+      "published": "2022-11-03T10:00:00Z",
+      "to": ["https://social.example.com/users/yinhm/followers"],
+      "content": "<p>followers only</p>"
+    }
+  }, {
+    "id": "https://social.example.com/users/yinhm/statuses/4/activity",
+    "type": "Announce",
+    "object": "https://other.example.com/statuses/10"
+  }, {
+    "id": "https://social.example.com/users/yinhm/statuses/5/activity",
+    "type": "Create",
+    "object": {
+      "id": "https://social.example.com/users/yinhm/statuses/5",
+      "type": "Note",
+      "published": "2022-11-05T10:00:00Z",
+      "to": "as:Public",
+      "summary": "spoiler",
+      "content": "<p>unlisted &lt;b&gt;</p>",
+      "attachment": {"type": "Image", "url": {"type": "Link", "href": "https://files.example.com/5.jpg"}}
+    }
+  }]
+}`
+
+func TestOutbox(t *testing.T) {
+	Convey("Given exported outbox, import public notes", t, func() {
+		outbox, err := ReadOutbox(strings.NewReader(exportedOutbox))
+		So(err, ShouldBeNil)
+		So(len(outbox.Notes()), ShouldEqual, 2)
+
+		profile := &pb.Profile{Uuid: "c6f8dca854f011ddb489003048343a40", Id: "yinhm", Name: "Heming", Type: "user"}
+		job := &pb.FeedJob{Id: profile.Id, Profile: profile}
+		var entries []*pb.Entry
+		send := func(entry *pb.Entry) error {
+			entries = append(entries, entry)
+			return nil
+		}
+		n, err := NewOutboxImporter(outbox).Import(context.Background(), job, send)
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 2)
+
+		entry := entries[0]
+		So(entry.Id, ShouldEqual, uuid.NewV5(uuid.NamespaceURL, "https://social.example.com/users/yinhm/statuses/1").String())
+		So(entry.Url, ShouldEqual, "https://social.example.com/@yinhm/1")
+		So(entry.Date, ShouldEqual, "2022-11-01T10:00:00Z")
+		So(entry.RawBody, ShouldEqual, "Hello #fediverse see https://example.com/a?b=1&c=2")
+		So(entry.Body, ShouldContainSubstring, `<a href="https://social.example.com/tags/fediverse">#fediverse</a>`)
+		So(entry.Body, ShouldContainSubstring, `<a href="https://example.com/a?b=1&amp;c=2">`)
+		So(entry.Via.Name, ShouldEqual, "Mastodon")
+		So(entry.ProfileUuid, ShouldEqual, profile.Uuid)
+		So(len(entry.Thumbnails), ShouldEqual, 1)
+		So(entry.Thumbnails[0].Width, ShouldEqual, 640)
+		So(len(entry.Files), ShouldEqual, 1)
+		So(entry.Files[0].Name, ShouldEqual, "cat")
+		So(entry.Files[0].Type, ShouldEqual, "video/mp4")
+
+		// links of text positions, never nested nor of javascript
+		body := linkText("#Go and #golang see http://golang.org/#go", []apTag{
+			{Type: "Hashtag", Name: "#go", Href: "https://social.example.com/tags/go"},
+			{Type: "Hashtag", Name: "#golang", Href: "javascript:alert(1)"},
+		})
+		So(body, ShouldEqual, `<a href="https://social.example.com/tags/go">#Go</a> and #golang see `+
+			`<a href="http://golang.org/#go">http://golang.org/#go</a>`)
+
+		unlisted := entries[1]
+		So(unlisted.RawBody, ShouldEqual, "[spoiler] unlisted <b>")
+		So(unlisted.Body, ShouldEqual, "[spoiler] unlisted &lt;b&gt;")
+		So(unlisted.Url, ShouldEqual, "https://social.example.com/users/yinhm/statuses/5")
+		So(unlisted.Thumbnails[0].Url, ShouldEqual, "https://files.example.com/5.jpg")
+	})
+}
+
+func TestMastodonImport(t *testing.T) {
+	Convey("Given actor url, import notes of outbox pages since updated", t, func() {
+		note := func(id, published string) string {
+			return fmt.Sprintf(`{"type": "Create", "object": {"id": "%s/notes/%s", "type": "Note",
+				"published": "%s", "to": ["https://www.w3.org/ns/activitystreams#Public"],
+				"content": "<p>note %s</p>"}}`, "http://social.example.com", id, published, id)
+		}
+		var accepts []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			accepts = append(accepts, r.Header.Get("Accept"))
+			w.Header().Set("Content-Type", activityContentType)
+			base := "http://" + r.Host
+			switch r.URL.Path + "?" + r.URL.RawQuery {
+			case "/users/yinhm?":
+				fmt.Fprintf(w, `{"type": "Person", "id": "%s/users/yinhm", "outbox": "%s/users/yinhm/outbox"}`, base, base)
+			case "/users/yinhm/outbox?":
+				fmt.Fprintf(w, `{"type": "OrderedCollection", "first": "%s/users/yinhm/outbox?page=1"}`, base)
+			case "/users/yinhm/outbox?page=1":
+				fmt.Fprintf(w, `{"type": "OrderedCollectionPage", "next": "%s/users/yinhm/outbox?page=2",
+					"orderedItems": [%s, %s]}`, base, note("4", "2022-11-04T10:00:00Z"), note("3", "2022-11-03T10:00:00Z"))
+			case "/users/yinhm/outbox?page=2":
+				fmt.Fprintf(w, `{"type": "OrderedCollectionPage", "next": "%s/users/yinhm/outbox?page=3",
+					"orderedItems": [%s, %s]}`, base, note("2", "2022-11-02T10:00:00Z"), note("1", "2022-11-01T10:00:00Z"))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer ts.Close()
+
+		profile := &pb.Profile{Uuid: "c6f8dca854f011ddb489003048343a40", Id: "yinhm", Name: "Heming", Type: "user"}
+		service := &pb.Service{
+			Id:      MastodonServiceId,
+			Name:    "social.example.com",
+			Url:     ts.URL + "/users/yinhm",
+			Updated: time.Date(2022, 11, 2, 0, 0, 0, 0, time.UTC).Unix(),
+		}
+		job := &pb.FeedJob{Id: profile.Id, Profile: profile, Service: service}
+		var entries []*pb.Entry
+		send := func(entry *pb.Entry) error {
+			entries = append(entries, entry)
+			return nil
+		}
+		n, err := Import(context.Background(), job, send)
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 3)
+		So(entries[0].RawBody, ShouldEqual, "note 2")
+		So(entries[2].RawBody, ShouldEqual, "note 4")
+		So(entries[2].Via.Name, ShouldEqual, "social.example.com")
+		// page 3 not fetched, older notes reached
+		So(len(accepts), ShouldEqual, 4)
+		So(accepts[0], ShouldStartWith, activityContentType)
+
+		service.Url = ts.URL + "/users/nobody"
+		_, err = Import(context.Background(), job, send)
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,83 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ChimeraCoder/anaconda"
+	"github.com/yinhm/friendfeed/ff"
+)
+
+// StatusError is returned when remote service responds status not ok.
+type StatusError struct {
+	Url        string
+	StatusCode int
+	Status     string
+	// suggested by Retry-After header, zero if not given
+	RetryAfter time.Duration
+}
+
+func newStatusError(u string, resp *http.Response) *StatusError {
+	return &StatusError{
+		Url:        u,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		RetryAfter: retryAfter(resp.Header),
+	}
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("importer: %s returned %s", e.Url, e.Status)
+}
+
+// Throttled reports whether err is rate limited or a server error of remote
+// service, which should be retried later. Wait suggested by service returned
+// if any.
+func Throttled(err error) (time.Duration, bool) {
+	switch e := err.(type) {
+	case *StatusError:
+		return e.RetryAfter, throttledStatus(e.StatusCode)
+	case *ff.ErrorResponse:
+		return e.RetryAfter, e.Temporary()
+	case *anaconda.ApiError:
+		return twitterRetryAfter(e), throttledStatus(e.StatusCode)
+	case anaconda.ApiError:
+		return twitterRetryAfter(&e), throttledStatus(e.StatusCode)
+	}
+	return 0, false
+}
+
+// Permanent reports whether job failed by err would fail again, eg: feed
+// private or removed, wrong remote key.
+func Permanent(err error) bool {
+	return errors.Is(err, ff.ErrNotFound) || errors.Is(err, ff.ErrPrivate) || errors.Is(err, ff.ErrAuth)
+}
+
+func throttledStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// retryAfter parses Retry-After header in seconds or http date.
+func retryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(time.Now()) {
+		return time.Until(t)
+	}
+	return 0
+}
+
+func twitterRetryAfter(e *anaconda.ApiError) time.Duration {
+	if limited, next := e.RateLimitCheck(); limited {
+		return time.Until(next)
+	}
+	return 0
+}
@@ -0,0 +1,505 @@
+package importer
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	pb "github.com/yinhm/friendfeed/proto"
+	"github.com/yinhm/friendfeed/sup"
+	"github.com/yinhm/friendfeed/util"
+	"golang.org/x/net/html/charset"
+)
+
+// FeedServiceId is service id of generic rss/atom services.
+const FeedServiceId = "feed"
+
+const maxFeedSize = 10 << 20
+
+func init() {
+	Register(&Source{
+		Id:       FeedServiceId,
+		Interval: 30 * time.Minute,
+		Url:      true,
+		New:      func() Importer { return NewFeedImporter() },
+	})
+}
+
+// FeedImporter imports items of rss 2.0 or atom 1.0 feed at job.Service.Url.
+type FeedImporter struct {
+	Client *http.Client
+}
+
+func NewFeedImporter() *FeedImporter {
+	return &FeedImporter{Client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Feed is a parsed rss or atom feed.
+type Feed struct {
+	Title string
+	// html page of the feed
+	Link string
+	// websub hub and topic, if advertised
+	Hub  string
+	Self string
+	// sup url of the feed with sup id, if advertised by X-SUP-ID header or
+	// sup link
+	Sup   string
+	Items []*Item
+}
+
+// Item is a rss item or atom entry.
+type Item struct {
+	Id        string
+	Title     string
+	Link      string
+	Content   string
+	Published time.Time
+
+	Thumbnails []*pb.Thumbnail
+	Files      []*pb.File
+}
+
+// Fetch fetches and parses feed.
+func (fi *FeedImporter) Fetch(ctx context.Context, feedUrl string) (*Feed, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", feedUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/atom+xml, application/rss+xml, application/xml;q=0.9, */*;q=0.8")
+	resp, err := fi.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(feedUrl, resp)
+	}
+	feed, err := ParseFeed(io.LimitReader(resp.Body, maxFeedSize), feedUrl)
+	if err != nil {
+		return nil, err
+	}
+	if supUrl := resp.Header.Get(sup.Header); supUrl != "" {
+		base, _ := url.Parse(feedUrl)
+		feed.Sup = resolve(base, supUrl)
+	}
+	return feed, nil
+}
+
+// Import sends items published since job.Service.Updated, items without date
+// only sent if job.Service.Updated is zero.
+func (fi *FeedImporter) Import(ctx context.Context, job *pb.FeedJob, send SendFunc) (int, error) {
+	if job.Service == nil || job.Service.Url == "" || job.Profile == nil {
+		return 0, fmt.Errorf("skip job: no feed url")
+	}
+	feed, err := fi.Fetch(ctx, job.Service.Url)
+	if err != nil {
+		return 0, err
+	}
+
+	updated := time.Unix(job.Service.Updated, 0)
+	n := 0
+	for _, item := range feed.Items {
+		if item.Published.IsZero() {
+			if job.Service.Updated > 0 {
+				continue
+			}
+		} else if item.Published.Before(updated) {
+			continue
+		}
+		entry := feed.Entry(item, job.Service, job.Profile)
+		if err := send(entry); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// Entry maps item to entry of profile. Entry id is uuid v5 of item id, the
+// same item always maps to the same entry, see entryId.
+func (f *Feed) Entry(item *Item, service *pb.Service, profile *pb.Profile) *pb.Entry {
+	title := item.Title
+	if title == "" {
+		title = truncate(plainText(item.Content), 140)
+	}
+	body := html.EscapeString(title)
+	if item.Link != "" {
+		body = fmt.Sprintf("<a rel=\"nofollow\" href=\"%s\">%s</a>", html.EscapeString(item.Link), body)
+	}
+	body = util.DefaultSanitize(body)
+
+	date := item.Published
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	via := &pb.Via{Name: service.Name, Url: f.Link}
+	if via.Name == "" {
+		via.Name = f.Title
+	}
+	if via.Url == "" {
+		via.Url = service.Url
+	}
+
+	return &pb.Entry{
+		Id:          entryId(profile, service, item.Id),
+		Url:         item.Link,
+		Date:        date.UTC().Format(time.RFC3339),
+		Body:        body,
+		RawBody:     title,
+		RawLink:     item.Link,
+		From:        from(profile),
+		Thumbnails:  item.Thumbnails,
+		Files:       item.Files,
+		Via:         via,
+		ProfileUuid: profile.Uuid,
+	}
+}
+
+// entryId returns uuid v5 of item id of service in namespace of profile, so
+// that guids only unique within a feed never collide, and users importing
+// the same feed own their entries.
+func entryId(profile *pb.Profile, service *pb.Service, itemId string) string {
+	ns, err := uuid.FromString(profile.Uuid)
+	if err != nil {
+		ns = uuid.NamespaceURL
+	}
+	return uuid.NewV5(ns, service.Url+"#"+itemId).String()
+}
+
+type xmlLink struct {
+	Rel    string `xml:"rel,attr"`
+	Href   string `xml:"href,attr"`
+	Type   string `xml:"type,attr"`
+	Title  string `xml:"title,attr"`
+	Length string `xml:"length,attr"`
+	Text   string `xml:",chardata"`
+}
+
+type xmlMedia struct {
+	Url      string `xml:"url,attr"`
+	Type     string `xml:"type,attr"`
+	Medium   string `xml:"medium,attr"`
+	FileSize string `xml:"fileSize,attr"`
+	Width    string `xml:"width,attr"`
+	Height   string `xml:"height,attr"`
+}
+
+type xmlEnclosure struct {
+	Url    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+type xmlMediaGroup struct {
+	Contents   []xmlMedia `xml:"http://search.yahoo.com/mrss/ content"`
+	Thumbnails []xmlMedia `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+type xmlItem struct {
+	// rss
+	Guid        string         `xml:"guid"`
+	PubDate     string         `xml:"pubDate"`
+	Description string         `xml:"description"`
+	Encoded     string         `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Date        string         `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Enclosures  []xmlEnclosure `xml:"enclosure"`
+	// atom, content of type xhtml not supported
+	Id        string    `xml:"http://www.w3.org/2005/Atom id"`
+	Published string    `xml:"http://www.w3.org/2005/Atom published"`
+	Updated   string    `xml:"http://www.w3.org/2005/Atom updated"`
+	Summary   string    `xml:"http://www.w3.org/2005/Atom summary"`
+	Content   string    `xml:"http://www.w3.org/2005/Atom content"`
+	Links     []xmlLink `xml:"link"`
+	// both
+	Title      string          `xml:"title"`
+	Contents   []xmlMedia      `xml:"http://search.yahoo.com/mrss/ content"`
+	Thumbnails []xmlMedia      `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	Groups     []xmlMediaGroup `xml:"http://search.yahoo.com/mrss/ group"`
+}
+
+type xmlFeed struct {
+	XMLName xml.Name
+	// rss
+	Channel struct {
+		Title string `xml:"title"`
+		// atom:link must be matched before rss link
+		AtomLinks []xmlLink `xml:"http://www.w3.org/2005/Atom link"`
+		Links     []xmlLink `xml:"link"`
+		Items     []xmlItem `xml:"item"`
+	} `xml:"channel"`
+	// atom
+	Title   string    `xml:"title"`
+	Links   []xmlLink `xml:"link"`
+	Entries []xmlItem `xml:"entry"`
+}
+
+// ParseFeed parses rss 2.0 or atom 1.0 feed, items sorted oldest first.
+// Relative urls are resolved against feedUrl.
+func ParseFeed(r io.Reader, feedUrl string) (*Feed, error) {
+	doc := new(xmlFeed)
+	dec := xml.NewDecoder(r)
+	dec.CharsetReader = charset.NewReaderLabel
+	dec.Strict = false
+	if err := dec.Decode(doc); err != nil {
+		return nil, err
+	}
+
+	base, _ := url.Parse(feedUrl)
+	feed := new(Feed)
+	var items []xmlItem
+	var links []xmlLink
+	switch doc.XMLName.Local {
+	case "rss":
+		feed.Title = doc.Channel.Title
+		items = doc.Channel.Items
+		links = doc.Channel.AtomLinks
+		for _, link := range doc.Channel.Links {
+			if link.Text != "" && link.Href == "" {
+				feed.Link = resolve(base, link.Text)
+			}
+		}
+	case "feed":
+		feed.Title = doc.Title
+		items = doc.Entries
+		links = doc.Links
+	default:
+		return nil, fmt.Errorf("importer: unknown feed format %q", doc.XMLName.Local)
+	}
+	for _, link := range links {
+		switch link.Rel {
+		case "hub":
+			feed.Hub = resolve(base, link.Href)
+		case "self":
+			feed.Self = resolve(base, link.Href)
+		case sup.Rel:
+			feed.Sup = resolve(base, link.Href)
+		case "", "alternate":
+			if feed.Link == "" {
+				feed.Link = resolve(base, link.Href)
+			}
+		}
+	}
+	feed.Title = strings.TrimSpace(html.UnescapeString(feed.Title))
+
+	for i := range items {
+		if item := parseItem(base, &items[i]); item != nil {
+			feed.Items = append(feed.Items, item)
+		}
+	}
+	sort.SliceStable(feed.Items, func(i, j int) bool {
+		return feed.Items[i].Published.Before(feed.Items[j].Published)
+	})
+	return feed, nil
+}
+
+func parseItem(base *url.URL, x *xmlItem) *Item {
+	item := &Item{
+		Title:   plainText(x.Title),
+		Content: firstNonEmpty(x.Encoded, x.Content, x.Description, x.Summary),
+	}
+
+	for _, link := range x.Links {
+		href := link.Href
+		if href == "" {
+			// rss <link>url</link>
+			href = strings.TrimSpace(link.Text)
+		}
+		if href == "" {
+			continue
+		}
+		switch link.Rel {
+		case "", "alternate":
+			if item.Link == "" {
+				item.Link = resolve(base, href)
+			}
+		case "enclosure":
+			size, _ := strconv.Atoi(link.Length)
+			item.addMedia(resolve(base, href), link.Type, "", int32(size), 0, 0)
+		}
+	}
+
+	item.Id = strings.TrimSpace(firstNonEmpty(x.Guid, x.Id, item.Link))
+	if item.Id == "" {
+		if item.Title == "" {
+			return nil
+		}
+		item.Id = base.String() + "#" + item.Title
+	}
+	if item.Link == "" && strings.HasPrefix(item.Id, "http") {
+		item.Link = resolve(nil, item.Id)
+	}
+
+	item.Published = parseTime(firstNonEmpty(x.Published, x.PubDate, x.Date, x.Updated))
+
+	for _, e := range x.Enclosures {
+		size, _ := strconv.Atoi(e.Length)
+		item.addMedia(resolve(base, e.Url), e.Type, "", int32(size), 0, 0)
+	}
+	contents, thumbnails := x.Contents, x.Thumbnails
+	for _, g := range x.Groups {
+		contents = append(contents, g.Contents...)
+		thumbnails = append(thumbnails, g.Thumbnails...)
+	}
+	for _, m := range thumbnails {
+		item.addThumbnail(resolve(base, m.Url), item.Link, atoi32(m.Width), atoi32(m.Height))
+	}
+	for _, m := range contents {
+		item.addMedia(resolve(base, m.Url), m.Type, m.Medium, atoi32(m.FileSize), atoi32(m.Width), atoi32(m.Height))
+	}
+	if len(item.Thumbnails) == 0 {
+		if src := firstImage(item.Content); src != "" {
+			item.addThumbnail(resolve(base, src), item.Link, 0, 0)
+		}
+	}
+	return item
+}
+
+// addMedia adds image as thumbnail, others as file.
+func (item *Item) addMedia(u, mimeType, medium string, size, width, height int32) {
+	if u == "" {
+		return
+	}
+	if medium == "image" || strings.HasPrefix(mimeType, "image/") {
+		item.addThumbnail(u, u, width, height)
+		return
+	}
+	for _, f := range item.Files {
+		if f.Url == u {
+			return
+		}
+	}
+	item.Files = append(item.Files, &pb.File{
+		Url:  u,
+		Type: mimeType,
+		Name: fileName(u),
+		Size: size,
+	})
+}
+
+func (item *Item) addThumbnail(u, link string, width, height int32) {
+	if u == "" {
+		return
+	}
+	for _, t := range item.Thumbnails {
+		if t.Url == u {
+			return
+		}
+	}
+	if link == "" {
+		link = u
+	}
+	item.Thumbnails = append(item.Thumbnails, &pb.Thumbnail{
+		Url:    u,
+		Link:   link,
+		Width:  width,
+		Height: height,
+	})
+}
+
+var timeLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"Mon, 02 Jan 2006 15:04 -0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// parseTime returns zero time if not parsed.
+func parseTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+// resolve returns ref resolved against base, empty unless an http or https
+// url, links of feeds rendered into entries as is.
+func resolve(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return u.String()
+}
+
+var (
+	tagRe = regexp.MustCompile(`<[^>]*>`)
+	imgRe = regexp.MustCompile(`(?i)<img[^>]+src\s*=\s*["']([^"']+)["']`)
+)
+
+func plainText(s string) string {
+	s = html.UnescapeString(tagRe.ReplaceAllString(s, " "))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func firstImage(content string) string {
+	m := imgRe.FindStringSubmatch(content)
+	if m == nil {
+		return ""
+	}
+	return html.UnescapeString(m[1])
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
+
+func fileName(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return ""
+	}
+	name := path.Base(parsed.Path)
+	if name == "/" || name == "." {
+		return ""
+	}
+	return name
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func atoi32(s string) int32 {
+	n, _ := strconv.Atoi(s)
+	return int32(n)
+}
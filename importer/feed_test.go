@@ -0,0 +1,227 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	. "github.com/smartystreets/goconvey/convey"
+	pb "github.com/yinhm/friendfeed/proto"
+)
+
+const rssFeed = `<?xml version="1.0" encoding="ISO-8859-1"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom"
+  xmlns:media="http://search.yahoo.com/mrss/"
+  xmlns:content="http://purl.org/rss/1.0/modules/content/">
+<channel>
+  <title>Caf&#233; blog</title>
+  <link>http://blog.example.com/</link>
+  <atom:link rel="self" href="http://blog.example.com/feed.xml" />
+  <atom:link rel="hub" href="http://hub.example.com/" />
+  <item>
+    <title>Second post</title>
+    <link>http://blog.example.com/2</link>
+    <guid>http://blog.example.com/2</guid>
+    <pubDate>Tue, 02 Jun 2015 10:00:00 +0000</pubDate>
+    <description>&lt;p&gt;Hello &lt;img src="/images/2.jpg"&gt;&lt;/p&gt;</description>
+    <enclosure url="http://blog.example.com/2.mp3" type="audio/mpeg" length="1024" />
+  </item>
+  <item>
+    <link>http://blog.example.com/1</link>
+    <pubDate>Mon, 01 Jun 2015 10:00:00 +0000</pubDate>
+    <content:encoded>&lt;p&gt;First &amp;amp; <![CDATA[post]]>&lt;/p&gt;</content:encoded>
+    <media:content url="http://blog.example.com/1.jpg" medium="image" width="640" height="480" />
+  </item>
+</channel>
+</rss>`
+
+const atomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Photos</title>
+  <link rel="alternate" href="/photos" />
+  <link rel="self" href="/photos.atom" />
+  <link rel="http://api.friendfeed.com/2008/03#sup" href="/sup.json#4ceb94af" />
+  <entry>
+    <id>tag:photos.example.com,2015:1</id>
+    <title>Sunset</title>
+    <link rel="alternate" href="/photos/1" />
+    <link rel="enclosure" href="/photos/1.jpg" type="image/jpeg" />
+    <published>2015-06-03T10:00:00Z</published>
+    <updated>2015-06-04T10:00:00Z</updated>
+    <content type="html">&lt;b&gt;sunset&lt;/b&gt;</content>
+  </entry>
+  <entry>
+    <title>Undated</title>
+    <link href="http://photos.example.com/photos/0" />
+  </entry>
+</feed>`
+
+func TestParseFeed(t *testing.T) {
+	Convey("Given rss feed, parse items oldest first", t, func() {
+		feed, err := ParseFeed(strings.NewReader(rssFeed), "http://blog.example.com/feed.xml")
+		So(err, ShouldBeNil)
+		So(feed.Title, ShouldEqual, "Café blog")
+		So(feed.Link, ShouldEqual, "http://blog.example.com/")
+		So(feed.Self, ShouldEqual, "http://blog.example.com/feed.xml")
+		So(feed.Hub, ShouldEqual, "http://hub.example.com/")
+		So(feed.Sup, ShouldEqual, "")
+		So(len(feed.Items), ShouldEqual, 2)
+
+		first := feed.Items[0]
+		So(first.Id, ShouldEqual, "http://blog.example.com/1")
+		So(first.Title, ShouldEqual, "")
+		So(first.Content, ShouldContainSubstring, "First &amp; post")
+		So(len(first.Thumbnails), ShouldEqual, 1)
+		So(first.Thumbnails[0].Width, ShouldEqual, 640)
+		So(first.Thumbnails[0].Link, ShouldEqual, "http://blog.example.com/1.jpg")
+
+		second := feed.Items[1]
+		So(second.Title, ShouldEqual, "Second post")
+		So(second.Published, ShouldEqual, time.Date(2015, 6, 2, 10, 0, 0, 0, time.UTC))
+		So(len(second.Files), ShouldEqual, 1)
+		So(second.Files[0].Name, ShouldEqual, "2.mp3")
+		So(second.Files[0].Size, ShouldEqual, 1024)
+		So(len(second.Thumbnails), ShouldEqual, 1)
+		So(second.Thumbnails[0].Url, ShouldEqual, "http://blog.example.com/images/2.jpg")
+		So(second.Thumbnails[0].Link, ShouldEqual, "http://blog.example.com/2")
+	})
+
+	Convey("Given atom feed, resolve relative links", t, func() {
+		feed, err := ParseFeed(strings.NewReader(atomFeed), "http://photos.example.com/photos.atom")
+		So(err, ShouldBeNil)
+		So(feed.Title, ShouldEqual, "Photos")
+		So(feed.Link, ShouldEqual, "http://photos.example.com/photos")
+		So(feed.Hub, ShouldEqual, "")
+		So(feed.Sup, ShouldEqual, "http://photos.example.com/sup.json#4ceb94af")
+		So(len(feed.Items), ShouldEqual, 2)
+
+		undated := feed.Items[0]
+		So(undated.Published.IsZero(), ShouldBeTrue)
+		So(undated.Id, ShouldEqual, "http://photos.example.com/photos/0")
+
+		item := feed.Items[1]
+		So(item.Id, ShouldEqual, "tag:photos.example.com,2015:1")
+		So(item.Link, ShouldEqual, "http://photos.example.com/photos/1")
+		So(item.Published, ShouldEqual, time.Date(2015, 6, 3, 10, 0, 0, 0, time.UTC))
+		So(item.Content, ShouldEqual, "<b>sunset</b>")
+		So(len(item.Files), ShouldEqual, 0)
+		So(len(item.Thumbnails), ShouldEqual, 1)
+		So(item.Thumbnails[0].Url, ShouldEqual, "http://photos.example.com/photos/1.jpg")
+	})
+
+	Convey("Given links not of http, never linked", t, func() {
+		evil := `<rss version="2.0"><channel><title>evil</title>
+  <item>
+    <title>hi</title>
+    <link>javascript:alert(document.cookie)</link>
+    <guid>1</guid>
+    <enclosure url="javascript:alert(1)" type="image/png" />
+  </item>
+</channel></rss>`
+		feed, err := ParseFeed(strings.NewReader(evil), "http://evil.example.com/feed.xml")
+		So(err, ShouldBeNil)
+		So(len(feed.Items), ShouldEqual, 1)
+		item := feed.Items[0]
+		So(item.Link, ShouldEqual, "")
+		So(len(item.Thumbnails), ShouldEqual, 0)
+
+		item.Link = "javascript:alert(document.cookie)"
+		entry := feed.Entry(item, &pb.Service{Url: "http://evil.example.com/feed.xml"}, &pb.Profile{Id: "yinhm"})
+		So(entry.Body, ShouldNotContainSubstring, "javascript:")
+		So(entry.Body, ShouldContainSubstring, "hi")
+	})
+
+	Convey("Given unknown document, parse failed", t, func() {
+		_, err := ParseFeed(strings.NewReader("<html></html>"), "http://example.com/")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestFeedImport(t *testing.T) {
+	Convey("Given feed service, import items as entries", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/feed.xml" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "application/rss+xml")
+			w.Write([]byte(rssFeed))
+		}))
+		defer ts.Close()
+
+		profile := &pb.Profile{Uuid: "c6f8dca854f011ddb489003048343a40", Id: "yinhm", Name: "Heming", Type: "user"}
+		service := &pb.Service{Id: FeedServiceId, Name: "My blog", Url: ts.URL + "/feed.xml"}
+		job := &pb.FeedJob{Id: profile.Id, Profile: profile, Service: service}
+
+		var entries []*pb.Entry
+		send := func(entry *pb.Entry) error {
+			entries = append(entries, entry)
+			return nil
+		}
+		n, err := NewFeedImporter().Import(context.Background(), job, send)
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 2)
+		So(len(entries), ShouldEqual, 2)
+
+		entry := entries[1]
+		ns, _ := uuid.FromString(profile.Uuid)
+		So(entry.Id, ShouldEqual, uuid.NewV5(ns, service.Url+"#http://blog.example.com/2").String())
+		// the same item of another feed, or imported by another user
+		So(entryId(profile, &pb.Service{Url: ts.URL + "/other.xml"}, "http://blog.example.com/2"), ShouldNotEqual, entry.Id)
+		other := &pb.Profile{Uuid: "0a6c8a2ad0c04b3a9f1f3d5a6b7c8d94"}
+		So(entryId(other, service, "http://blog.example.com/2"), ShouldNotEqual, entry.Id)
+		So(entry.Url, ShouldEqual, "http://blog.example.com/2")
+		So(entry.Date, ShouldEqual, "2015-06-02T10:00:00Z")
+		So(entry.Body, ShouldEqual, `<a href="http://blog.example.com/2" rel="nofollow">Second post</a>`)
+		So(entry.RawBody, ShouldEqual, "Second post")
+		So(entry.From.Id, ShouldEqual, "yinhm")
+		So(entry.ProfileUuid, ShouldEqual, profile.Uuid)
+		So(entry.Via.Name, ShouldEqual, "My blog")
+		So(entry.Via.Url, ShouldEqual, "http://blog.example.com/")
+
+		// untitled item summarized from content
+		So(entries[0].RawBody, ShouldEqual, "First & post")
+
+		// items since service updated
+		service.Updated = time.Date(2015, 6, 2, 0, 0, 0, 0, time.UTC).Unix()
+		entries = nil
+		n, err = NewFeedImporter().Import(context.Background(), job, send)
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 1)
+		So(entries[0].RawBody, ShouldEqual, "Second post")
+
+		service.Url = ts.URL + "/missing.xml"
+		_, err = NewFeedImporter().Import(context.Background(), job, send)
+		So(err, ShouldNotBeNil)
+		_, throttled := Throttled(err)
+		So(throttled, ShouldBeFalse)
+	})
+}
+
+func TestThrottled(t *testing.T) {
+	Convey("Given rate limited feed, wait as suggested by Retry-After", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer ts.Close()
+
+		profile := &pb.Profile{Uuid: "c6f8dca854f011ddb489003048343a40", Id: "yinhm"}
+		job := &pb.FeedJob{Id: profile.Id, Profile: profile, Service: &pb.Service{Id: FeedServiceId, Url: ts.URL}}
+		_, err := NewFeedImporter().Import(context.Background(), job, func(*pb.Entry) error { return nil })
+		wait, throttled := Throttled(err)
+		So(throttled, ShouldBeTrue)
+		So(wait, ShouldEqual, 2*time.Minute)
+
+		wait, throttled = Throttled(&StatusError{StatusCode: http.StatusBadGateway})
+		So(throttled, ShouldBeTrue)
+		So(wait, ShouldEqual, 0)
+		_, throttled = Throttled(fmt.Errorf("503"))
+		So(throttled, ShouldBeFalse)
+	})
+}
@@ -0,0 +1,95 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/yinhm/friendfeed/ff"
+	pb "github.com/yinhm/friendfeed/proto"
+)
+
+const (
+	// entries per page of friendfeed v2 api at most
+	friendfeedPageSize = 100
+	friendfeedTimeout  = 60 * time.Second
+)
+
+// CheckpointFunc saves progress of job, job.Start is the index of the next
+// page to fetch.
+type CheckpointFunc func(job *pb.FeedJob) error
+
+// FriendFeedArchiver archives feed job.Id of friendfeed v2 api page by page,
+// from job.Start. Progress checkpointed after every page so that an
+// interrupted job resumes where it stopped.
+//
+// job.PageSize is entries per page, job.MaxLimit is max pages to fetch, zero
+// for no limit. Entries saved into feed of job.Uuid.
+type FriendFeedArchiver struct {
+	// Client of friendfeed api, authed by job.Id and job.RemoteKey if nil.
+	Client     *ff.Client
+	Checkpoint CheckpointFunc
+	// Wait blocks until next page may be fetched, eg: rate limited by agent.
+	// Not called for the first page.
+	Wait func(ctx context.Context) error
+}
+
+func NewFriendFeedArchiver(checkpoint CheckpointFunc) *FriendFeedArchiver {
+	return &FriendFeedArchiver{Checkpoint: checkpoint}
+}
+
+// Import sends entries newest first, as paged by friendfeed.
+func (fa *FriendFeedArchiver) Import(ctx context.Context, job *pb.FeedJob, send SendFunc) (int, error) {
+	if job.Id == "" || job.Uuid == "" {
+		return 0, fmt.Errorf("skip job: no feed id or uuid")
+	}
+	client := fa.Client
+	if client == nil {
+		client = ff.NewClient(&http.Client{Timeout: friendfeedTimeout}, job.Id, job.RemoteKey)
+	}
+	pageSize := int(job.PageSize)
+	if pageSize <= 0 || pageSize > friendfeedPageSize {
+		pageSize = friendfeedPageSize
+	}
+
+	it := client.FeedPages(ctx, job.Id, &ff.PageOptions{
+		FeedOptions: ff.FeedOptions{
+			Start:   int(job.Start),
+			Num:     pageSize,
+			RawBody: 1,
+		},
+	})
+	n := 0
+	for page := 0; job.MaxLimit <= 0 || page < int(job.MaxLimit); page++ {
+		if err := ctx.Err(); err != nil {
+			return n, err
+		}
+		if page > 0 && fa.Wait != nil {
+			if err := fa.Wait(ctx); err != nil {
+				return n, err
+			}
+		}
+		if !it.NextPage() {
+			break
+		}
+		for _, entry := range it.Page() {
+			entry.ProfileUuid = job.Uuid
+			if err := send(entry); err != nil {
+				return n, err
+			}
+			n++
+		}
+
+		job.Start = int32(it.Start())
+		if fa.Checkpoint != nil {
+			if err := fa.Checkpoint(job); err != nil {
+				return n, err
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return n, err
+	}
+	return n, nil
+}
@@ -0,0 +1,136 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/yinhm/friendfeed/cassette"
+	"github.com/yinhm/friendfeed/ff"
+	pb "github.com/yinhm/friendfeed/proto"
+)
+
+func TestFriendFeedArchiver(t *testing.T) {
+	Convey("Given friendfeed feed pages, archive and checkpoint every page", t, func() {
+		// 100, 100 and 30 entries
+		pages := map[string]string{
+			"0":   "../ff/testdata/feed1.json",
+			"100": "../ff/testdata/feed5.json",
+			"200": "../ff/testdata/feed.json",
+		}
+		var starts []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := r.URL.Query().Get("start")
+			if start == "" {
+				start = "0"
+			}
+			starts = append(starts, start)
+			user, key, _ := r.BasicAuth()
+			if r.URL.Path != "/v2/feed/yinhm" || user != "yinhm" || key != "remotekey" {
+				http.NotFound(w, r)
+				return
+			}
+			data, err := ioutil.ReadFile(pages[start])
+			if err != nil {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(data)
+		}))
+		defer ts.Close()
+
+		client := ff.NewClient(nil, "yinhm", "remotekey")
+		client.BaseURL, _ = url.Parse(ts.URL + "/v2")
+
+		job := &pb.FeedJob{
+			Id:        "yinhm",
+			Uuid:      "c6f8dca854f011ddb489003048343a40",
+			RemoteKey: "remotekey",
+			TargetId:  "yinhm",
+			PageSize:  100,
+		}
+		var checkpoints []int32
+		archiver := &FriendFeedArchiver{
+			Client: client,
+			Checkpoint: func(job *pb.FeedJob) error {
+				checkpoints = append(checkpoints, job.Start)
+				return nil
+			},
+		}
+
+		// interrupted on the second page
+		var entries []*pb.Entry
+		send := func(entry *pb.Entry) error {
+			if len(entries) == 150 {
+				return fmt.Errorf("stream closed")
+			}
+			entries = append(entries, entry)
+			return nil
+		}
+		n, err := archiver.Import(context.Background(), job, send)
+		So(err, ShouldNotBeNil)
+		So(n, ShouldEqual, 150)
+		So(job.Start, ShouldEqual, 100)
+		So(checkpoints, ShouldResemble, []int32{100})
+		So(entries[0].Id, ShouldEqual, "e/95a0d02fb680418ea1b7fb55baf1ee2d")
+		So(entries[0].ProfileUuid, ShouldEqual, job.Uuid)
+
+		// resumed from checkpoint, stops on the last short page
+		entries = nil
+		n, err = archiver.Import(context.Background(), job, send)
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 130)
+		So(entries[0].Id, ShouldEqual, "e/2b43a9066074d120ed2e45494eea1797")
+		So(job.Start, ShouldEqual, 230)
+		So(checkpoints, ShouldResemble, []int32{100, 200, 230})
+		So(starts, ShouldResemble, []string{"0", "100", "100", "200"})
+
+		// max pages
+		job.Start = 0
+		job.MaxLimit = 1
+		entries = nil
+		n, err = archiver.Import(context.Background(), job, send)
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 100)
+		So(job.Start, ShouldEqual, 100)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = archiver.Import(ctx, job, send)
+		So(err, ShouldEqual, context.Canceled)
+	})
+}
+
+func TestFriendFeedArchiverReplay(t *testing.T) {
+	Convey("Given recorded friendfeed feed, archive all pages offline", t, func() {
+		rec, err := cassette.New("testdata/cassettes/friendfeed", cassette.ModeFromEnv())
+		So(err, ShouldBeNil)
+		defer rec.Stop()
+
+		job := &pb.FeedJob{
+			Id:        "yinhm",
+			Uuid:      "c6f8dca854f011ddb489003048343a40",
+			RemoteKey: "remotekey",
+			PageSize:  10,
+		}
+		archiver := &FriendFeedArchiver{
+			Client: ff.NewClient(rec.Client(), job.Id, job.RemoteKey),
+		}
+		var entries []*pb.Entry
+		n, err := archiver.Import(context.Background(), job, func(entry *pb.Entry) error {
+			entries = append(entries, entry)
+			return nil
+		})
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 25)
+		So(job.Start, ShouldEqual, 25)
+		So(entries[0].Id, ShouldEqual, "e/95a0d02fb680418ea1b7fb55baf1ee2d")
+		So(entries[24].From.Id, ShouldEqual, "yinhm")
+	})
+}
@@ -0,0 +1,28 @@
+// Package importer imports entries of third party services, eg: blogs,
+// flickr, into friendfeed.
+package importer
+
+import (
+	"context"
+
+	pb "github.com/yinhm/friendfeed/proto"
+)
+
+// SendFunc sends imported entry to archive.
+type SendFunc func(entry *pb.Entry) error
+
+// Importer imports entries of job.Service into feed of job.Profile.
+type Importer interface {
+	// Import sends entries of service, oldest first, returns number of
+	// entries sent.
+	Import(ctx context.Context, job *pb.FeedJob, send SendFunc) (int, error)
+}
+
+// from returns author feed of imported entries.
+func from(profile *pb.Profile) *pb.Feed {
+	return &pb.Feed{
+		Id:   profile.Id,
+		Name: profile.Name,
+		Type: profile.Type,
+	}
+}
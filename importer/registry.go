@@ -0,0 +1,89 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/yinhm/friendfeed/proto"
+)
+
+// Source declares how services of the same Service.Id are imported.
+type Source struct {
+	// Id matches Service.Id
+	Id string
+	// Interval between two jobs of a service
+	Interval time.Duration
+	// OAuth is required, service without Oauth not scheduled
+	OAuth bool
+	// Url of feed is required, service without Url not scheduled
+	Url bool
+	// New returns importer fetching services of the source
+	New func() Importer
+}
+
+// Ready reports whether service is configured for importing.
+func (src *Source) Ready(service *pb.Service) bool {
+	if src.OAuth && service.Oauth == nil {
+		return false
+	}
+	if src.Url && service.Url == "" {
+		return false
+	}
+	return true
+}
+
+var (
+	mu      sync.RWMutex
+	sources = make(map[string]*Source)
+)
+
+// Register makes source available by Service.Id, panics if registered twice.
+func Register(src *Source) {
+	mu.Lock()
+	defer mu.Unlock()
+	if src == nil || src.Id == "" || src.New == nil {
+		panic("importer: Register source is invalid")
+	}
+	if _, dup := sources[src.Id]; dup {
+		panic("importer: Register called twice for source " + src.Id)
+	}
+	sources[src.Id] = src
+}
+
+// Lookup returns source registered for id.
+func Lookup(id string) (*Source, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	src, ok := sources[id]
+	return src, ok
+}
+
+// Sources returns ids of registered sources, sorted.
+func Sources() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	var ids []string
+	for id := range sources {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Import dispatches job to importer of job.Service.Id.
+func Import(ctx context.Context, job *pb.FeedJob, send SendFunc) (int, error) {
+	if job.Service == nil {
+		return 0, fmt.Errorf("skip job: no service")
+	}
+	src, ok := Lookup(job.Service.Id)
+	if !ok {
+		return 0, fmt.Errorf("skip job: unknown service %q", job.Service.Id)
+	}
+	if !src.Ready(job.Service) {
+		return 0, fmt.Errorf("skip job: service %q not configured", job.Service.Id)
+	}
+	return src.New().Import(ctx, job, send)
+}
@@ -0,0 +1,36 @@
+package importer
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	pb "github.com/yinhm/friendfeed/proto"
+)
+
+func TestRegistry(t *testing.T) {
+	Convey("Given registered sources, dispatch by service id", t, func() {
+		So(Sources(), ShouldResemble, []string{FeedServiceId, MastodonServiceId, TwitterServiceId})
+
+		twitter, ok := Lookup(TwitterServiceId)
+		So(ok, ShouldBeTrue)
+		So(twitter.Ready(&pb.Service{Id: TwitterServiceId}), ShouldBeFalse)
+		So(twitter.Ready(&pb.Service{Id: TwitterServiceId, Oauth: &pb.OAuthUser{}}), ShouldBeTrue)
+
+		feed, ok := Lookup(FeedServiceId)
+		So(ok, ShouldBeTrue)
+		So(feed.Ready(&pb.Service{Id: FeedServiceId}), ShouldBeFalse)
+		So(feed.Interval, ShouldBeGreaterThan, twitter.Interval)
+
+		_, ok = Lookup("friendfeed")
+		So(ok, ShouldBeFalse)
+
+		send := func(entry *pb.Entry) error { return nil }
+		_, err := Import(context.Background(), &pb.FeedJob{Service: &pb.Service{Id: "friendfeed"}}, send)
+		So(err, ShouldNotBeNil)
+		_, err = Import(context.Background(), &pb.FeedJob{Service: &pb.Service{Id: TwitterServiceId}}, send)
+		So(err, ShouldNotBeNil)
+
+		So(func() { Register(&Source{Id: FeedServiceId, New: func() Importer { return NewFeedImporter() }}) }, ShouldPanic)
+	})
+}
@@ -0,0 +1,129 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ChimeraCoder/anaconda"
+	ttext "github.com/cupcake/text-entities-go"
+	uuid "github.com/satori/go.uuid"
+	pb "github.com/yinhm/friendfeed/proto"
+)
+
+// TwitterServiceId is service id of twitter, bound by oauth.
+const TwitterServiceId = "twitter"
+
+func init() {
+	Register(&Source{
+		Id:       TwitterServiceId,
+		Interval: 5 * time.Minute,
+		OAuth:    true,
+		New:      func() Importer { return new(TwitterImporter) },
+	})
+}
+
+// TwitterImporter imports user timeline of job.Service.Oauth, replies
+// skipped. Consumer key and secret must be set by anaconda.SetConsumerKey and
+// anaconda.SetConsumerSecret.
+type TwitterImporter struct{}
+
+// Import sends tweets created since job.Service.Updated.
+func (ti *TwitterImporter) Import(ctx context.Context, job *pb.FeedJob, send SendFunc) (int, error) {
+	updated := time.Unix(job.Service.Updated, 0)
+	authinfo := job.Service.Oauth
+	if authinfo == nil {
+		return 0, fmt.Errorf("skip job: no authinfo")
+	}
+	api := anaconda.NewTwitterApi(authinfo.AccessToken, authinfo.AccessTokenSecret)
+	defer api.Close()
+
+	v := url.Values{}
+	v.Set("screen_name", authinfo.NickName) // goth user.NickName == screen_name
+	tweets, err := api.GetUserTimeline(v)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for i := len(tweets) - 1; i >= 0; i-- {
+		tweet := tweets[i]
+
+		// skip reply status
+		if tweet.InReplyToStatusID != 0 {
+			continue
+		}
+
+		tt, err := tweet.CreatedAtTime()
+		if err != nil || tt.Before(updated) {
+			continue
+		}
+
+		var thumbnails []*pb.Thumbnail
+		for _, media := range tweet.Entities.Media {
+			if media.Type != "photo" {
+				continue
+			}
+
+			url := ""
+			if media.Media_url_https != "" {
+				url = media.Media_url_https
+			} else {
+				url = media.Media_url
+			}
+			thumb := &pb.Thumbnail{
+				Url:    url,
+				Link:   media.Expanded_url,
+				Width:  int32(media.Sizes.Small.W),
+				Height: int32(media.Sizes.Small.H),
+			}
+			thumbnails = append(thumbnails, thumb)
+		}
+
+		entry := tweetEntry(job.Profile, tweet.User.ScreenName, tweet.IdStr, tweet.Text, tt)
+		entry.Thumbnails = thumbnails
+		if err := send(entry); err != nil {
+			return n, err
+		}
+
+		n++
+	}
+	return n, nil
+}
+
+// tweetEntry maps tweet to entry of profile.
+func tweetEntry(profile *pb.Profile, screenName, idStr, text string, created time.Time) *pb.Entry {
+	url := "https://twitter.com/" + screenName + "/status/" + idStr
+	// deterministic uuid or feed will be polluted
+	uuid1 := uuid.NewV5(uuid.NamespaceURL, url)
+
+	body := text
+	tags := ttext.ExtractHashtags(body)
+	for _, tag := range tags {
+		new := fmt.Sprintf("<a href=\"https://twitter.com/hashtag/%s\">%s</a>", tag, tag)
+		body = strings.Replace(body, tag, new, -1)
+	}
+	urls := ttext.ExtractURLs(text)
+	for _, url := range urls {
+		new := fmt.Sprintf("<a href=\"%s\">%s</a>", url, url)
+		body = strings.Replace(body, url, new, -1)
+	}
+
+	return &pb.Entry{
+		Id:      uuid1.String(),
+		Url:     url,
+		Date:    created.Format(time.RFC3339),
+		Body:    body,
+		RawBody: text,
+		RawLink: url,
+		From:    from(profile),
+		// To:         []*pb.Feed{from},
+		Via: &pb.Via{
+			Name: "Twitter",
+			Url:  url,
+		},
+		ProfileUuid: profile.Uuid,
+	}
+}
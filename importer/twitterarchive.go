@@ -0,0 +1,342 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"mime"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yinhm/friendfeed/media"
+	pb "github.com/yinhm/friendfeed/proto"
+)
+
+// TwitterArchive is the zip of "Download an archive of your data" from
+// twitter, tweets in data/tweets.js and media in data/tweets_media. Older
+// archives named them data/tweet.js and data/tweet_media.
+type TwitterArchive struct {
+	// screen name of account
+	Username string
+	Tweets   []*ArchivedTweet
+
+	// media files by name, eg: 1234-abcd.jpg
+	media map[string]*zip.File
+	rc    *zip.ReadCloser
+}
+
+// ArchivedTweet is a tweet in archive.
+type ArchivedTweet struct {
+	IdStr             string `json:"id_str"`
+	FullText          string `json:"full_text"`
+	CreatedAt         string `json:"created_at"`
+	InReplyToStatusId string `json:"in_reply_to_status_id_str"`
+	Entities          struct {
+		Media []*archivedMedia `json:"media"`
+	} `json:"entities"`
+	ExtendedEntities struct {
+		Media []*archivedMedia `json:"media"`
+	} `json:"extended_entities"`
+}
+
+type archivedMedia struct {
+	IdStr         string `json:"id_str"`
+	MediaUrlHttps string `json:"media_url_https"`
+	ExpandedUrl   string `json:"expanded_url"`
+	Type          string `json:"type"`
+	Sizes         struct {
+		Small struct {
+			W string `json:"w"`
+			H string `json:"h"`
+		} `json:"small"`
+	} `json:"sizes"`
+	VideoInfo struct {
+		Variants []struct {
+			Bitrate     string `json:"bitrate"`
+			ContentType string `json:"content_type"`
+			Url         string `json:"url"`
+		} `json:"variants"`
+	} `json:"video_info"`
+}
+
+// CreatedAtTime parses created_at of tweet.
+func (t *ArchivedTweet) CreatedAtTime() (time.Time, error) {
+	return time.Parse(time.RubyDate, t.CreatedAt)
+}
+
+// OpenTwitterArchive opens archive zip file, archive must be closed after use.
+func OpenTwitterArchive(name string) (*TwitterArchive, error) {
+	rc, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := NewTwitterArchive(&rc.Reader)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+	archive.rc = rc
+	return archive, nil
+}
+
+// NewTwitterArchive reads account and tweets of archive, media files read
+// on import.
+func NewTwitterArchive(r *zip.Reader) (*TwitterArchive, error) {
+	archive := &TwitterArchive{media: make(map[string]*zip.File)}
+	found := false
+	for _, f := range r.File {
+		dir, name := path.Split(strings.TrimPrefix(f.Name, "/"))
+		switch {
+		case dir == "data/tweets_media/" || dir == "data/tweet_media/":
+			archive.media[name] = f
+		case dir == "data/" && name == "account.js":
+			var accounts []struct {
+				Account struct {
+					Username string `json:"username"`
+				} `json:"account"`
+			}
+			if err := readYTD(f, &accounts); err != nil {
+				return nil, err
+			}
+			if len(accounts) > 0 {
+				archive.Username = accounts[0].Account.Username
+			}
+		case dir == "data/" && isTweetsFile(name):
+			var items []json.RawMessage
+			if err := readYTD(f, &items); err != nil {
+				return nil, err
+			}
+			for _, item := range items {
+				tweet, err := parseArchivedTweet(item)
+				if err != nil {
+					return nil, fmt.Errorf("importer: %s: %s", f.Name, err)
+				}
+				archive.Tweets = append(archive.Tweets, tweet)
+			}
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("importer: no tweets in archive")
+	}
+	if archive.Username == "" {
+		return nil, fmt.Errorf("importer: no account in archive")
+	}
+	return archive, nil
+}
+
+// Close closes archive opened by OpenTwitterArchive.
+func (a *TwitterArchive) Close() error {
+	if a.rc == nil {
+		return nil
+	}
+	return a.rc.Close()
+}
+
+// isTweetsFile matches tweets.js, tweet.js and parts, eg: tweets-part1.js.
+func isTweetsFile(name string) bool {
+	if !strings.HasSuffix(name, ".js") {
+		return false
+	}
+	name = strings.TrimSuffix(name, ".js")
+	return name == "tweets" || name == "tweet" ||
+		strings.HasPrefix(name, "tweets-part") || strings.HasPrefix(name, "tweet-part")
+}
+
+// readYTD decodes json of data file, eg: window.YTD.tweets.part0 = [...].
+func readYTD(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	data, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+	if i := bytes.IndexAny(data, "[{"); i > 0 {
+		data = data[i:]
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("importer: %s: %s", f.Name, err)
+	}
+	return nil
+}
+
+// parseArchivedTweet parses {"tweet": {...}}, or bare tweet of old archives.
+func parseArchivedTweet(data []byte) (*ArchivedTweet, error) {
+	var wrapped struct {
+		Tweet *ArchivedTweet `json:"tweet"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return nil, err
+	}
+	if wrapped.Tweet != nil {
+		return wrapped.Tweet, nil
+	}
+	tweet := new(ArchivedTweet)
+	if err := json.Unmarshal(data, tweet); err != nil {
+		return nil, err
+	}
+	return tweet, nil
+}
+
+// TwitterArchiveImporter imports tweets of archive, replies skipped. Entries
+// share ids with TwitterImporter, tweets imported by both never duplicated.
+type TwitterArchiveImporter struct {
+	Archive *TwitterArchive
+	// media of archive uploaded to storage, twitter urls kept if nil
+	Storage media.Storage
+}
+
+func NewTwitterArchiveImporter(archive *TwitterArchive, storage media.Storage) *TwitterArchiveImporter {
+	return &TwitterArchiveImporter{Archive: archive, Storage: storage}
+}
+
+// Import sends all tweets of archive, oldest first.
+func (ai *TwitterArchiveImporter) Import(ctx context.Context, job *pb.FeedJob, send SendFunc) (int, error) {
+	if job.Profile == nil {
+		return 0, fmt.Errorf("skip job: no profile")
+	}
+
+	type dated struct {
+		tweet   *ArchivedTweet
+		created time.Time
+	}
+	var tweets []dated
+	for _, tweet := range ai.Archive.Tweets {
+		// skip reply status
+		if tweet.InReplyToStatusId != "" {
+			continue
+		}
+		tt, err := tweet.CreatedAtTime()
+		if err != nil || tweet.IdStr == "" {
+			continue
+		}
+		tweets = append(tweets, dated{tweet, tt})
+	}
+	sort.SliceStable(tweets, func(i, j int) bool {
+		return tweets[i].created.Before(tweets[j].created)
+	})
+
+	n := 0
+	for _, t := range tweets {
+		if err := ctx.Err(); err != nil {
+			return n, err
+		}
+		entry := tweetEntry(job.Profile, ai.Archive.Username, t.tweet.IdStr, t.tweet.FullText, t.created)
+		ai.attachMedia(entry, t.tweet)
+		if err := send(entry); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// attachMedia adds photos as thumbnails, videos and gifs as files. Media
+// files uploaded to storage, twitter urls used if missing in archive or
+// upload failed.
+func (ai *TwitterArchiveImporter) attachMedia(entry *pb.Entry, tweet *ArchivedTweet) {
+	medias := tweet.ExtendedEntities.Media
+	if len(medias) == 0 {
+		medias = tweet.Entities.Media
+	}
+	for _, m := range medias {
+		switch m.Type {
+		case "photo":
+			link := m.ExpandedUrl
+			u := m.MediaUrlHttps
+			if uploaded := ai.upload(tweet.IdStr, u); uploaded != "" {
+				u, link = uploaded, uploaded
+			}
+			entry.Thumbnails = append(entry.Thumbnails, &pb.Thumbnail{
+				Url:    u,
+				Link:   link,
+				Width:  atoi32(m.Sizes.Small.W),
+				Height: atoi32(m.Sizes.Small.H),
+			})
+		case "video", "animated_gif":
+			src := m.video()
+			if src == "" {
+				continue
+			}
+			u := src
+			if uploaded := ai.upload(tweet.IdStr, src); uploaded != "" {
+				u = uploaded
+			}
+			entry.Files = append(entry.Files, &pb.File{
+				Url:  u,
+				Type: "video/mp4",
+				Name: fileName(src),
+			})
+			entry.Thumbnails = append(entry.Thumbnails, &pb.Thumbnail{
+				Url:    m.MediaUrlHttps,
+				Link:   u,
+				Width:  atoi32(m.Sizes.Small.W),
+				Height: atoi32(m.Sizes.Small.H),
+			})
+		}
+	}
+}
+
+// video returns mp4 url of the highest bitrate.
+func (m *archivedMedia) video() string {
+	best, bitrate := "", -1
+	for _, v := range m.VideoInfo.Variants {
+		if v.ContentType != "video/mp4" {
+			continue
+		}
+		if b := int(atoi32(v.Bitrate)); b > bitrate {
+			best, bitrate = v.Url, b
+		}
+	}
+	return best
+}
+
+// upload uploads media file of tweet, archive names file by tweet id and
+// base name of remote url. Returns url of uploaded file, empty if failed.
+func (ai *TwitterArchiveImporter) upload(tweetId, remote string) string {
+	if ai.Storage == nil {
+		return ""
+	}
+	u, err := url.Parse(remote)
+	if err != nil {
+		return ""
+	}
+	name := tweetId + "-" + path.Base(u.Path)
+	f, ok := ai.Archive.media[name]
+	if !ok {
+		return ""
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return ""
+	}
+	defer rc.Close()
+	content, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return ""
+	}
+
+	obj := &media.Object{
+		Filename: name,
+		Path:     "twitter/" + name,
+		MimeType: mime.TypeByExtension(path.Ext(name)),
+		Url:      remote,
+		Content:  content,
+	}
+	newObj, err := ai.Storage.Mirror(obj)
+	if err != nil {
+		log.Printf("upload %s failed: %s", name, err)
+		return ""
+	}
+	return newObj.Url
+}
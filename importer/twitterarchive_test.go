@@ -0,0 +1,187 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/yinhm/friendfeed/media"
+	pb "github.com/yinhm/friendfeed/proto"
+)
+
+const archiveAccount = `window.YTD.account.part0 = [ {
+  "account" : { "username" : "yinhm", "accountId" : "1234" }
+} ]`
+
+const archiveTweets = `window.YTD.tweets.part0 = [ {
+  "tweet" : {
+    "id_str" : "200",
+    "full_text" : "sunset #photo https://t.co/abc",
+    "created_at" : "Tue Jun 02 10:00:00 +0000 2015",
+    "entities" : { "media" : [ {
+      "media_url_https" : "https://pbs.twimg.com/media/sunset.jpg",
+      "expanded_url" : "https://twitter.com/yinhm/status/200/photo/1",
+      "type" : "photo"
+    } ] },
+    "extended_entities" : { "media" : [ {
+      "media_url_https" : "https://pbs.twimg.com/media/sunset.jpg",
+      "expanded_url" : "https://twitter.com/yinhm/status/200/photo/1",
+      "type" : "photo",
+      "sizes" : { "small" : { "w" : "680", "h" : "453" } }
+    }, {
+      "media_url_https" : "https://pbs.twimg.com/media/missing.jpg",
+      "expanded_url" : "https://twitter.com/yinhm/status/200/photo/2",
+      "type" : "photo"
+    } ] }
+  }
+}, {
+  "tweet" : {
+    "id_str" : "100",
+    "full_text" : "hello world",
+    "created_at" : "Mon Jun 01 10:00:00 +0000 2015"
+  }
+}, {
+  "tweet" : {
+    "id_str" : "300",
+    "full_text" : "@bret yes",
+    "in_reply_to_status_id_str" : "299",
+    "created_at" : "Wed Jun 03 10:00:00 +0000 2015"
+  }
+} ]`
+
+const archiveVideos = `window.YTD.tweets.part1 = [ {
+  "id_str" : "400",
+  "full_text" : "video",
+  "created_at" : "Thu Jun 04 10:00:00 +0000 2015",
+  "extended_entities" : { "media" : [ {
+    "media_url_https" : "https://pbs.twimg.com/ext_tw_video_thumb/400/poster.jpg",
+    "type" : "video",
+    "video_info" : { "variants" : [
+      { "bitrate" : "320000", "content_type" : "video/mp4", "url" : "https://video.twimg.com/ext_tw_video/400/low.mp4" },
+      { "content_type" : "application/x-mpegURL", "url" : "https://video.twimg.com/ext_tw_video/400/pl.m3u8" },
+      { "bitrate" : "2176000", "content_type" : "video/mp4", "url" : "https://video.twimg.com/ext_tw_video/400/high.mp4?tag=10" }
+    ] }
+  } ] }
+} ]`
+
+// fakeStorage keeps mirrored objects in memory.
+type fakeStorage struct {
+	objects map[string]*media.Object
+}
+
+func (fs *fakeStorage) Exists(name string) (bool, error) {
+	_, ok := fs.objects[name]
+	return ok, nil
+}
+
+func (fs *fakeStorage) Delete(name string) error {
+	delete(fs.objects, name)
+	return nil
+}
+
+func (fs *fakeStorage) Post(obj *media.Object) (*media.Object, error) {
+	if len(obj.Content) == 0 {
+		return nil, fmt.Errorf("no content")
+	}
+	fs.objects[obj.Path] = obj
+	return &media.Object{Path: obj.Path, Url: "https://media.example.com/" + obj.Path}, nil
+}
+
+func (fs *fakeStorage) Mirror(obj *media.Object) (*media.Object, error) {
+	return fs.Post(obj)
+}
+
+func (fs *fakeStorage) FromUrl(filename, src, mimetype string) (*media.Object, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func newTestArchive(files map[string]string) (*TwitterArchive, error) {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			return nil, err
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		return nil, err
+	}
+	return NewTwitterArchive(zr)
+}
+
+func TestTwitterArchive(t *testing.T) {
+	Convey("Given twitter archive, import tweets with media", t, func() {
+		archive, err := newTestArchive(map[string]string{
+			"data/account.js":                  archiveAccount,
+			"data/tweets.js":                   archiveTweets,
+			"data/tweets-part1.js":             archiveVideos,
+			"data/tweets_media/200-sunset.jpg": "jpeg",
+			"data/tweets_media/400-high.mp4":   "mp4",
+			"data/like.js":                     "window.YTD.like.part0 = []",
+		})
+		So(err, ShouldBeNil)
+		So(archive.Username, ShouldEqual, "yinhm")
+		So(len(archive.Tweets), ShouldEqual, 4)
+
+		storage := &fakeStorage{objects: make(map[string]*media.Object)}
+		profile := &pb.Profile{Uuid: "c6f8dca854f011ddb489003048343a40", Id: "yinhm", Name: "Heming", Type: "user"}
+		job := &pb.FeedJob{Id: profile.Id, Profile: profile}
+
+		var entries []*pb.Entry
+		send := func(entry *pb.Entry) error {
+			entries = append(entries, entry)
+			return nil
+		}
+		n, err := NewTwitterArchiveImporter(archive, storage).Import(context.Background(), job, send)
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 3)
+
+		// oldest first, reply skipped, same ids as timeline importer
+		hello := entries[0]
+		So(hello.Id, ShouldEqual, uuid.NewV5(uuid.NamespaceURL, "https://twitter.com/yinhm/status/100").String())
+		So(hello.Url, ShouldEqual, "https://twitter.com/yinhm/status/100")
+		So(hello.Date, ShouldEqual, "2015-06-01T10:00:00Z")
+		So(hello.From.Id, ShouldEqual, "yinhm")
+		So(hello.ProfileUuid, ShouldEqual, profile.Uuid)
+		So(hello.Via.Name, ShouldEqual, "Twitter")
+
+		sunset := entries[1]
+		So(sunset.Body, ShouldContainSubstring, `<a href="https://twitter.com/hashtag/photo">`)
+		So(sunset.Body, ShouldContainSubstring, `<a href="https://t.co/abc">`)
+		So(len(sunset.Thumbnails), ShouldEqual, 2)
+		So(sunset.Thumbnails[0].Url, ShouldEqual, "https://media.example.com/twitter/200-sunset.jpg")
+		So(sunset.Thumbnails[0].Width, ShouldEqual, 680)
+		// missing in archive
+		So(sunset.Thumbnails[1].Url, ShouldEqual, "https://pbs.twimg.com/media/missing.jpg")
+		So(sunset.Thumbnails[1].Link, ShouldEqual, "https://twitter.com/yinhm/status/200/photo/2")
+		So(string(storage.objects["twitter/200-sunset.jpg"].Content), ShouldEqual, "jpeg")
+		So(storage.objects["twitter/200-sunset.jpg"].MimeType, ShouldEqual, "image/jpeg")
+
+		video := entries[2]
+		So(len(video.Files), ShouldEqual, 1)
+		So(video.Files[0].Url, ShouldEqual, "https://media.example.com/twitter/400-high.mp4")
+		So(video.Files[0].Name, ShouldEqual, "high.mp4")
+		So(video.Thumbnails[0].Url, ShouldEqual, "https://pbs.twimg.com/ext_tw_video_thumb/400/poster.jpg")
+
+		// no storage, twitter urls kept
+		entries = nil
+		_, err = NewTwitterArchiveImporter(archive, nil).Import(context.Background(), job, send)
+		So(err, ShouldBeNil)
+		So(entries[1].Thumbnails[0].Url, ShouldEqual, "https://pbs.twimg.com/media/sunset.jpg")
+	})
+
+	Convey("Given zip without tweets, open failed", t, func() {
+		_, err := newTestArchive(map[string]string{"data/account.js": archiveAccount})
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,113 @@
+package media
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"golang.org/x/net/context"
+)
+
+// Index maps source urls to hashes of content mirrored, see ContentStore.
+type Index interface {
+	// Hash returns hash of content mirrored from src, empty if not mirrored.
+	Hash(src string) (string, error)
+	// PutHash records content of src mirrored as obj.
+	PutHash(src string, obj *Object) error
+}
+
+// ContentStore keeps objects in backend storage by SHA-256 of content, so
+// that content reposted under different urls is stored once and a changed
+// source never overwrites an object. Sources mirrored are indexed, known
+// urls are not fetched again.
+//
+// Paths of objects are ignored, objects returned have Hash set.
+type ContentStore struct {
+	Storage
+	Index Index
+	// Fetcher fetches content of objects posted by url
+	Fetcher *Fetcher
+}
+
+func NewContentStore(backend Storage, index Index) *ContentStore {
+	return &ContentStore{
+		Storage: backend,
+		Index:   index,
+		Fetcher: NewFetcher(nil),
+	}
+}
+
+// ContentHash returns hex sha256 of content.
+func ContentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// BlobPath returns object path of content hash in backend storage.
+func BlobPath(hash string) string {
+	if len(hash) < 2 {
+		return "sha256/" + hash
+	}
+	return "sha256/" + hash[:2] + "/" + hash
+}
+
+func (c *ContentStore) FromUrl(filename, src, mimetype string) (*Object, error) {
+	obj, err := newObject(filename, src, mimetype)
+	if err != nil {
+		return nil, err
+	}
+	return c.Mirror(obj)
+}
+
+// Mirror returns blob of obj.Url if indexed and kept, posts obj otherwise.
+func (c *ContentStore) Mirror(obj *Object) (*Object, error) {
+	if obj.Url != "" && c.Index != nil {
+		hash, err := c.Index.Hash(obj.Url)
+		if err != nil {
+			return nil, err
+		}
+		if hash != "" {
+			// blob collected, mirrored again
+			if ok, err := c.Storage.Exists(BlobPath(hash)); err == nil && ok {
+				return c.blob(&Object{Filename: obj.Filename, MimeType: obj.MimeType}, hash)
+			}
+		}
+	}
+	return c.Post(obj)
+}
+
+// Post keeps obj.Content as blob of its hash, content fetched from obj.Url
+// if empty. Blob uploaded once.
+func (c *ContentStore) Post(obj *Object) (*Object, error) {
+	if len(obj.Content) == 0 {
+		if err := c.Fetcher.Fetch(context.Background(), obj); err != nil {
+			return nil, err
+		}
+	}
+	hash := ContentHash(obj.Content)
+	newObj, err := c.blob(obj, hash)
+	if err != nil {
+		return nil, err
+	}
+	if obj.Url != "" && c.Index != nil {
+		if err := c.Index.PutHash(obj.Url, newObj); err != nil {
+			return nil, err
+		}
+	}
+	return newObj, nil
+}
+
+func (c *ContentStore) blob(obj *Object, hash string) (*Object, error) {
+	newObj, err := c.Storage.Mirror(&Object{
+		Filename: obj.Filename,
+		Path:     BlobPath(hash),
+		MimeType: obj.MimeType,
+		Content:  obj.Content,
+	})
+	if err != nil {
+		return nil, err
+	}
+	newObj.Hash = hash
+	// content kept for thumbnails
+	newObj.Content = obj.Content
+	return newObj, nil
+}
@@ -0,0 +1,94 @@
+package media
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/yinhm/friendfeed/cassette"
+)
+
+// mapIndex keeps hashes of urls in memory.
+type mapIndex map[string]string
+
+func (m mapIndex) Hash(src string) (string, error) {
+	return m[src], nil
+}
+
+func (m mapIndex) PutHash(src string, obj *Object) error {
+	m[src] = obj.Hash
+	return nil
+}
+
+// countingTransport counts requests sent.
+type countingTransport struct {
+	http.RoundTripper
+	n int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.n++
+	return t.RoundTripper.RoundTrip(req)
+}
+
+func TestContentStore(t *testing.T) {
+	Convey("Given content store of local storage, keep objects by hash", t, func() {
+		root, err := ioutil.TempDir("", "media")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(root)
+
+		rec, err := cassette.New("testdata/cassettes/fetch", cassette.Replay)
+		So(err, ShouldBeNil)
+		transport := &countingTransport{RoundTripper: rec}
+		index := make(mapIndex)
+		cs := NewContentStore(NewLocalStorage(&Config{LocalRoot: root}), index)
+		cs.Fetcher = NewFetcher(&http.Client{Transport: transport})
+
+		src := "http://m.friendfeed-media.com/46b97c2da4b7596dfb4f78613d65080cbdca2439"
+		obj, err := cs.FromUrl("", src, "")
+		So(err, ShouldBeNil)
+		So(len(obj.Hash), ShouldEqual, 64)
+		So(obj.Path, ShouldEqual, BlobPath(obj.Hash))
+		So(obj.Url, ShouldEqual, "/media/"+BlobPath(obj.Hash))
+		So(obj.MimeType, ShouldEqual, "image/png")
+		So(index[src], ShouldEqual, obj.Hash)
+		hash := obj.Hash
+
+		// indexed url not fetched again
+		obj, err = cs.FromUrl("", src, "")
+		So(err, ShouldBeNil)
+		So(obj.Hash, ShouldEqual, hash)
+		So(transport.n, ShouldEqual, 1)
+
+		// same content of another url deduplicated
+		content, err := ioutil.ReadFile(root + "/" + BlobPath(hash))
+		So(err, ShouldBeNil)
+		repost := "http://i.friendfeed.com/repost.png"
+		obj, err = cs.Mirror(&Object{Url: repost, Content: content})
+		So(err, ShouldBeNil)
+		So(obj.Hash, ShouldEqual, hash)
+		So(index[repost], ShouldEqual, hash)
+
+		// changed source never overwrites
+		obj, err = cs.Post(&Object{Url: repost, Content: []byte("changed")})
+		So(err, ShouldBeNil)
+		So(obj.Hash, ShouldNotEqual, hash)
+		ok, _ := cs.Exists(BlobPath(hash))
+		So(ok, ShouldBeTrue)
+
+		// collected blob mirrored again
+		So(cs.Delete(BlobPath(hash)), ShouldBeNil)
+		So(cs.Delete(BlobPath(hash)), ShouldBeNil)
+		obj, err = cs.FromUrl("", src, "")
+		So(err, ShouldBeNil)
+		So(obj.Hash, ShouldEqual, hash)
+		So(transport.n, ShouldEqual, 2)
+		ok, _ = cs.Exists(BlobPath(hash))
+		So(ok, ShouldBeTrue)
+
+		_, err = cs.FromUrl("", "http://m.friendfeed-media.com/missing", "")
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,157 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+)
+
+// EXIF orientations, http://www.cipa.jp/std/documents/e/DC-008-2012_E.pdf
+const (
+	orientNormal     = 1
+	orientFlipH      = 2
+	orientRotate180  = 3
+	orientFlipV      = 4
+	orientTranspose  = 5
+	orientRotate90   = 6
+	orientTransverse = 7
+	orientRotate270  = 8
+
+	exifTagOrientation = 0x0112
+)
+
+// exifOrientation returns orientation in exif of jpeg or webp content,
+// orientNormal if none.
+func exifOrientation(content []byte) int {
+	var exif []byte
+	switch {
+	case bytes.HasPrefix(content, []byte("\xff\xd8")):
+		exif = jpegExif(content)
+	case len(content) > 12 && string(content[:4]) == "RIFF" && string(content[8:12]) == "WEBP":
+		exif = webpExif(content)
+	}
+	if o := tiffOrientation(exif); o >= orientNormal && o <= orientRotate270 {
+		return o
+	}
+	return orientNormal
+}
+
+// jpegExif returns tiff data of exif APP1 segment.
+func jpegExif(content []byte) []byte {
+	p := content[2:]
+	for len(p) >= 4 && p[0] == 0xff {
+		marker := p[1]
+		// start of scan, no more metadata
+		if marker == 0xda {
+			return nil
+		}
+		n := int(binary.BigEndian.Uint16(p[2:4]))
+		if n < 2 || len(p) < 2+n {
+			return nil
+		}
+		segment := p[4 : 2+n]
+		if marker == 0xe1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:]
+		}
+		p = p[2+n:]
+	}
+	return nil
+}
+
+// webpExif returns tiff data of EXIF chunk.
+func webpExif(content []byte) []byte {
+	p := content[12:]
+	for len(p) >= 8 {
+		n := int(binary.LittleEndian.Uint32(p[4:8]))
+		if n < 0 || len(p) < 8+n {
+			return nil
+		}
+		if string(p[:4]) == "EXIF" {
+			exif := p[8 : 8+n]
+			// some writers keep the jpeg header
+			return bytes.TrimPrefix(exif, []byte("Exif\x00\x00"))
+		}
+		// chunks padded to even size, pad of the last chunk may be missing
+		next := 8 + n + n%2
+		if next > len(p) {
+			return nil
+		}
+		p = p[next:]
+	}
+	return nil
+}
+
+// tiffOrientation returns orientation tag of IFD0, zero if not found.
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset < 8 || len(tiff) < offset+2 {
+		return 0
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if len(tiff) < entry+12 {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == exifTagOrientation {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 0
+}
+
+// orientTransposed reports whether width and height swapped by orientation.
+func orientTransposed(orientation int) bool {
+	return orientation >= orientTranspose
+}
+
+// orient returns img displayed upright by exif orientation.
+func orient(img *image.NRGBA, orientation int) *image.NRGBA {
+	if orientation <= orientNormal || orientation > orientRotate270 {
+		return img
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientTransposed(orientation) {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case orientFlipH:
+				dx, dy = w-1-x, y
+			case orientRotate180:
+				dx, dy = w-1-x, h-1-y
+			case orientFlipV:
+				dx, dy = x, h-1-y
+			case orientTranspose:
+				dx, dy = y, x
+			case orientRotate90:
+				dx, dy = h-1-y, x
+			case orientTransverse:
+				dx, dy = h-1-y, w-1-x
+			case orientRotate270:
+				dx, dy = y, w-1-x
+			}
+			si := img.PixOffset(b.Min.X+x, b.Min.Y+y)
+			di := dst.PixOffset(dx, dy)
+			copy(dst.Pix[di:di+4], img.Pix[si:si+4])
+		}
+	}
+	return dst
+}
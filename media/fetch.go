@@ -0,0 +1,49 @@
+package media
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"golang.org/x/net/context"
+)
+
+// Fetcher fetches media of remote urls, eg: friendfeed media mirrored.
+type Fetcher struct {
+	Client *http.Client
+}
+
+// NewFetcher returns fetcher of client, http.DefaultClient if nil.
+func NewFetcher(client *http.Client) *Fetcher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Fetcher{Client: client}
+}
+
+// Fetch reads content of obj.Url into obj.Content, obj.MimeType set by
+// response if empty.
+func (f *Fetcher) Fetch(ctx context.Context, obj *Object) error {
+	req, err := http.NewRequest("GET", obj.Url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := f.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetch %s: %s", obj.Url, resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if obj.MimeType == "" {
+		obj.MimeType = resp.Header.Get("Content-Type")
+	}
+	obj.Content = body
+	return nil
+}
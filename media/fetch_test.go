@@ -0,0 +1,29 @@
+package media
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/yinhm/friendfeed/cassette"
+	"golang.org/x/net/context"
+)
+
+func TestFetcher(t *testing.T) {
+	Convey("Given recorded media, fetch content and mime type", t, func() {
+		rec, err := cassette.New("testdata/cassettes/fetch", cassette.Replay)
+		So(err, ShouldBeNil)
+		fetcher := NewFetcher(rec.Client())
+
+		obj := &Object{Url: "http://m.friendfeed-media.com/46b97c2da4b7596dfb4f78613d65080cbdca2439"}
+		So(fetcher.Fetch(context.Background(), obj), ShouldBeNil)
+		So(obj.MimeType, ShouldEqual, "image/png")
+		So(bytes.HasPrefix(obj.Content, []byte("\x89PNG")), ShouldBeTrue)
+
+		missing := &Object{Url: "http://m.friendfeed-media.com/missing"}
+		So(fetcher.Fetch(context.Background(), missing), ShouldNotBeNil)
+		// not recorded
+		other := &Object{Url: "http://i.friendfeed.com/other"}
+		So(fetcher.Fetch(context.Background(), other), ShouldNotBeNil)
+	})
+}
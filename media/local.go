@@ -0,0 +1,197 @@
+package media
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/net/context"
+)
+
+const (
+	defaultLocalRoot = "/srv/ff/media"
+	defaultLocalURL  = "/media"
+
+	// mime type of object kept in file of the same path with this suffix
+	mimeTypeSuffix = ".mimetype"
+)
+
+// LocalStorage keeps objects in files under Root by object path, served by
+// httpd at BaseURL, for dev and single box deployments.
+type LocalStorage struct {
+	Root    string
+	BaseURL string
+	// Fetcher fetches content of objects posted by url
+	Fetcher *Fetcher
+}
+
+func NewLocalStorage(config *Config) *LocalStorage {
+	root, baseURL := config.LocalRoot, config.LocalURL
+	if root == "" {
+		root = defaultLocalRoot
+	}
+	if baseURL == "" {
+		baseURL = defaultLocalURL
+	}
+	return &LocalStorage{
+		Root:    root,
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Fetcher: NewFetcher(nil),
+	}
+}
+
+// filename returns file of object path, path escaping root is rejected.
+func (c *LocalStorage) filename(name string) (string, error) {
+	name = path.Clean("/" + name)
+	if name == "/" || strings.HasSuffix(name, mimeTypeSuffix) {
+		return "", fmt.Errorf("bad request: invalid path %q", name)
+	}
+	return filepath.Join(c.Root, filepath.FromSlash(name)), nil
+}
+
+func (c *LocalStorage) Exists(name string) (bool, error) {
+	filename, err := c.filename(name)
+	if err != nil {
+		return false, err
+	}
+	fi, err := os.Stat(filename)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !fi.IsDir(), nil
+}
+
+func (c *LocalStorage) FromUrl(filename, src, mimetype string) (*Object, error) {
+	obj, err := newObject(filename, src, mimetype)
+	if err != nil {
+		return nil, err
+	}
+	return c.Mirror(obj)
+}
+
+// Mirror posts obj if not kept yet.
+func (c *LocalStorage) Mirror(obj *Object) (*Object, error) {
+	ok, err := c.Exists(obj.Path)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return c.Post(obj)
+	}
+	return c.object(obj.Filename, obj.Path), nil
+}
+
+// Post writes obj.Content, content fetched from obj.Url if empty.
+func (c *LocalStorage) Post(obj *Object) (*Object, error) {
+	filename, err := c.filename(obj.Path)
+	if err != nil {
+		return nil, err
+	}
+	if len(obj.Content) == 0 {
+		if err := c.Fetcher.Fetch(context.Background(), obj); err != nil {
+			return nil, err
+		}
+	}
+	mimeType := obj.MimeType
+	if mimeType == "" {
+		mimeType = http.DetectContentType(obj.Content)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return nil, err
+	}
+	if err := writeFile(filename+mimeTypeSuffix, []byte(mimeType)); err != nil {
+		return nil, err
+	}
+	// content written last, object exists once complete
+	if err := writeFile(filename, obj.Content); err != nil {
+		return nil, err
+	}
+
+	newObj := c.object(obj.Filename, obj.Path)
+	newObj.MimeType = mimeType
+	return newObj, nil
+}
+
+func (c *LocalStorage) Delete(name string) error {
+	filename, err := c.filename(name)
+	if err != nil {
+		return err
+	}
+	for _, f := range []string{filename, filename + mimeTypeSuffix} {
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *LocalStorage) object(filename, name string) *Object {
+	name = strings.TrimLeft(path.Clean("/"+name), "/")
+	obj := &Object{
+		Filename: filename,
+		Path:     name,
+		Url:      c.BaseURL + "/" + name,
+	}
+	obj.MimeType, _ = c.mimeType(name)
+	return obj
+}
+
+func (c *LocalStorage) mimeType(name string) (string, error) {
+	filename, err := c.filename(name)
+	if err != nil {
+		return "", err
+	}
+	data, err := ioutil.ReadFile(filename + mimeTypeSuffix)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Open opens file of object name for serving, with mime type of object.
+func (c *LocalStorage) Open(name string) (*os.File, string, error) {
+	filename, err := c.filename(name)
+	if err != nil {
+		return nil, "", err
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, "", err
+	}
+	if fi, err := f.Stat(); err != nil || fi.IsDir() {
+		f.Close()
+		return nil, "", os.ErrNotExist
+	}
+	mimeType, _ := c.mimeType(name)
+	return f, mimeType, nil
+}
+
+// writeFile writes data into filename atomically.
+func writeFile(filename string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), ".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
@@ -0,0 +1,72 @@
+package media
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/yinhm/friendfeed/cassette"
+)
+
+func TestLocalStorage(t *testing.T) {
+	Convey("Given local storage, keep objects under root", t, func() {
+		root, err := ioutil.TempDir("", "media")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(root)
+
+		mc := &Config{KeyFile: "/nonexistent/gcs.json", LocalRoot: root}
+		So(mc.IsLocal(), ShouldBeTrue)
+		storage, err := NewStorage(mc)
+		So(err, ShouldBeNil)
+		fs, ok := storage.(*LocalStorage)
+		So(ok, ShouldBeTrue)
+		rec, err := cassette.New("testdata/cassettes/fetch", cassette.Replay)
+		So(err, ShouldBeNil)
+		fs.Fetcher = NewFetcher(rec.Client())
+
+		obj, err := fs.Post(&Object{Filename: "a.txt", Path: "x/a.txt", Content: []byte("hello")})
+		So(err, ShouldBeNil)
+		So(obj.Path, ShouldEqual, "x/a.txt")
+		So(obj.Url, ShouldEqual, "/media/x/a.txt")
+		So(obj.MimeType, ShouldStartWith, "text/plain")
+		data, _ := ioutil.ReadFile(filepath.Join(root, "x", "a.txt"))
+		So(string(data), ShouldEqual, "hello")
+
+		// mirrored from ff media, fetched once
+		src := "http://m.friendfeed-media.com/46b97c2da4b7596dfb4f78613d65080cbdca2439"
+		obj, err = fs.FromUrl("", src, "")
+		So(err, ShouldBeNil)
+		So(obj.Path, ShouldEqual, "46b97c2da4b7596dfb4f78613d65080cbdca2439")
+		So(obj.MimeType, ShouldEqual, "image/png")
+		So(obj.Url, ShouldEqual, "/media/46b97c2da4b7596dfb4f78613d65080cbdca2439")
+		fs.Fetcher = nil
+		obj, err = fs.FromUrl("", src, "")
+		So(err, ShouldBeNil)
+		So(obj.MimeType, ShouldEqual, "image/png")
+		_, err = fs.FromUrl("", "http://example.com/1.png", "")
+		So(err, ShouldNotBeNil)
+
+		f, mimeType, err := fs.Open("/46b97c2da4b7596dfb4f78613d65080cbdca2439")
+		So(err, ShouldBeNil)
+		f.Close()
+		So(mimeType, ShouldEqual, "image/png")
+
+		ok, err = fs.Exists("x/a.txt")
+		So(ok, ShouldBeTrue)
+		ok, err = fs.Exists("x")
+		So(ok, ShouldBeFalse)
+		ok, err = fs.Exists("missing")
+		So(ok, ShouldBeFalse)
+		So(err, ShouldBeNil)
+
+		// never out of root
+		_, _, err = fs.Open("../../etc/passwd")
+		So(os.IsNotExist(err), ShouldBeTrue)
+		_, _, err = fs.Open("x/a.txt.mimetype")
+		So(err, ShouldNotBeNil)
+		_, _, err = fs.Open("x")
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
 
 	"cloud.google.com/go/storage"
@@ -50,30 +51,34 @@ type Response struct {
 
 // client basic for Imgur mandible server
 func NewClient() *Client {
-	httpClient := http.DefaultClient
-	baseURL, err := url.Parse(apiURL)
+	client, err := NewMandibleClient(apiURL)
 	if err != nil {
 		panic("Error media server address.")
 	}
+	return client
+}
 
-	return &Client{
-		client:  httpClient,
-		BaseURL: baseURL,
+// NewMandibleClient returns client of mandible server at baseURL, optional
+// thumbnailer, see NewThumbnailer.
+func NewMandibleClient(baseURL string) (*Client, error) {
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, err
 	}
+	return &Client{
+		client:  http.DefaultClient,
+		BaseURL: u,
+	}, nil
 }
 
 func (c *Client) PostUrl(imageUrl string) (*Response, error) {
-	thumbs := map[string]thumbConfig{
-		"small": {
-			Width:  175,
-			Height: 175,
+	thumbs := make(map[string]thumbConfig)
+	for _, size := range DefaultThumbSizes {
+		thumbs[size.Name] = thumbConfig{
+			Width:  size.Width,
+			Height: size.Height,
 			Shape:  "thumb",
-		},
-		"large": {
-			Width:  1600,
-			Height: 1600,
-			Shape:  "thumb",
-		},
+		}
 	}
 	buf := new(bytes.Buffer)
 	enc := json.NewEncoder(buf)
@@ -90,10 +95,11 @@ func (c *Client) PostUrl(imageUrl string) (*Response, error) {
 
 	reqUrl := c.BaseURL.String() + "/url"
 	//r, err := http.Post(reqUrl, "application/json", data)
-	r, err := http.PostForm(reqUrl, data)
+	r, err := c.client.PostForm(reqUrl, data)
 	if err != nil {
 		return nil, err
 	}
+	defer r.Body.Close()
 
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
@@ -108,6 +114,27 @@ func (c *Client) PostUrl(imageUrl string) (*Response, error) {
 	return resp, nil
 }
 
+// Thumbnails posts obj.Url to mandible, thumbnails kept by mandible.
+func (c *Client) Thumbnails(obj *Object) (*Thumbs, error) {
+	resp, err := c.PostUrl(obj.Url)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("mandible: %d %s", resp.Status, obj.Url)
+	}
+	thumbs := &Thumbs{Width: resp.Data.Width, Height: resp.Data.Height}
+	for _, size := range DefaultThumbSizes {
+		u, ok := resp.Data.Thumbs[size.Name]
+		if !ok {
+			continue
+		}
+		w, h := size.Fit(resp.Data.Width, resp.Data.Height)
+		thumbs.Thumbs = append(thumbs.Thumbs, &Thumb{Size: size.Name, Width: w, Height: h, Url: u})
+	}
+	return thumbs, nil
+}
+
 // ----------------------------
 // Google Cloud Storage Mirror
 // ----------------------------
@@ -115,6 +142,28 @@ type Config struct {
 	AppId   string `json:"gcs_app_id"`
 	Bucket  string `json:"gcs_bucket"`
 	KeyFile string `json:"gcs_key_file"`
+
+	// LocalRoot is directory of local storage, LocalURL is url media served
+	// by httpd, see LocalStorage.
+	LocalRoot string `json:"media_root"`
+	LocalURL  string `json:"media_url"`
+
+	// MandibleURL of mandible server making thumbnails, thumbnails made
+	// natively if empty.
+	MandibleURL string `json:"mandible_url"`
+
+	// Backend is one of "gcs", "s3" and "local", chosen by IsLocal if empty.
+	Backend string `json:"media_backend"`
+
+	// S3 compatible storage, see S3Storage.
+	S3Endpoint  string `json:"s3_endpoint"`
+	S3Region    string `json:"s3_region"`
+	S3Bucket    string `json:"s3_bucket"`
+	S3AccessKey string `json:"s3_access_key"`
+	S3SecretKey string `json:"s3_secret_key"`
+	S3PathStyle bool   `json:"s3_path_style"`
+	S3ACL       string `json:"s3_acl"`
+	S3PublicURL string `json:"s3_public_url"`
 }
 
 type Object struct {
@@ -124,6 +173,8 @@ type Object struct {
 	MimeType string
 	Url      string
 	Content  []byte
+	// Hash is hex sha256 of content kept by ContentStore
+	Hash string
 }
 
 func NewConfigFromJSON(filename string) (*Config, error) {
@@ -144,34 +195,83 @@ type Storage interface {
 	Post(obj *Object) (*Object, error)
 	Mirror(obj *Object) (*Object, error)
 	FromUrl(filename, src, mimetype string) (*Object, error)
+	// Delete removes object name, not exists is not an error.
+	Delete(name string) error
 }
 
-type LocalStorage struct{}
-
-func NewLocalStorage(config *Config) *LocalStorage {
-	return &LocalStorage{}
-}
+// Media backends of Config.
+const (
+	BackendGCS   = "gcs"
+	BackendS3    = "s3"
+	BackendLocal = "local"
+)
 
-func (c *LocalStorage) Exists(name string) (bool, error) {
-	return false, fmt.Errorf("not implemented yet.")
+// NewStorage returns storage of config.Backend. Without backend configured,
+// local storage if config IsLocal, google storage otherwise.
+func NewStorage(config *Config) (Storage, error) {
+	switch config.Backend {
+	case BackendGCS:
+		return NewGoogleStorage(config), nil
+	case BackendS3:
+		return NewS3Storage(config)
+	case BackendLocal:
+		return NewLocalStorage(config), nil
+	case "":
+		if config.IsLocal() {
+			return NewLocalStorage(config), nil
+		}
+		return NewGoogleStorage(config), nil
+	}
+	return nil, fmt.Errorf("unknown media backend: %s", config.Backend)
 }
 
-func (c *LocalStorage) Mirror(obj *Object) (*Object, error) {
-	return nil, fmt.Errorf("Mirror not implemented yet: %s", obj.Url)
+// IsLocal reports whether media kept in local storage, local backend, or no
+// backend configured but media root or no key file of google storage.
+func (c *Config) IsLocal() bool {
+	if c.Backend != "" {
+		return c.Backend == BackendLocal
+	}
+	if c.LocalRoot != "" {
+		return true
+	}
+	_, err := os.Stat(c.KeyFile)
+	return err != nil
 }
 
-func (c *LocalStorage) FromUrl(filename, src, mimetype string) (*Object, error) {
-	return nil, fmt.Errorf("Mirror not implemented yet: %s", src)
+// IsMirrorable reports whether src is ff media, the only media mirrored.
+func IsMirrorable(src string) bool {
+	parsed, err := url.Parse(src)
+	return err == nil && ff.IsMediaServer(parsed.Host)
 }
 
-func (c *LocalStorage) Post(obj *Object) (*Object, error) {
-	return nil, fmt.Errorf("not implemented yet.")
+// newObject returns object of ff media src to mirror, path of object is the
+// path of src.
+func newObject(filename, src, mimetype string) (*Object, error) {
+	parsed, err := url.Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("Can not parse: %s", src)
+	}
+	if !IsMirrorable(src) {
+		return nil, fmt.Errorf("Skip non-ff: %s", src)
+	}
+	newpath := strings.TrimLeft(parsed.Path, "/")
+	if filename == "" {
+		filename = newpath
+	}
+	return &Object{
+		Filename: filename,
+		Path:     newpath,
+		MimeType: mimetype,
+		Url:      src,
+	}, nil
 }
 
 type GoogleStorage struct {
 	ctx    context.Context
 	bucket string
 	client *storage.Client
+	// Fetcher fetches content of objects posted by url
+	Fetcher *Fetcher
 }
 
 func NewGoogleStorage(config *Config) *GoogleStorage {
@@ -195,41 +295,37 @@ func NewGoogleStorage(config *Config) *GoogleStorage {
 	}
 
 	return &GoogleStorage{
-		ctx:    ctx,
-		bucket: config.Bucket,
-		client: client,
+		ctx:     ctx,
+		bucket:  config.Bucket,
+		client:  client,
+		Fetcher: NewFetcher(nil),
 	}
 }
 
 func (c *GoogleStorage) Exists(name string) (bool, error) {
 	_, err := c.client.Bucket(c.bucket).Object(name).Attrs(c.ctx)
 	if err == storage.ErrObjectNotExist {
+		return false, nil
+	}
+	if err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
+func (c *GoogleStorage) Delete(name string) error {
+	err := c.client.Bucket(c.bucket).Object(name).Delete(c.ctx)
+	if err == storage.ErrObjectNotExist {
+		return nil
+	}
+	return err
+}
+
 func (c *GoogleStorage) FromUrl(filename, src, mimetype string) (*Object, error) {
-	parsed, err := url.Parse(src)
+	obj, err := newObject(filename, src, mimetype)
 	if err != nil {
-		return nil, fmt.Errorf("Can not parse: %s", src)
-	}
-	if !ff.IsMediaServer(parsed.Host) {
-		return nil, fmt.Errorf("Skip non-ff: %s", src)
-	}
-	newpath := strings.TrimLeft(parsed.Path, "/")
-	if filename == "" {
-		filename = newpath
-	}
-	obj := &Object{
-		Filename: filename,
-		Path:     newpath,
-		Url:      src,
-	}
-	if mimetype != "" {
-		obj.MimeType = mimetype
+		return nil, err
 	}
-
 	return c.Mirror(obj)
 }
 
@@ -251,11 +347,13 @@ func (c *GoogleStorage) Mirror(obj *Object) (*Object, error) {
 	return newObj, nil
 }
 
+// Post uploads obj.Content, content fetched from obj.Url if empty.
 func (c *GoogleStorage) Post(obj *Object) (*Object, error) {
-	_, err := c.fetch(obj)
-	if err != nil {
-		log.Println("error on read url:", obj.Url, err)
-		return nil, err
+	if len(obj.Content) == 0 {
+		if err := c.Fetcher.Fetch(c.ctx, obj); err != nil {
+			log.Println("error on read url:", obj.Url, err)
+			return nil, err
+		}
 	}
 
 	// path = obj.path
@@ -282,25 +380,3 @@ func (c *GoogleStorage) Post(obj *Object) (*Object, error) {
 
 	return newObj, nil
 }
-
-// fetch file from url
-func (c *GoogleStorage) fetch(obj *Object) (*http.Response, error) {
-	resp, err := http.Get(obj.Url)
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return resp, err
-	}
-
-	mimeType := resp.Header.Get("Content-Type")
-	// contentDisposition := resp.Header.Get("Content-Disposition")
-	if obj.MimeType == "" {
-		obj.MimeType = mimeType
-	}
-
-	obj.Content = body
-	return resp, nil
-}
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
 )
 
 var (
@@ -106,15 +107,9 @@ func TestDownloadAttachment(t *testing.T) {
 			MimeType: "image/png",
 		}
 
-		resp, err := client.fetch(obj)
+		err := client.Fetcher.Fetch(context.Background(), obj)
 		So(err, ShouldBeNil)
-
-		mimetype := resp.Header.Get("Content-Type")
-		So(mimetype, ShouldEqual, "image/png")
-
-		actual := resp.Header.Get("Content-Length")
-		So(actual, ShouldEqual, "64313")
-
+		So(obj.MimeType, ShouldEqual, "image/png")
 		So(len(obj.Content), ShouldEqual, 64313)
 
 		newObj, err := client.Post(obj)
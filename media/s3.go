@@ -0,0 +1,324 @@
+package media
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+const (
+	defaultS3Endpoint = "https://s3.amazonaws.com"
+	defaultS3Region   = "us-east-1"
+	s3Timeout         = 60 * time.Second
+
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// S3Storage keeps objects in bucket of S3 compatible api, eg: AWS, MinIO.
+// Requests signed by AWS signature version 4.
+type S3Storage struct {
+	Endpoint  *url.URL
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PathStyle addresses bucket by path instead of host, as MinIO does.
+	PathStyle bool
+	// ACL of objects posted if not empty, eg: public-read.
+	ACL string
+	// PublicURL is base url of objects served, url of the api if empty.
+	PublicURL string
+
+	Client *http.Client
+	// Fetcher fetches content of objects posted by url
+	Fetcher *Fetcher
+	// now returns time of signing, for testing
+	now func() time.Time
+}
+
+func NewS3Storage(config *Config) (*S3Storage, error) {
+	if config.S3Bucket == "" {
+		return nil, fmt.Errorf("s3: no bucket configured")
+	}
+	endpoint := config.S3Endpoint
+	if endpoint == "" {
+		endpoint = defaultS3Endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("s3: invalid endpoint %q", endpoint)
+	}
+	region := config.S3Region
+	if region == "" {
+		region = defaultS3Region
+	}
+	return &S3Storage{
+		Endpoint:  u,
+		Region:    region,
+		Bucket:    config.S3Bucket,
+		AccessKey: config.S3AccessKey,
+		SecretKey: config.S3SecretKey,
+		PathStyle: config.S3PathStyle,
+		ACL:       config.S3ACL,
+		PublicURL: strings.TrimRight(config.S3PublicURL, "/"),
+		Client:    &http.Client{Timeout: s3Timeout},
+		Fetcher:   NewFetcher(nil),
+		now:       time.Now,
+	}, nil
+}
+
+func (c *S3Storage) Exists(name string) (bool, error) {
+	_, ok, err := c.head(name)
+	return ok, err
+}
+
+func (c *S3Storage) FromUrl(filename, src, mimetype string) (*Object, error) {
+	obj, err := newObject(filename, src, mimetype)
+	if err != nil {
+		return nil, err
+	}
+	return c.Mirror(obj)
+}
+
+// Mirror posts obj if not in bucket yet.
+func (c *S3Storage) Mirror(obj *Object) (*Object, error) {
+	mimeType, ok, err := c.head(obj.Path)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return c.Post(obj)
+	}
+	newObj := c.object(obj)
+	newObj.MimeType = mimeType
+	return newObj, nil
+}
+
+// Post uploads obj.Content, content fetched from obj.Url if empty.
+func (c *S3Storage) Post(obj *Object) (*Object, error) {
+	if len(obj.Content) == 0 {
+		if err := c.Fetcher.Fetch(context.Background(), obj); err != nil {
+			return nil, err
+		}
+	}
+	if obj.MimeType == "" {
+		obj.MimeType = http.DetectContentType(obj.Content)
+	}
+
+	req, err := http.NewRequest("PUT", c.objectURL(obj.Path), bytes.NewReader(obj.Content))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", obj.MimeType)
+	if c.ACL != "" {
+		req.Header.Set("X-Amz-Acl", c.ACL)
+	}
+	sum := sha256.Sum256(obj.Content)
+	resp, err := c.do(req, hex.EncodeToString(sum[:]))
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	newObj := c.object(obj)
+	newObj.MimeType = obj.MimeType
+	return newObj, nil
+}
+
+func (c *S3Storage) Delete(name string) error {
+	req, err := http.NewRequest("DELETE", c.objectURL(name), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req, emptyPayloadHash)
+	if err != nil {
+		if e, ok := err.(*S3Error); ok && e.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// head returns content type of object name, false if not exists.
+func (c *S3Storage) head(name string) (string, bool, error) {
+	req, err := http.NewRequest("HEAD", c.objectURL(name), nil)
+	if err != nil {
+		return "", false, err
+	}
+	resp, err := c.do(req, emptyPayloadHash)
+	if err != nil {
+		if e, ok := err.(*S3Error); ok && e.StatusCode == http.StatusNotFound {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	resp.Body.Close()
+	return resp.Header.Get("Content-Type"), true, nil
+}
+
+func (c *S3Storage) object(obj *Object) *Object {
+	return &Object{
+		Filename: obj.Filename,
+		Bucket:   c.Bucket,
+		Path:     obj.Path,
+		Url:      c.publicURL(obj.Path),
+	}
+}
+
+// objectURL returns api url of object key.
+func (c *S3Storage) objectURL(key string) string {
+	u := *c.Endpoint
+	if c.PathStyle {
+		u.Path = "/" + c.Bucket + "/" + key
+	} else {
+		u.Host = c.Bucket + "." + u.Host
+		u.Path = "/" + key
+	}
+	u.RawPath = ""
+	return u.Scheme + "://" + u.Host + s3Escape(u.Path)
+}
+
+func (c *S3Storage) publicURL(key string) string {
+	if c.PublicURL != "" {
+		return c.PublicURL + s3Escape("/"+key)
+	}
+	return c.objectURL(key)
+}
+
+// S3Error is error response of S3 api.
+type S3Error struct {
+	StatusCode int    `xml:"-"`
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+	Key        string `xml:"Key"`
+}
+
+func (e *S3Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("s3: %d %s", e.StatusCode, e.Key)
+	}
+	return fmt.Sprintf("s3: %d %s: %s %s", e.StatusCode, e.Key, e.Code, e.Message)
+}
+
+func (c *S3Storage) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	c.sign(req, payloadHash, c.now())
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 == 2 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	e := &S3Error{StatusCode: resp.StatusCode}
+	if data, err := ioutil.ReadAll(resp.Body); err == nil && len(data) > 0 {
+		xml.Unmarshal(data, e)
+	}
+	if e.Key == "" {
+		e.Key = req.URL.Path
+	}
+	return nil, e
+}
+
+// sign signs req by AWS signature version 4, host, content type, range and
+// x-amz-* headers signed.
+func (c *S3Storage) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		k = strings.ToLower(k)
+		if k == "content-type" || k == "content-md5" || k == "range" || strings.HasPrefix(k, "x-amz-") {
+			headers[k] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+	var names []string
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3Escape(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + c.Region + "/s3/aws4_request"
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+c.SecretKey), date)
+	key = hmacSHA256(key, c.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.AccessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func canonicalQuery(values url.Values) string {
+	var pairs []string
+	for k, vs := range values {
+		for _, v := range vs {
+			pairs = append(pairs, s3EscapeComponent(k)+"="+s3EscapeComponent(v))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// s3Escape escapes path as S3 does, slashes kept.
+func s3Escape(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		parts[i] = s3EscapeComponent(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// s3EscapeComponent escapes all but unreserved characters.
+func s3EscapeComponent(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if 'A' <= ch && ch <= 'Z' || 'a' <= ch && ch <= 'z' || '0' <= ch && ch <= '9' ||
+			ch == '-' || ch == '.' || ch == '_' || ch == '~' {
+			b.WriteByte(ch)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", ch)
+	}
+	return b.String()
+}
@@ -0,0 +1,213 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"golang.org/x/net/context"
+)
+
+const (
+	defaultJPEGQuality = 85
+	// images larger are not decoded
+	defaultMaxPixels = 50 * 1000 * 1000
+)
+
+// ThumbSize is a box thumbnails fit into, aspect ratio kept.
+type ThumbSize struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+// Sizes of thumbnails, the same as mandible generated.
+var (
+	ThumbSmall = ThumbSize{"small", 175, 175}
+	ThumbLarge = ThumbSize{"large", 1600, 1600}
+
+	DefaultThumbSizes = []ThumbSize{ThumbSmall, ThumbLarge}
+)
+
+// Fit returns size of image of width and height fit into box, never
+// upscaled.
+func (s ThumbSize) Fit(width, height int) (int, int) {
+	if width <= 0 || height <= 0 || (width <= s.Width && height <= s.Height) {
+		return width, height
+	}
+	if width*s.Height > height*s.Width {
+		h := (height*s.Width + width/2) / width
+		if h < 1 {
+			h = 1
+		}
+		return s.Width, h
+	}
+	w := (width*s.Height + height/2) / height
+	if w < 1 {
+		w = 1
+	}
+	return w, s.Height
+}
+
+// Thumb is a thumbnail of size.
+type Thumb struct {
+	Size   string
+	Width  int
+	Height int
+	Url    string
+	// Hash of content if kept by ContentStore
+	Hash string
+}
+
+// Thumbs is thumbnails of an image, Width and Height are of the image
+// displayed upright.
+type Thumbs struct {
+	Width  int
+	Height int
+	Thumbs []*Thumb
+}
+
+// Get returns thumbnail of size name, nil if not generated.
+func (t *Thumbs) Get(size string) *Thumb {
+	for _, thumb := range t.Thumbs {
+		if thumb.Size == size {
+			return thumb
+		}
+	}
+	return nil
+}
+
+// Thumbnailer makes thumbnails of image obj, obj.Url is the source of image.
+type Thumbnailer interface {
+	Thumbnails(obj *Object) (*Thumbs, error)
+}
+
+// NewThumbnailer returns mandible client if configured, thumbnails made
+// natively and kept in storage otherwise.
+func NewThumbnailer(config *Config, storage Storage) (Thumbnailer, error) {
+	if config.MandibleURL != "" {
+		return NewMandibleClient(config.MandibleURL)
+	}
+	return NewImageThumbnailer(storage), nil
+}
+
+// ImageThumbnailer decodes jpeg, png, gif and webp images, orients them by
+// exif, resizes them into Sizes and posts them to Storage. Thumbnails are
+// encoded as jpeg, or png if transparent.
+type ImageThumbnailer struct {
+	Storage Storage
+	Sizes   []ThumbSize
+	Quality int
+	// MaxPixels of images decoded
+	MaxPixels int
+	// Fetcher fetches content of images by url
+	Fetcher *Fetcher
+}
+
+func NewImageThumbnailer(storage Storage) *ImageThumbnailer {
+	return &ImageThumbnailer{
+		Storage:   storage,
+		Sizes:     DefaultThumbSizes,
+		Quality:   defaultJPEGQuality,
+		MaxPixels: defaultMaxPixels,
+		Fetcher:   NewFetcher(nil),
+	}
+}
+
+// Thumbnails makes thumbnails of obj.Content, fetched from obj.Url if empty.
+func (t *ImageThumbnailer) Thumbnails(obj *Object) (*Thumbs, error) {
+	if len(obj.Content) == 0 {
+		src := &Object{Url: obj.Url}
+		if err := t.Fetcher.Fetch(context.Background(), src); err != nil {
+			return nil, err
+		}
+		obj = &Object{Filename: obj.Filename, Path: obj.Path, Url: obj.Url, Content: src.Content, Hash: obj.Hash}
+	}
+
+	img, orientation, err := t.decode(obj.Content)
+	if err != nil {
+		return nil, err
+	}
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	if orientTransposed(orientation) {
+		width, height = height, width
+	}
+
+	name := obj.Hash
+	if name == "" {
+		name = ContentHash(obj.Content)
+	}
+	thumbs := &Thumbs{Width: width, Height: height}
+	for _, size := range t.Sizes {
+		w, h := size.Fit(width, height)
+		// resized before oriented
+		sw, sh := w, h
+		if orientTransposed(orientation) {
+			sw, sh = h, w
+		}
+		dst := image.NewNRGBA(image.Rect(0, 0, sw, sh))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+
+		content, mimeType, err := t.encode(orient(dst, orientation))
+		if err != nil {
+			return nil, err
+		}
+		newObj, err := t.Storage.Post(&Object{
+			Filename: size.Name,
+			Path:     "t/" + name + "/" + size.Name,
+			MimeType: mimeType,
+			Content:  content,
+		})
+		if err != nil {
+			return nil, err
+		}
+		thumbs.Thumbs = append(thumbs.Thumbs, &Thumb{
+			Size:   size.Name,
+			Width:  w,
+			Height: h,
+			Url:    newObj.Url,
+			Hash:   newObj.Hash,
+		})
+	}
+	return thumbs, nil
+}
+
+// decode returns the first frame of image content, with exif orientation.
+func (t *ImageThumbnailer) decode(content []byte) (image.Image, int, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, 0, err
+	}
+	if t.MaxPixels > 0 && config.Width*config.Height > t.MaxPixels {
+		return nil, 0, fmt.Errorf("image too large: %dx%d", config.Width, config.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, 0, fmt.Errorf("decode %s: %v", format, err)
+	}
+	return img, exifOrientation(content), nil
+}
+
+func (t *ImageThumbnailer) encode(img *image.NRGBA) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if !img.Opaque() {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/png", nil
+	}
+	quality := t.Quality
+	if quality <= 0 {
+		quality = defaultJPEGQuality
+	}
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/jpeg", nil
+}
@@ -0,0 +1,216 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// jpegWithOrientation returns jpeg of img with exif orientation.
+func jpegWithOrientation(img image.Image, orientation uint16) []byte {
+	var buf bytes.Buffer
+	jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95})
+	content := buf.Bytes()
+
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01")
+	entry := make([]byte, 12)
+	binary.BigEndian.PutUint16(entry[0:], exifTagOrientation)
+	binary.BigEndian.PutUint16(entry[2:], 3) // SHORT
+	binary.BigEndian.PutUint32(entry[4:], 1)
+	binary.BigEndian.PutUint16(entry[8:], orientation)
+	tiff = append(tiff, entry...)
+	tiff = append(tiff, 0, 0, 0, 0)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xff, 0xe1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	out := append([]byte{}, content[:2]...)
+	out = append(out, app1...)
+	return append(out, content[2:]...)
+}
+
+// halves returns image of red left half and blue right half.
+func halves(w, h int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.NRGBA{255, 0, 0, 255}
+			if x >= w/2 {
+				c = color.NRGBA{0, 0, 255, 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func decodeThumb(root, url string) (image.Image, string) {
+	data, err := ioutil.ReadFile(filepath.Join(root, url[len(defaultLocalURL):]))
+	if err != nil {
+		return nil, ""
+	}
+	img, format, _ := image.Decode(bytes.NewReader(data))
+	return img, format
+}
+
+func TestThumbSizeFit(t *testing.T) {
+	Convey("Images fit into box, never upscaled", t, func() {
+		w, h := ThumbSmall.Fit(350, 175)
+		So(fmt.Sprint(w, h), ShouldEqual, "175 88")
+		w, h = ThumbSmall.Fit(175, 700)
+		So(fmt.Sprint(w, h), ShouldEqual, "44 175")
+		w, h = ThumbSmall.Fit(100, 50)
+		So(fmt.Sprint(w, h), ShouldEqual, "100 50")
+		w, h = ThumbSmall.Fit(10000, 1)
+		So(fmt.Sprint(w, h), ShouldEqual, "175 1")
+	})
+}
+
+func TestExifOrientation(t *testing.T) {
+	Convey("Orientation read from exif of jpeg", t, func() {
+		content := jpegWithOrientation(halves(4, 2), orientRotate90)
+		So(exifOrientation(content), ShouldEqual, orientRotate90)
+		_, err := jpeg.Decode(bytes.NewReader(content))
+		So(err, ShouldBeNil)
+
+		var buf bytes.Buffer
+		jpeg.Encode(&buf, halves(4, 2), nil)
+		So(exifOrientation(buf.Bytes()), ShouldEqual, orientNormal)
+		So(exifOrientation([]byte("not an image")), ShouldEqual, orientNormal)
+		// odd sized last chunk without pad byte
+		So(exifOrientation([]byte("RIFF\x0d\x00\x00\x00WEBPVP8X\x01\x00\x00\x00A")), ShouldEqual, orientNormal)
+	})
+
+	Convey("Images oriented upright", t, func() {
+		// 2x1, red then blue
+		img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+		img.Set(0, 0, color.NRGBA{255, 0, 0, 255})
+		img.Set(1, 0, color.NRGBA{0, 0, 255, 255})
+		red := color.NRGBA{255, 0, 0, 255}
+
+		cases := map[int]image.Point{
+			orientNormal:     {0, 0},
+			orientFlipH:      {1, 0},
+			orientRotate180:  {1, 0},
+			orientFlipV:      {0, 0},
+			orientTranspose:  {0, 0},
+			orientRotate90:   {0, 0},
+			orientTransverse: {0, 1},
+			orientRotate270:  {0, 1},
+		}
+		for o, p := range cases {
+			dst := orient(img, o)
+			So(dst.NRGBAAt(p.X, p.Y), ShouldResemble, red)
+			if orientTransposed(o) {
+				So(dst.Bounds().Dx(), ShouldEqual, 1)
+			} else {
+				So(dst.Bounds().Dx(), ShouldEqual, 2)
+			}
+		}
+	})
+}
+
+func TestImageThumbnailer(t *testing.T) {
+	Convey("Given image thumbnailer of local storage", t, func() {
+		root, err := ioutil.TempDir("", "media")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(root)
+
+		thumbnailer := NewImageThumbnailer(NewLocalStorage(&Config{LocalRoot: root}))
+		thumbnailer.Sizes = []ThumbSize{{"small", 10, 10}, {"large", 100, 100}}
+
+		// rotated 90 cw, red half on top
+		content := jpegWithOrientation(halves(40, 20), orientRotate90)
+		thumbs, err := thumbnailer.Thumbnails(&Object{Content: content})
+		So(err, ShouldBeNil)
+		So(thumbs.Width, ShouldEqual, 20)
+		So(thumbs.Height, ShouldEqual, 40)
+		So(len(thumbs.Thumbs), ShouldEqual, 2)
+
+		small := thumbs.Get("small")
+		So(small.Width, ShouldEqual, 5)
+		So(small.Height, ShouldEqual, 10)
+		So(small.Url, ShouldEqual, "/media/t/"+ContentHash(content)+"/small")
+		img, format := decodeThumb(root, small.Url)
+		So(format, ShouldEqual, "jpeg")
+		So(img.Bounds().Dx(), ShouldEqual, 5)
+		So(img.Bounds().Dy(), ShouldEqual, 10)
+		r, _, b, _ := img.At(2, 1).RGBA()
+		So(r > b, ShouldBeTrue)
+		r, _, b, _ = img.At(2, 8).RGBA()
+		So(b > r, ShouldBeTrue)
+
+		// not upscaled
+		large := thumbs.Get("large")
+		So(large.Width, ShouldEqual, 20)
+		So(large.Height, ShouldEqual, 40)
+		So(thumbs.Get("medium"), ShouldBeNil)
+
+		// transparent png kept png
+		transparent := image.NewNRGBA(image.Rect(0, 0, 30, 30))
+		var buf bytes.Buffer
+		So(png.Encode(&buf, transparent), ShouldBeNil)
+		thumbs, err = thumbnailer.Thumbnails(&Object{Content: buf.Bytes(), Hash: "h1"})
+		So(err, ShouldBeNil)
+		So(thumbs.Get("small").Url, ShouldEqual, "/media/t/h1/small")
+		_, format = decodeThumb(root, thumbs.Get("small").Url)
+		So(format, ShouldEqual, "png")
+
+		webp, err := ioutil.ReadFile("testdata/video-001.webp")
+		So(err, ShouldBeNil)
+		thumbs, err = thumbnailer.Thumbnails(&Object{Content: webp})
+		So(err, ShouldBeNil)
+		So(thumbs.Width, ShouldEqual, 150)
+		So(thumbs.Height, ShouldEqual, 103)
+		So(thumbs.Get("large").Width, ShouldEqual, 100)
+		So(thumbs.Get("large").Height, ShouldEqual, 69)
+
+		_, err = thumbnailer.Thumbnails(&Object{Content: []byte("not an image")})
+		So(err, ShouldNotBeNil)
+
+		thumbnailer.MaxPixels = 100
+		_, err = thumbnailer.Thumbnails(&Object{Content: content})
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestMandibleThumbnails(t *testing.T) {
+	Convey("Given mandible configured, thumbnails kept by mandible", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"data": {"width": 350, "height": 3500, "thumbs": {
+				"small": "https://s3.amazonaws.com/gophergala/t/CUqU4If/small",
+				"large": "https://s3.amazonaws.com/gophergala/t/CUqU4If/large"
+			}}, "status": 200, "success": true}`)
+		}))
+		defer ts.Close()
+
+		thumbnailer, err := NewThumbnailer(&Config{MandibleURL: ts.URL}, nil)
+		So(err, ShouldBeNil)
+		thumbs, err := thumbnailer.Thumbnails(&Object{Url: "http://m.friendfeed-media.com/a"})
+		So(err, ShouldBeNil)
+		So(thumbs.Width, ShouldEqual, 350)
+		small := thumbs.Get("small")
+		So(small.Url, ShouldEqual, "https://s3.amazonaws.com/gophergala/t/CUqU4If/small")
+		So(small.Width, ShouldEqual, 18)
+		So(small.Height, ShouldEqual, 175)
+
+		thumbnailer, err = NewThumbnailer(&Config{}, nil)
+		So(err, ShouldBeNil)
+		_, ok := thumbnailer.(*ImageThumbnailer)
+		So(ok, ShouldBeTrue)
+	})
+}
@@ -418,8 +418,16 @@ type FeedRequest struct {
 	// NOTICE: this is not the same as original friendfeed api
 	// auto should be default it not set.
 	// if max_comments set to 1, then all comments should returned.
-	MaxComments          int32    `protobuf:"varint,5,opt,name=max_comments,json=maxComments,proto3" json:"max_comments,omitempty"`
-	MaxLikes             int32    `protobuf:"varint,6,opt,name=max_likes,json=maxLikes,proto3" json:"max_likes,omitempty"`
+	// if max_comments set to -1, then do not include any comments.
+	MaxComments int32 `protobuf:"varint,5,opt,name=max_comments,json=maxComments,proto3" json:"max_comments,omitempty"`
+	MaxLikes    int32 `protobuf:"varint,6,opt,name=max_likes,json=maxLikes,proto3" json:"max_likes,omitempty"`
+	// user uuid, entries hidden by the user are excluded from the response.
+	User string `protobuf:"bytes,7,opt,name=user,proto3" json:"user,omitempty"`
+	// hidden=1 - If specified, include hidden entries in the response. Hidden
+	// entries include the additional property hidden.
+	Hidden bool `protobuf:"varint,8,opt,name=hidden,proto3" json:"hidden,omitempty"`
+	// search words, entries contain all words in body are returned.
+	Query                string   `protobuf:"bytes,9,opt,name=query,proto3" json:"query,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -492,284 +500,1877 @@ func (m *FeedRequest) GetMaxLikes() int32 {
 	return 0
 }
 
+func (m *FeedRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *FeedRequest) GetHidden() bool {
+	if m != nil {
+		return m.Hidden
+	}
+	return false
+}
+
+func (m *FeedRequest) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
+}
+
 type EntryRequest struct {
-	Uuid                 string   `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Uuid string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	// same as FeedRequest
+	MaxComments          int32    `protobuf:"varint,2,opt,name=max_comments,json=maxComments,proto3" json:"max_comments,omitempty"`
+	MaxLikes             int32    `protobuf:"varint,3,opt,name=max_likes,json=maxLikes,proto3" json:"max_likes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EntryRequest) Reset()         { *m = EntryRequest{} }
+func (m *EntryRequest) String() string { return proto.CompactTextString(m) }
+func (*EntryRequest) ProtoMessage()    {}
+func (*EntryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{6}
+}
+
+func (m *EntryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EntryRequest.Unmarshal(m, b)
+}
+func (m *EntryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EntryRequest.Marshal(b, m, deterministic)
+}
+func (m *EntryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EntryRequest.Merge(m, src)
+}
+func (m *EntryRequest) XXX_Size() int {
+	return xxx_messageInfo_EntryRequest.Size(m)
+}
+func (m *EntryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EntryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EntryRequest proto.InternalMessageInfo
+
+func (m *EntryRequest) GetUuid() string {
+	if m != nil {
+		return m.Uuid
+	}
+	return ""
+}
+
+func (m *EntryRequest) GetMaxComments() int32 {
+	if m != nil {
+		return m.MaxComments
+	}
+	return 0
+}
+
+func (m *EntryRequest) GetMaxLikes() int32 {
+	if m != nil {
+		return m.MaxLikes
+	}
+	return 0
+}
+
+type ProfileRequest struct {
+	Uuid string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	// feed id, eg: yinhm, used if uuid is empty
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ProfileRequest) Reset()         { *m = ProfileRequest{} }
+func (m *ProfileRequest) String() string { return proto.CompactTextString(m) }
+func (*ProfileRequest) ProtoMessage()    {}
+func (*ProfileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{7}
+}
+
+func (m *ProfileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProfileRequest.Unmarshal(m, b)
+}
+func (m *ProfileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProfileRequest.Marshal(b, m, deterministic)
+}
+func (m *ProfileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProfileRequest.Merge(m, src)
+}
+func (m *ProfileRequest) XXX_Size() int {
+	return xxx_messageInfo_ProfileRequest.Size(m)
+}
+func (m *ProfileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProfileRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProfileRequest proto.InternalMessageInfo
+
+func (m *ProfileRequest) GetUuid() string {
+	if m != nil {
+		return m.Uuid
+	}
+	return ""
+}
+
+func (m *ProfileRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type LikeRequest struct {
+	Entry                string   `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	User                 string   `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Like                 bool     `protobuf:"varint,3,opt,name=like,proto3" json:"like,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LikeRequest) Reset()         { *m = LikeRequest{} }
+func (m *LikeRequest) String() string { return proto.CompactTextString(m) }
+func (*LikeRequest) ProtoMessage()    {}
+func (*LikeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{8}
+}
+
+func (m *LikeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LikeRequest.Unmarshal(m, b)
+}
+func (m *LikeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LikeRequest.Marshal(b, m, deterministic)
+}
+func (m *LikeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LikeRequest.Merge(m, src)
+}
+func (m *LikeRequest) XXX_Size() int {
+	return xxx_messageInfo_LikeRequest.Size(m)
+}
+func (m *LikeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LikeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LikeRequest proto.InternalMessageInfo
+
+func (m *LikeRequest) GetEntry() string {
+	if m != nil {
+		return m.Entry
+	}
+	return ""
+}
+
+func (m *LikeRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *LikeRequest) GetLike() bool {
+	if m != nil {
+		return m.Like
+	}
+	return false
+}
+
+type CommentRequest struct {
+	Entry                string   `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	Comment              *Comment `protobuf:"bytes,2,opt,name=comment,proto3" json:"comment,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CommentRequest) Reset()         { *m = CommentRequest{} }
+func (m *CommentRequest) String() string { return proto.CompactTextString(m) }
+func (*CommentRequest) ProtoMessage()    {}
+func (*CommentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{9}
+}
+
+func (m *CommentRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CommentRequest.Unmarshal(m, b)
+}
+func (m *CommentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CommentRequest.Marshal(b, m, deterministic)
+}
+func (m *CommentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CommentRequest.Merge(m, src)
+}
+func (m *CommentRequest) XXX_Size() int {
+	return xxx_messageInfo_CommentRequest.Size(m)
+}
+func (m *CommentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CommentRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CommentRequest proto.InternalMessageInfo
+
+func (m *CommentRequest) GetEntry() string {
+	if m != nil {
+		return m.Entry
+	}
+	return ""
+}
+
+func (m *CommentRequest) GetComment() *Comment {
+	if m != nil {
+		return m.Comment
+	}
+	return nil
+}
+
+type CommentDeleteRequest struct {
+	Entry                string   `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	Comment              string   `protobuf:"bytes,2,opt,name=comment,proto3" json:"comment,omitempty"`
+	User                 string   `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *EntryRequest) Reset()         { *m = EntryRequest{} }
-func (m *EntryRequest) String() string { return proto.CompactTextString(m) }
-func (*EntryRequest) ProtoMessage()    {}
-func (*EntryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{6}
+func (m *CommentDeleteRequest) Reset()         { *m = CommentDeleteRequest{} }
+func (m *CommentDeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*CommentDeleteRequest) ProtoMessage()    {}
+func (*CommentDeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{10}
+}
+
+func (m *CommentDeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CommentDeleteRequest.Unmarshal(m, b)
+}
+func (m *CommentDeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CommentDeleteRequest.Marshal(b, m, deterministic)
+}
+func (m *CommentDeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CommentDeleteRequest.Merge(m, src)
+}
+func (m *CommentDeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_CommentDeleteRequest.Size(m)
+}
+func (m *CommentDeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CommentDeleteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CommentDeleteRequest proto.InternalMessageInfo
+
+func (m *CommentDeleteRequest) GetEntry() string {
+	if m != nil {
+		return m.Entry
+	}
+	return ""
+}
+
+func (m *CommentDeleteRequest) GetComment() string {
+	if m != nil {
+		return m.Comment
+	}
+	return ""
+}
+
+func (m *CommentDeleteRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+type EntryEditRequest struct {
+	Entry string `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	// user id
+	User                 string   `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Body                 string   `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	RawBody              string   `protobuf:"bytes,4,opt,name=raw_body,json=rawBody,proto3" json:"raw_body,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EntryEditRequest) Reset()         { *m = EntryEditRequest{} }
+func (m *EntryEditRequest) String() string { return proto.CompactTextString(m) }
+func (*EntryEditRequest) ProtoMessage()    {}
+func (*EntryEditRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{11}
+}
+
+func (m *EntryEditRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EntryEditRequest.Unmarshal(m, b)
+}
+func (m *EntryEditRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EntryEditRequest.Marshal(b, m, deterministic)
+}
+func (m *EntryEditRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EntryEditRequest.Merge(m, src)
+}
+func (m *EntryEditRequest) XXX_Size() int {
+	return xxx_messageInfo_EntryEditRequest.Size(m)
+}
+func (m *EntryEditRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EntryEditRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EntryEditRequest proto.InternalMessageInfo
+
+func (m *EntryEditRequest) GetEntry() string {
+	if m != nil {
+		return m.Entry
+	}
+	return ""
+}
+
+func (m *EntryEditRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *EntryEditRequest) GetBody() string {
+	if m != nil {
+		return m.Body
+	}
+	return ""
+}
+
+func (m *EntryEditRequest) GetRawBody() string {
+	if m != nil {
+		return m.RawBody
+	}
+	return ""
+}
+
+type EntryDeleteRequest struct {
+	Entry string `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	// user id
+	User                 string   `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EntryDeleteRequest) Reset()         { *m = EntryDeleteRequest{} }
+func (m *EntryDeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*EntryDeleteRequest) ProtoMessage()    {}
+func (*EntryDeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{12}
+}
+
+func (m *EntryDeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EntryDeleteRequest.Unmarshal(m, b)
+}
+func (m *EntryDeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EntryDeleteRequest.Marshal(b, m, deterministic)
+}
+func (m *EntryDeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EntryDeleteRequest.Merge(m, src)
+}
+func (m *EntryDeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_EntryDeleteRequest.Size(m)
+}
+func (m *EntryDeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EntryDeleteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EntryDeleteRequest proto.InternalMessageInfo
+
+func (m *EntryDeleteRequest) GetEntry() string {
+	if m != nil {
+		return m.Entry
+	}
+	return ""
+}
+
+func (m *EntryDeleteRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+// hide or unhide an entry or all entries of an author for the user.
+type HideRequest struct {
+	// user uuid
+	User string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// entry uuid or author id
+	Target               string   `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	Hide                 bool     `protobuf:"varint,3,opt,name=hide,proto3" json:"hide,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HideRequest) Reset()         { *m = HideRequest{} }
+func (m *HideRequest) String() string { return proto.CompactTextString(m) }
+func (*HideRequest) ProtoMessage()    {}
+func (*HideRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{13}
+}
+
+func (m *HideRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HideRequest.Unmarshal(m, b)
+}
+func (m *HideRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HideRequest.Marshal(b, m, deterministic)
+}
+func (m *HideRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HideRequest.Merge(m, src)
+}
+func (m *HideRequest) XXX_Size() int {
+	return xxx_messageInfo_HideRequest.Size(m)
+}
+func (m *HideRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HideRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HideRequest proto.InternalMessageInfo
+
+func (m *HideRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *HideRequest) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func (m *HideRequest) GetHide() bool {
+	if m != nil {
+		return m.Hide
+	}
+	return false
+}
+
+type SupRequest struct {
+	// unix timestamp
+	Since                int64    `protobuf:"varint,1,opt,name=since,proto3" json:"since,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SupRequest) Reset()         { *m = SupRequest{} }
+func (m *SupRequest) String() string { return proto.CompactTextString(m) }
+func (*SupRequest) ProtoMessage()    {}
+func (*SupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{14}
+}
+
+func (m *SupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SupRequest.Unmarshal(m, b)
+}
+func (m *SupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SupRequest.Marshal(b, m, deterministic)
+}
+func (m *SupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SupRequest.Merge(m, src)
+}
+func (m *SupRequest) XXX_Size() int {
+	return xxx_messageInfo_SupRequest.Size(m)
+}
+func (m *SupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SupRequest proto.InternalMessageInfo
+
+func (m *SupRequest) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+type SupUpdate struct {
+	SupId string `protobuf:"bytes,1,opt,name=sup_id,json=supId,proto3" json:"sup_id,omitempty"`
+	// unix timestamp
+	Updated              int64    `protobuf:"varint,2,opt,name=updated,proto3" json:"updated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SupUpdate) Reset()         { *m = SupUpdate{} }
+func (m *SupUpdate) String() string { return proto.CompactTextString(m) }
+func (*SupUpdate) ProtoMessage()    {}
+func (*SupUpdate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{15}
+}
+
+func (m *SupUpdate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SupUpdate.Unmarshal(m, b)
+}
+func (m *SupUpdate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SupUpdate.Marshal(b, m, deterministic)
+}
+func (m *SupUpdate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SupUpdate.Merge(m, src)
+}
+func (m *SupUpdate) XXX_Size() int {
+	return xxx_messageInfo_SupUpdate.Size(m)
+}
+func (m *SupUpdate) XXX_DiscardUnknown() {
+	xxx_messageInfo_SupUpdate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SupUpdate proto.InternalMessageInfo
+
+func (m *SupUpdate) GetSupId() string {
+	if m != nil {
+		return m.SupId
+	}
+	return ""
+}
+
+func (m *SupUpdate) GetUpdated() int64 {
+	if m != nil {
+		return m.Updated
+	}
+	return 0
+}
+
+type SupResponse struct {
+	Updates              []*SupUpdate `protobuf:"bytes,1,rep,name=updates,proto3" json:"updates,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *SupResponse) Reset()         { *m = SupResponse{} }
+func (m *SupResponse) String() string { return proto.CompactTextString(m) }
+func (*SupResponse) ProtoMessage()    {}
+func (*SupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{16}
+}
+
+func (m *SupResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SupResponse.Unmarshal(m, b)
+}
+func (m *SupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SupResponse.Marshal(b, m, deterministic)
+}
+func (m *SupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SupResponse.Merge(m, src)
+}
+func (m *SupResponse) XXX_Size() int {
+	return xxx_messageInfo_SupResponse.Size(m)
+}
+func (m *SupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SupResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SupResponse proto.InternalMessageInfo
+
+func (m *SupResponse) GetUpdates() []*SupUpdate {
+	if m != nil {
+		return m.Updates
+	}
+	return nil
+}
+
+// Subscription to SUP or WebSub hub.
+type Subscription struct {
+	// feed url for WebSub, sup url of the feed for SUP, eg:
+	// http://friendfeed.com/api/sup.json#4ceb94af
+	Topic string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	// WebSub hub url, empty for SUP
+	Hub string `protobuf:"bytes,2,opt,name=hub,proto3" json:"hub,omitempty"`
+	// WebSub callback url
+	Callback string `protobuf:"bytes,3,opt,name=callback,proto3" json:"callback,omitempty"`
+	Secret   string `protobuf:"bytes,4,opt,name=secret,proto3" json:"secret,omitempty"`
+	Verified bool   `protobuf:"varint,5,opt,name=verified,proto3" json:"verified,omitempty"`
+	// unix timestamp
+	LeaseExpires int64 `protobuf:"varint,6,opt,name=lease_expires,json=leaseExpires,proto3" json:"lease_expires,omitempty"`
+	// latest update seen, unix timestamp
+	Updated int64 `protobuf:"varint,7,opt,name=updated,proto3" json:"updated,omitempty"`
+	// sha1 of latest content distributed
+	Digest string `protobuf:"bytes,8,opt,name=digest,proto3" json:"digest,omitempty"`
+	// job enqueued once the topic updated
+	Job *FeedJob `protobuf:"bytes,9,opt,name=job,proto3" json:"job,omitempty"`
+	// mode of the latest request sent to hub, until verified by hub
+	PendingMode string `protobuf:"bytes,10,opt,name=pending_mode,json=pendingMode,proto3" json:"pending_mode,omitempty"`
+	// lease seconds asked in pending subscribe request
+	PendingLease         int64    `protobuf:"varint,11,opt,name=pending_lease,json=pendingLease,proto3" json:"pending_lease,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Subscription) Reset()         { *m = Subscription{} }
+func (m *Subscription) String() string { return proto.CompactTextString(m) }
+func (*Subscription) ProtoMessage()    {}
+func (*Subscription) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{17}
+}
+
+func (m *Subscription) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Subscription.Unmarshal(m, b)
+}
+func (m *Subscription) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Subscription.Marshal(b, m, deterministic)
+}
+func (m *Subscription) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Subscription.Merge(m, src)
+}
+func (m *Subscription) XXX_Size() int {
+	return xxx_messageInfo_Subscription.Size(m)
+}
+func (m *Subscription) XXX_DiscardUnknown() {
+	xxx_messageInfo_Subscription.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Subscription proto.InternalMessageInfo
+
+func (m *Subscription) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+func (m *Subscription) GetHub() string {
+	if m != nil {
+		return m.Hub
+	}
+	return ""
+}
+
+func (m *Subscription) GetCallback() string {
+	if m != nil {
+		return m.Callback
+	}
+	return ""
+}
+
+func (m *Subscription) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *Subscription) GetVerified() bool {
+	if m != nil {
+		return m.Verified
+	}
+	return false
+}
+
+func (m *Subscription) GetLeaseExpires() int64 {
+	if m != nil {
+		return m.LeaseExpires
+	}
+	return 0
+}
+
+func (m *Subscription) GetUpdated() int64 {
+	if m != nil {
+		return m.Updated
+	}
+	return 0
+}
+
+func (m *Subscription) GetDigest() string {
+	if m != nil {
+		return m.Digest
+	}
+	return ""
+}
+
+func (m *Subscription) GetJob() *FeedJob {
+	if m != nil {
+		return m.Job
+	}
+	return nil
+}
+
+func (m *Subscription) GetPendingMode() string {
+	if m != nil {
+		return m.PendingMode
+	}
+	return ""
+}
+
+func (m *Subscription) GetPendingLease() int64 {
+	if m != nil {
+		return m.PendingLease
+	}
+	return 0
+}
+
+type PushRequest struct {
+	Topic string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	// subscribe, unsubscribe or denied for intent verification, empty for
+	// content distribution
+	Mode         string `protobuf:"bytes,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	Challenge    string `protobuf:"bytes,3,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	LeaseSeconds int64  `protobuf:"varint,4,opt,name=lease_seconds,json=leaseSeconds,proto3" json:"lease_seconds,omitempty"`
+	Body         []byte `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
+	// X-Hub-Signature
+	Signature string `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+	// callback url requested by hub
+	Callback             string   `protobuf:"bytes,7,opt,name=callback,proto3" json:"callback,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PushRequest) Reset()         { *m = PushRequest{} }
+func (m *PushRequest) String() string { return proto.CompactTextString(m) }
+func (*PushRequest) ProtoMessage()    {}
+func (*PushRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{18}
+}
+
+func (m *PushRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PushRequest.Unmarshal(m, b)
+}
+func (m *PushRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PushRequest.Marshal(b, m, deterministic)
+}
+func (m *PushRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushRequest.Merge(m, src)
+}
+func (m *PushRequest) XXX_Size() int {
+	return xxx_messageInfo_PushRequest.Size(m)
+}
+func (m *PushRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushRequest proto.InternalMessageInfo
+
+func (m *PushRequest) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+func (m *PushRequest) GetMode() string {
+	if m != nil {
+		return m.Mode
+	}
+	return ""
+}
+
+func (m *PushRequest) GetChallenge() string {
+	if m != nil {
+		return m.Challenge
+	}
+	return ""
+}
+
+func (m *PushRequest) GetLeaseSeconds() int64 {
+	if m != nil {
+		return m.LeaseSeconds
+	}
+	return 0
+}
+
+func (m *PushRequest) GetBody() []byte {
+	if m != nil {
+		return m.Body
+	}
+	return nil
+}
+
+func (m *PushRequest) GetSignature() string {
+	if m != nil {
+		return m.Signature
+	}
+	return ""
+}
+
+func (m *PushRequest) GetCallback() string {
+	if m != nil {
+		return m.Callback
+	}
+	return ""
+}
+
+type PushResponse struct {
+	// echo for intent verification
+	Challenge string `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	// job enqueued, nil if content not changed
+	Job                  *FeedJob `protobuf:"bytes,2,opt,name=job,proto3" json:"job,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PushResponse) Reset()         { *m = PushResponse{} }
+func (m *PushResponse) String() string { return proto.CompactTextString(m) }
+func (*PushResponse) ProtoMessage()    {}
+func (*PushResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{19}
+}
+
+func (m *PushResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PushResponse.Unmarshal(m, b)
+}
+func (m *PushResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PushResponse.Marshal(b, m, deterministic)
+}
+func (m *PushResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushResponse.Merge(m, src)
+}
+func (m *PushResponse) XXX_Size() int {
+	return xxx_messageInfo_PushResponse.Size(m)
+}
+func (m *PushResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushResponse proto.InternalMessageInfo
+
+func (m *PushResponse) GetChallenge() string {
+	if m != nil {
+		return m.Challenge
+	}
+	return ""
+}
+
+func (m *PushResponse) GetJob() *FeedJob {
+	if m != nil {
+		return m.Job
+	}
+	return nil
+}
+
+// Subscription to our own feed from downstream readers.
+type HubSubscription struct {
+	// subscribe or unsubscribe
+	Mode   string `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	FeedId string `protobuf:"bytes,2,opt,name=feed_id,json=feedId,proto3" json:"feed_id,omitempty"`
+	// atom or rss url of the feed
+	Topic        string `protobuf:"bytes,3,opt,name=topic,proto3" json:"topic,omitempty"`
+	Callback     string `protobuf:"bytes,4,opt,name=callback,proto3" json:"callback,omitempty"`
+	Secret       string `protobuf:"bytes,5,opt,name=secret,proto3" json:"secret,omitempty"`
+	LeaseSeconds int64  `protobuf:"varint,6,opt,name=lease_seconds,json=leaseSeconds,proto3" json:"lease_seconds,omitempty"`
+	// unix timestamp
+	Expires int64 `protobuf:"varint,7,opt,name=expires,proto3" json:"expires,omitempty"`
+	// hub url, advertised in content distribution
+	Hub                  string   `protobuf:"bytes,8,opt,name=hub,proto3" json:"hub,omitempty"`
+	Created              int64    `protobuf:"varint,9,opt,name=created,proto3" json:"created,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HubSubscription) Reset()         { *m = HubSubscription{} }
+func (m *HubSubscription) String() string { return proto.CompactTextString(m) }
+func (*HubSubscription) ProtoMessage()    {}
+func (*HubSubscription) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{20}
+}
+
+func (m *HubSubscription) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HubSubscription.Unmarshal(m, b)
+}
+func (m *HubSubscription) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HubSubscription.Marshal(b, m, deterministic)
+}
+func (m *HubSubscription) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HubSubscription.Merge(m, src)
+}
+func (m *HubSubscription) XXX_Size() int {
+	return xxx_messageInfo_HubSubscription.Size(m)
+}
+func (m *HubSubscription) XXX_DiscardUnknown() {
+	xxx_messageInfo_HubSubscription.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HubSubscription proto.InternalMessageInfo
+
+func (m *HubSubscription) GetMode() string {
+	if m != nil {
+		return m.Mode
+	}
+	return ""
+}
+
+func (m *HubSubscription) GetFeedId() string {
+	if m != nil {
+		return m.FeedId
+	}
+	return ""
+}
+
+func (m *HubSubscription) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+func (m *HubSubscription) GetCallback() string {
+	if m != nil {
+		return m.Callback
+	}
+	return ""
+}
+
+func (m *HubSubscription) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *HubSubscription) GetLeaseSeconds() int64 {
+	if m != nil {
+		return m.LeaseSeconds
+	}
+	return 0
+}
+
+func (m *HubSubscription) GetExpires() int64 {
+	if m != nil {
+		return m.Expires
+	}
+	return 0
+}
+
+func (m *HubSubscription) GetHub() string {
+	if m != nil {
+		return m.Hub
+	}
+	return ""
+}
+
+func (m *HubSubscription) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+// Pending content distribution to a subscriber, retried until delivered.
+type HubDelivery struct {
+	// key of HubSubscription
+	Key      string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Attempts int32  `protobuf:"varint,2,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// unix timestamp
+	NextAttempt          int64    `protobuf:"varint,3,opt,name=next_attempt,json=nextAttempt,proto3" json:"next_attempt,omitempty"`
+	Created              int64    `protobuf:"varint,4,opt,name=created,proto3" json:"created,omitempty"`
+	Error                string   `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HubDelivery) Reset()         { *m = HubDelivery{} }
+func (m *HubDelivery) String() string { return proto.CompactTextString(m) }
+func (*HubDelivery) ProtoMessage()    {}
+func (*HubDelivery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{21}
+}
+
+func (m *HubDelivery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HubDelivery.Unmarshal(m, b)
+}
+func (m *HubDelivery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HubDelivery.Marshal(b, m, deterministic)
+}
+func (m *HubDelivery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HubDelivery.Merge(m, src)
+}
+func (m *HubDelivery) XXX_Size() int {
+	return xxx_messageInfo_HubDelivery.Size(m)
+}
+func (m *HubDelivery) XXX_DiscardUnknown() {
+	xxx_messageInfo_HubDelivery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HubDelivery proto.InternalMessageInfo
+
+func (m *HubDelivery) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *HubDelivery) GetAttempts() int32 {
+	if m != nil {
+		return m.Attempts
+	}
+	return 0
+}
+
+func (m *HubDelivery) GetNextAttempt() int64 {
+	if m != nil {
+		return m.NextAttempt
+	}
+	return 0
+}
+
+func (m *HubDelivery) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+func (m *HubDelivery) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type ActorRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ActorRequest) Reset()         { *m = ActorRequest{} }
+func (m *ActorRequest) String() string { return proto.CompactTextString(m) }
+func (*ActorRequest) ProtoMessage()    {}
+func (*ActorRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{22}
+}
+
+func (m *ActorRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ActorRequest.Unmarshal(m, b)
+}
+func (m *ActorRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ActorRequest.Marshal(b, m, deterministic)
+}
+func (m *ActorRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActorRequest.Merge(m, src)
+}
+func (m *ActorRequest) XXX_Size() int {
+	return xxx_messageInfo_ActorRequest.Size(m)
+}
+func (m *ActorRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActorRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ActorRequest proto.InternalMessageInfo
+
+func (m *ActorRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+// ActivityPub actor of our feed.
+type Actor struct {
+	Profile *Profile `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	// PEM encoded public key
+	PublicKey            string   `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Followers            int32    `protobuf:"varint,3,opt,name=followers,proto3" json:"followers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Actor) Reset()         { *m = Actor{} }
+func (m *Actor) String() string { return proto.CompactTextString(m) }
+func (*Actor) ProtoMessage()    {}
+func (*Actor) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{23}
+}
+
+func (m *Actor) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Actor.Unmarshal(m, b)
+}
+func (m *Actor) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Actor.Marshal(b, m, deterministic)
+}
+func (m *Actor) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Actor.Merge(m, src)
+}
+func (m *Actor) XXX_Size() int {
+	return xxx_messageInfo_Actor.Size(m)
+}
+func (m *Actor) XXX_DiscardUnknown() {
+	xxx_messageInfo_Actor.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Actor proto.InternalMessageInfo
+
+func (m *Actor) GetProfile() *Profile {
+	if m != nil {
+		return m.Profile
+	}
+	return nil
+}
+
+func (m *Actor) GetPublicKey() string {
+	if m != nil {
+		return m.PublicKey
+	}
+	return ""
+}
+
+func (m *Actor) GetFollowers() int32 {
+	if m != nil {
+		return m.Followers
+	}
+	return 0
+}
+
+// Key pair of actor, PEM encoded, private key never leaves the server.
+type ActorKey struct {
+	FeedId               string   `protobuf:"bytes,1,opt,name=feed_id,json=feedId,proto3" json:"feed_id,omitempty"`
+	PrivateKey           string   `protobuf:"bytes,2,opt,name=private_key,json=privateKey,proto3" json:"private_key,omitempty"`
+	PublicKey            string   `protobuf:"bytes,3,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Created              int64    `protobuf:"varint,4,opt,name=created,proto3" json:"created,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ActorKey) Reset()         { *m = ActorKey{} }
+func (m *ActorKey) String() string { return proto.CompactTextString(m) }
+func (*ActorKey) ProtoMessage()    {}
+func (*ActorKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{24}
+}
+
+func (m *ActorKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ActorKey.Unmarshal(m, b)
+}
+func (m *ActorKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ActorKey.Marshal(b, m, deterministic)
+}
+func (m *ActorKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActorKey.Merge(m, src)
+}
+func (m *ActorKey) XXX_Size() int {
+	return xxx_messageInfo_ActorKey.Size(m)
+}
+func (m *ActorKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActorKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ActorKey proto.InternalMessageInfo
+
+func (m *ActorKey) GetFeedId() string {
+	if m != nil {
+		return m.FeedId
+	}
+	return ""
+}
+
+func (m *ActorKey) GetPrivateKey() string {
+	if m != nil {
+		return m.PrivateKey
+	}
+	return ""
+}
+
+func (m *ActorKey) GetPublicKey() string {
+	if m != nil {
+		return m.PublicKey
+	}
+	return ""
+}
+
+func (m *ActorKey) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+// Inbox request as received by httpd, signature verified by server.
+type InboxRequest struct {
+	FeedId string `protobuf:"bytes,1,opt,name=feed_id,json=feedId,proto3" json:"feed_id,omitempty"`
+	// actor id of the feed, eg: https://example.com/ap/users/foo
+	Actor  string `protobuf:"bytes,2,opt,name=actor,proto3" json:"actor,omitempty"`
+	Method string `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	// request uri
+	Path                 string            `protobuf:"bytes,4,opt,name=path,proto3" json:"path,omitempty"`
+	Host                 string            `protobuf:"bytes,5,opt,name=host,proto3" json:"host,omitempty"`
+	Headers              map[string]string `protobuf:"bytes,6,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Body                 []byte            `protobuf:"bytes,7,opt,name=body,proto3" json:"body,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *InboxRequest) Reset()         { *m = InboxRequest{} }
+func (m *InboxRequest) String() string { return proto.CompactTextString(m) }
+func (*InboxRequest) ProtoMessage()    {}
+func (*InboxRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{25}
+}
+
+func (m *InboxRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InboxRequest.Unmarshal(m, b)
+}
+func (m *InboxRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InboxRequest.Marshal(b, m, deterministic)
+}
+func (m *InboxRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InboxRequest.Merge(m, src)
+}
+func (m *InboxRequest) XXX_Size() int {
+	return xxx_messageInfo_InboxRequest.Size(m)
+}
+func (m *InboxRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InboxRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InboxRequest proto.InternalMessageInfo
+
+func (m *InboxRequest) GetFeedId() string {
+	if m != nil {
+		return m.FeedId
+	}
+	return ""
+}
+
+func (m *InboxRequest) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *InboxRequest) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *InboxRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *InboxRequest) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *InboxRequest) GetHeaders() map[string]string {
+	if m != nil {
+		return m.Headers
+	}
+	return nil
+}
+
+func (m *InboxRequest) GetBody() []byte {
+	if m != nil {
+		return m.Body
+	}
+	return nil
+}
+
+type InboxResponse struct {
+	// type of activity accepted
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InboxResponse) Reset()         { *m = InboxResponse{} }
+func (m *InboxResponse) String() string { return proto.CompactTextString(m) }
+func (*InboxResponse) ProtoMessage()    {}
+func (*InboxResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{26}
+}
+
+func (m *InboxResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InboxResponse.Unmarshal(m, b)
+}
+func (m *InboxResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InboxResponse.Marshal(b, m, deterministic)
+}
+func (m *InboxResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InboxResponse.Merge(m, src)
+}
+func (m *InboxResponse) XXX_Size() int {
+	return xxx_messageInfo_InboxResponse.Size(m)
+}
+func (m *InboxResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_InboxResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InboxResponse proto.InternalMessageInfo
+
+func (m *InboxResponse) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+// Remote follower of our feed.
+type Follower struct {
+	FeedId string `protobuf:"bytes,1,opt,name=feed_id,json=feedId,proto3" json:"feed_id,omitempty"`
+	// actor id of follower
+	Actor       string `protobuf:"bytes,2,opt,name=actor,proto3" json:"actor,omitempty"`
+	Inbox       string `protobuf:"bytes,3,opt,name=inbox,proto3" json:"inbox,omitempty"`
+	SharedInbox string `protobuf:"bytes,4,opt,name=shared_inbox,json=sharedInbox,proto3" json:"shared_inbox,omitempty"`
+	// actor id of the feed followed
+	Object               string   `protobuf:"bytes,5,opt,name=object,proto3" json:"object,omitempty"`
+	Created              int64    `protobuf:"varint,6,opt,name=created,proto3" json:"created,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Follower) Reset()         { *m = Follower{} }
+func (m *Follower) String() string { return proto.CompactTextString(m) }
+func (*Follower) ProtoMessage()    {}
+func (*Follower) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{27}
+}
+
+func (m *Follower) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Follower.Unmarshal(m, b)
+}
+func (m *Follower) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Follower.Marshal(b, m, deterministic)
+}
+func (m *Follower) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Follower.Merge(m, src)
+}
+func (m *Follower) XXX_Size() int {
+	return xxx_messageInfo_Follower.Size(m)
+}
+func (m *Follower) XXX_DiscardUnknown() {
+	xxx_messageInfo_Follower.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Follower proto.InternalMessageInfo
+
+func (m *Follower) GetFeedId() string {
+	if m != nil {
+		return m.FeedId
+	}
+	return ""
+}
+
+func (m *Follower) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *Follower) GetInbox() string {
+	if m != nil {
+		return m.Inbox
+	}
+	return ""
+}
+
+func (m *Follower) GetSharedInbox() string {
+	if m != nil {
+		return m.SharedInbox
+	}
+	return ""
+}
+
+func (m *Follower) GetObject() string {
+	if m != nil {
+		return m.Object
+	}
+	return ""
+}
+
+func (m *Follower) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+// Pending activity delivery to an inbox, retried until delivered.
+type ActivityDelivery struct {
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// feed signing the request
+	FeedId   string `protobuf:"bytes,2,opt,name=feed_id,json=feedId,proto3" json:"feed_id,omitempty"`
+	KeyId    string `protobuf:"bytes,3,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Inbox    string `protobuf:"bytes,4,opt,name=inbox,proto3" json:"inbox,omitempty"`
+	Activity []byte `protobuf:"bytes,5,opt,name=activity,proto3" json:"activity,omitempty"`
+	Attempts int32  `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// unix timestamp
+	NextAttempt          int64    `protobuf:"varint,7,opt,name=next_attempt,json=nextAttempt,proto3" json:"next_attempt,omitempty"`
+	Created              int64    `protobuf:"varint,8,opt,name=created,proto3" json:"created,omitempty"`
+	Error                string   `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ActivityDelivery) Reset()         { *m = ActivityDelivery{} }
+func (m *ActivityDelivery) String() string { return proto.CompactTextString(m) }
+func (*ActivityDelivery) ProtoMessage()    {}
+func (*ActivityDelivery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{28}
+}
+
+func (m *ActivityDelivery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ActivityDelivery.Unmarshal(m, b)
+}
+func (m *ActivityDelivery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ActivityDelivery.Marshal(b, m, deterministic)
+}
+func (m *ActivityDelivery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActivityDelivery.Merge(m, src)
+}
+func (m *ActivityDelivery) XXX_Size() int {
+	return xxx_messageInfo_ActivityDelivery.Size(m)
+}
+func (m *ActivityDelivery) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActivityDelivery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ActivityDelivery proto.InternalMessageInfo
+
+func (m *ActivityDelivery) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *ActivityDelivery) GetFeedId() string {
+	if m != nil {
+		return m.FeedId
+	}
+	return ""
+}
+
+func (m *ActivityDelivery) GetKeyId() string {
+	if m != nil {
+		return m.KeyId
+	}
+	return ""
+}
+
+func (m *ActivityDelivery) GetInbox() string {
+	if m != nil {
+		return m.Inbox
+	}
+	return ""
+}
+
+func (m *ActivityDelivery) GetActivity() []byte {
+	if m != nil {
+		return m.Activity
+	}
+	return nil
+}
+
+func (m *ActivityDelivery) GetAttempts() int32 {
+	if m != nil {
+		return m.Attempts
+	}
+	return 0
+}
+
+func (m *ActivityDelivery) GetNextAttempt() int64 {
+	if m != nil {
+		return m.NextAttempt
+	}
+	return 0
+}
+
+func (m *ActivityDelivery) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+func (m *ActivityDelivery) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type ServiceRequest struct {
+	User    string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Service string `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
+	// feed url of rss/atom service, all services of the type deleted if empty
+	Url  string `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	Name string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	// websub callback of our site, hub advertised by feed subscribed if set
+	PushCallback         string   `protobuf:"bytes,5,opt,name=push_callback,json=pushCallback,proto3" json:"push_callback,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ServiceRequest) Reset()         { *m = ServiceRequest{} }
+func (m *ServiceRequest) String() string { return proto.CompactTextString(m) }
+func (*ServiceRequest) ProtoMessage()    {}
+func (*ServiceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{29}
+}
+
+func (m *ServiceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServiceRequest.Unmarshal(m, b)
+}
+func (m *ServiceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServiceRequest.Marshal(b, m, deterministic)
+}
+func (m *ServiceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServiceRequest.Merge(m, src)
+}
+func (m *ServiceRequest) XXX_Size() int {
+	return xxx_messageInfo_ServiceRequest.Size(m)
+}
+func (m *ServiceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServiceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServiceRequest proto.InternalMessageInfo
+
+func (m *ServiceRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *ServiceRequest) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *ServiceRequest) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *ServiceRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
 }
 
-func (m *EntryRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_EntryRequest.Unmarshal(m, b)
+func (m *ServiceRequest) GetPushCallback() string {
+	if m != nil {
+		return m.PushCallback
+	}
+	return ""
 }
-func (m *EntryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_EntryRequest.Marshal(b, m, deterministic)
+
+// Media content of hash mirrored, refs counted from entries and pictures.
+type MediaBlob struct {
+	// hex sha256 of content
+	Hash     string `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	MimeType string `protobuf:"bytes,2,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	Refs     int32  `protobuf:"varint,3,opt,name=refs,proto3" json:"refs,omitempty"`
+	// unix timestamp refs dropped to zero, blob collected after grace period
+	Orphaned int64 `protobuf:"varint,4,opt,name=orphaned,proto3" json:"orphaned,omitempty"`
+	Created  int64 `protobuf:"varint,5,opt,name=created,proto3" json:"created,omitempty"`
+	// size of image displayed upright, thumbnails made once
+	Width                int32         `protobuf:"varint,6,opt,name=width,proto3" json:"width,omitempty"`
+	Height               int32         `protobuf:"varint,7,opt,name=height,proto3" json:"height,omitempty"`
+	Thumbs               []*MediaThumb `protobuf:"bytes,8,rep,name=thumbs,proto3" json:"thumbs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
-func (m *EntryRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_EntryRequest.Merge(m, src)
+
+func (m *MediaBlob) Reset()         { *m = MediaBlob{} }
+func (m *MediaBlob) String() string { return proto.CompactTextString(m) }
+func (*MediaBlob) ProtoMessage()    {}
+func (*MediaBlob) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{30}
 }
-func (m *EntryRequest) XXX_Size() int {
-	return xxx_messageInfo_EntryRequest.Size(m)
+
+func (m *MediaBlob) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MediaBlob.Unmarshal(m, b)
 }
-func (m *EntryRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_EntryRequest.DiscardUnknown(m)
+func (m *MediaBlob) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MediaBlob.Marshal(b, m, deterministic)
+}
+func (m *MediaBlob) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MediaBlob.Merge(m, src)
+}
+func (m *MediaBlob) XXX_Size() int {
+	return xxx_messageInfo_MediaBlob.Size(m)
+}
+func (m *MediaBlob) XXX_DiscardUnknown() {
+	xxx_messageInfo_MediaBlob.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_EntryRequest proto.InternalMessageInfo
+var xxx_messageInfo_MediaBlob proto.InternalMessageInfo
 
-func (m *EntryRequest) GetUuid() string {
+func (m *MediaBlob) GetHash() string {
 	if m != nil {
-		return m.Uuid
+		return m.Hash
 	}
 	return ""
 }
 
-type ProfileRequest struct {
-	Uuid                 string   `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *MediaBlob) GetMimeType() string {
+	if m != nil {
+		return m.MimeType
+	}
+	return ""
 }
 
-func (m *ProfileRequest) Reset()         { *m = ProfileRequest{} }
-func (m *ProfileRequest) String() string { return proto.CompactTextString(m) }
-func (*ProfileRequest) ProtoMessage()    {}
-func (*ProfileRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{7}
+func (m *MediaBlob) GetRefs() int32 {
+	if m != nil {
+		return m.Refs
+	}
+	return 0
 }
 
-func (m *ProfileRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ProfileRequest.Unmarshal(m, b)
-}
-func (m *ProfileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ProfileRequest.Marshal(b, m, deterministic)
-}
-func (m *ProfileRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ProfileRequest.Merge(m, src)
+func (m *MediaBlob) GetOrphaned() int64 {
+	if m != nil {
+		return m.Orphaned
+	}
+	return 0
 }
-func (m *ProfileRequest) XXX_Size() int {
-	return xxx_messageInfo_ProfileRequest.Size(m)
+
+func (m *MediaBlob) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
 }
-func (m *ProfileRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ProfileRequest.DiscardUnknown(m)
+
+func (m *MediaBlob) GetWidth() int32 {
+	if m != nil {
+		return m.Width
+	}
+	return 0
 }
 
-var xxx_messageInfo_ProfileRequest proto.InternalMessageInfo
+func (m *MediaBlob) GetHeight() int32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
 
-func (m *ProfileRequest) GetUuid() string {
+func (m *MediaBlob) GetThumbs() []*MediaThumb {
 	if m != nil {
-		return m.Uuid
+		return m.Thumbs
 	}
-	return ""
+	return nil
 }
 
-type LikeRequest struct {
-	Entry                string   `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
-	User                 string   `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
-	Like                 bool     `protobuf:"varint,3,opt,name=like,proto3" json:"like,omitempty"`
+// Thumbnail of media blob, by size name, eg: small, large.
+type MediaThumb struct {
+	Size   string `protobuf:"bytes,1,opt,name=size,proto3" json:"size,omitempty"`
+	Url    string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Width  int32  `protobuf:"varint,3,opt,name=width,proto3" json:"width,omitempty"`
+	Height int32  `protobuf:"varint,4,opt,name=height,proto3" json:"height,omitempty"`
+	// hash of thumbnail blob, empty if kept by mandible
+	Hash                 string   `protobuf:"bytes,5,opt,name=hash,proto3" json:"hash,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *LikeRequest) Reset()         { *m = LikeRequest{} }
-func (m *LikeRequest) String() string { return proto.CompactTextString(m) }
-func (*LikeRequest) ProtoMessage()    {}
-func (*LikeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{8}
+func (m *MediaThumb) Reset()         { *m = MediaThumb{} }
+func (m *MediaThumb) String() string { return proto.CompactTextString(m) }
+func (*MediaThumb) ProtoMessage()    {}
+func (*MediaThumb) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{31}
 }
 
-func (m *LikeRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_LikeRequest.Unmarshal(m, b)
+func (m *MediaThumb) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MediaThumb.Unmarshal(m, b)
 }
-func (m *LikeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_LikeRequest.Marshal(b, m, deterministic)
+func (m *MediaThumb) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MediaThumb.Marshal(b, m, deterministic)
 }
-func (m *LikeRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_LikeRequest.Merge(m, src)
+func (m *MediaThumb) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MediaThumb.Merge(m, src)
 }
-func (m *LikeRequest) XXX_Size() int {
-	return xxx_messageInfo_LikeRequest.Size(m)
+func (m *MediaThumb) XXX_Size() int {
+	return xxx_messageInfo_MediaThumb.Size(m)
 }
-func (m *LikeRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_LikeRequest.DiscardUnknown(m)
+func (m *MediaThumb) XXX_DiscardUnknown() {
+	xxx_messageInfo_MediaThumb.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_LikeRequest proto.InternalMessageInfo
+var xxx_messageInfo_MediaThumb proto.InternalMessageInfo
 
-func (m *LikeRequest) GetEntry() string {
+func (m *MediaThumb) GetSize() string {
 	if m != nil {
-		return m.Entry
+		return m.Size
 	}
 	return ""
 }
 
-func (m *LikeRequest) GetUser() string {
+func (m *MediaThumb) GetUrl() string {
 	if m != nil {
-		return m.User
+		return m.Url
 	}
 	return ""
 }
 
-func (m *LikeRequest) GetLike() bool {
+func (m *MediaThumb) GetWidth() int32 {
 	if m != nil {
-		return m.Like
+		return m.Width
 	}
-	return false
+	return 0
 }
 
-type CommentRequest struct {
-	Entry                string   `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
-	Comment              *Comment `protobuf:"bytes,2,opt,name=comment,proto3" json:"comment,omitempty"`
+func (m *MediaThumb) GetHeight() int32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *MediaThumb) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+// Media blobs referenced by owner, entry id or profile picture.
+type MediaRefs struct {
+	Owner                string   `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	Hashes               []string `protobuf:"bytes,2,rep,name=hashes,proto3" json:"hashes,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CommentRequest) Reset()         { *m = CommentRequest{} }
-func (m *CommentRequest) String() string { return proto.CompactTextString(m) }
-func (*CommentRequest) ProtoMessage()    {}
-func (*CommentRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{9}
+func (m *MediaRefs) Reset()         { *m = MediaRefs{} }
+func (m *MediaRefs) String() string { return proto.CompactTextString(m) }
+func (*MediaRefs) ProtoMessage()    {}
+func (*MediaRefs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{32}
 }
 
-func (m *CommentRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CommentRequest.Unmarshal(m, b)
+func (m *MediaRefs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MediaRefs.Unmarshal(m, b)
 }
-func (m *CommentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CommentRequest.Marshal(b, m, deterministic)
+func (m *MediaRefs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MediaRefs.Marshal(b, m, deterministic)
 }
-func (m *CommentRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CommentRequest.Merge(m, src)
+func (m *MediaRefs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MediaRefs.Merge(m, src)
 }
-func (m *CommentRequest) XXX_Size() int {
-	return xxx_messageInfo_CommentRequest.Size(m)
+func (m *MediaRefs) XXX_Size() int {
+	return xxx_messageInfo_MediaRefs.Size(m)
 }
-func (m *CommentRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_CommentRequest.DiscardUnknown(m)
+func (m *MediaRefs) XXX_DiscardUnknown() {
+	xxx_messageInfo_MediaRefs.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CommentRequest proto.InternalMessageInfo
+var xxx_messageInfo_MediaRefs proto.InternalMessageInfo
 
-func (m *CommentRequest) GetEntry() string {
+func (m *MediaRefs) GetOwner() string {
 	if m != nil {
-		return m.Entry
+		return m.Owner
 	}
 	return ""
 }
 
-func (m *CommentRequest) GetComment() *Comment {
+func (m *MediaRefs) GetHashes() []string {
 	if m != nil {
-		return m.Comment
+		return m.Hashes
 	}
 	return nil
 }
 
-type CommentDeleteRequest struct {
-	Entry                string   `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
-	Comment              string   `protobuf:"bytes,2,opt,name=comment,proto3" json:"comment,omitempty"`
-	User                 string   `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+// Pending mirroring of media of entry, retried until mirrored.
+type MediaMirror struct {
+	EntryId  string `protobuf:"bytes,1,opt,name=entry_id,json=entryId,proto3" json:"entry_id,omitempty"`
+	Attempts int32  `protobuf:"varint,2,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// unix timestamp
+	NextAttempt          int64    `protobuf:"varint,3,opt,name=next_attempt,json=nextAttempt,proto3" json:"next_attempt,omitempty"`
+	Created              int64    `protobuf:"varint,4,opt,name=created,proto3" json:"created,omitempty"`
+	Error                string   `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CommentDeleteRequest) Reset()         { *m = CommentDeleteRequest{} }
-func (m *CommentDeleteRequest) String() string { return proto.CompactTextString(m) }
-func (*CommentDeleteRequest) ProtoMessage()    {}
-func (*CommentDeleteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{10}
+func (m *MediaMirror) Reset()         { *m = MediaMirror{} }
+func (m *MediaMirror) String() string { return proto.CompactTextString(m) }
+func (*MediaMirror) ProtoMessage()    {}
+func (*MediaMirror) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{33}
 }
 
-func (m *CommentDeleteRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CommentDeleteRequest.Unmarshal(m, b)
+func (m *MediaMirror) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MediaMirror.Unmarshal(m, b)
 }
-func (m *CommentDeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CommentDeleteRequest.Marshal(b, m, deterministic)
+func (m *MediaMirror) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MediaMirror.Marshal(b, m, deterministic)
 }
-func (m *CommentDeleteRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CommentDeleteRequest.Merge(m, src)
+func (m *MediaMirror) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MediaMirror.Merge(m, src)
 }
-func (m *CommentDeleteRequest) XXX_Size() int {
-	return xxx_messageInfo_CommentDeleteRequest.Size(m)
+func (m *MediaMirror) XXX_Size() int {
+	return xxx_messageInfo_MediaMirror.Size(m)
 }
-func (m *CommentDeleteRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_CommentDeleteRequest.DiscardUnknown(m)
+func (m *MediaMirror) XXX_DiscardUnknown() {
+	xxx_messageInfo_MediaMirror.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CommentDeleteRequest proto.InternalMessageInfo
+var xxx_messageInfo_MediaMirror proto.InternalMessageInfo
 
-func (m *CommentDeleteRequest) GetEntry() string {
+func (m *MediaMirror) GetEntryId() string {
 	if m != nil {
-		return m.Entry
+		return m.EntryId
 	}
 	return ""
 }
 
-func (m *CommentDeleteRequest) GetComment() string {
+func (m *MediaMirror) GetAttempts() int32 {
 	if m != nil {
-		return m.Comment
+		return m.Attempts
 	}
-	return ""
+	return 0
 }
 
-func (m *CommentDeleteRequest) GetUser() string {
+func (m *MediaMirror) GetNextAttempt() int64 {
 	if m != nil {
-		return m.User
+		return m.NextAttempt
 	}
-	return ""
-}
-
-type ServiceRequest struct {
-	User                 string   `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
-	Service              string   `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *ServiceRequest) Reset()         { *m = ServiceRequest{} }
-func (m *ServiceRequest) String() string { return proto.CompactTextString(m) }
-func (*ServiceRequest) ProtoMessage()    {}
-func (*ServiceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{11}
-}
-
-func (m *ServiceRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ServiceRequest.Unmarshal(m, b)
-}
-func (m *ServiceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ServiceRequest.Marshal(b, m, deterministic)
-}
-func (m *ServiceRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ServiceRequest.Merge(m, src)
-}
-func (m *ServiceRequest) XXX_Size() int {
-	return xxx_messageInfo_ServiceRequest.Size(m)
-}
-func (m *ServiceRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ServiceRequest.DiscardUnknown(m)
+	return 0
 }
 
-var xxx_messageInfo_ServiceRequest proto.InternalMessageInfo
-
-func (m *ServiceRequest) GetUser() string {
+func (m *MediaMirror) GetCreated() int64 {
 	if m != nil {
-		return m.User
+		return m.Created
 	}
-	return ""
+	return 0
 }
 
-func (m *ServiceRequest) GetService() string {
+func (m *MediaMirror) GetError() string {
 	if m != nil {
-		return m.Service
+		return m.Error
 	}
 	return ""
 }
@@ -786,74 +2387,174 @@ func init() {
 	proto.RegisterType((*LikeRequest)(nil), "proto.LikeRequest")
 	proto.RegisterType((*CommentRequest)(nil), "proto.CommentRequest")
 	proto.RegisterType((*CommentDeleteRequest)(nil), "proto.CommentDeleteRequest")
+	proto.RegisterType((*EntryEditRequest)(nil), "proto.EntryEditRequest")
+	proto.RegisterType((*EntryDeleteRequest)(nil), "proto.EntryDeleteRequest")
+	proto.RegisterType((*HideRequest)(nil), "proto.HideRequest")
+	proto.RegisterType((*SupRequest)(nil), "proto.SupRequest")
+	proto.RegisterType((*SupUpdate)(nil), "proto.SupUpdate")
+	proto.RegisterType((*SupResponse)(nil), "proto.SupResponse")
+	proto.RegisterType((*Subscription)(nil), "proto.Subscription")
+	proto.RegisterType((*PushRequest)(nil), "proto.PushRequest")
+	proto.RegisterType((*PushResponse)(nil), "proto.PushResponse")
+	proto.RegisterType((*HubSubscription)(nil), "proto.HubSubscription")
+	proto.RegisterType((*HubDelivery)(nil), "proto.HubDelivery")
+	proto.RegisterType((*ActorRequest)(nil), "proto.ActorRequest")
+	proto.RegisterType((*Actor)(nil), "proto.Actor")
+	proto.RegisterType((*ActorKey)(nil), "proto.ActorKey")
+	proto.RegisterType((*InboxRequest)(nil), "proto.InboxRequest")
+	proto.RegisterMapType((map[string]string)(nil), "proto.InboxRequest.HeadersEntry")
+	proto.RegisterType((*InboxResponse)(nil), "proto.InboxResponse")
+	proto.RegisterType((*Follower)(nil), "proto.Follower")
+	proto.RegisterType((*ActivityDelivery)(nil), "proto.ActivityDelivery")
 	proto.RegisterType((*ServiceRequest)(nil), "proto.ServiceRequest")
+	proto.RegisterType((*MediaBlob)(nil), "proto.MediaBlob")
+	proto.RegisterType((*MediaThumb)(nil), "proto.MediaThumb")
+	proto.RegisterType((*MediaRefs)(nil), "proto.MediaRefs")
+	proto.RegisterType((*MediaMirror)(nil), "proto.MediaMirror")
 }
 
 func init() { proto.RegisterFile("api.proto", fileDescriptor_00212fb1f9d3bf1c) }
 
 var fileDescriptor_00212fb1f9d3bf1c = []byte{
-	// 963 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0x6d, 0x6f, 0xe3, 0x44,
-	0x10, 0x8e, 0xeb, 0xe6, 0xc5, 0x93, 0x34, 0xad, 0x96, 0x5e, 0x65, 0x72, 0x42, 0x17, 0x56, 0x7c,
-	0x08, 0x12, 0x14, 0xae, 0x07, 0xe2, 0x45, 0x08, 0xa9, 0x94, 0xf6, 0x54, 0x0e, 0x89, 0xca, 0xe5,
-	0x84, 0x04, 0x1f, 0x22, 0x37, 0x9e, 0x36, 0x4b, 0x63, 0x3b, 0xb7, 0x5e, 0xdf, 0xb5, 0xf7, 0x57,
-	0xf8, 0xc6, 0x07, 0x7e, 0x1d, 0x3f, 0x02, 0xcd, 0xbe, 0x24, 0x8e, 0xdb, 0x14, 0xf8, 0x94, 0x9d,
-	0x67, 0xe6, 0x99, 0xcc, 0xce, 0x33, 0xb3, 0x86, 0x20, 0x9e, 0x8b, 0xfd, 0xb9, 0xcc, 0x55, 0xce,
-	0x9a, 0xfa, 0x67, 0x00, 0x97, 0x88, 0x89, 0x81, 0xf8, 0x6f, 0xd0, 0xfa, 0x25, 0x97, 0xd7, 0x28,
-	0x59, 0x1f, 0x36, 0x4e, 0x93, 0xd0, 0x1b, 0x7a, 0xa3, 0x20, 0xda, 0x38, 0x4d, 0xd8, 0x13, 0xd8,
-	0xa4, 0xb8, 0x70, 0x63, 0xe8, 0x8d, 0xba, 0x07, 0x5d, 0x13, 0xbf, 0x7f, 0x82, 0x98, 0x44, 0xda,
-	0xc1, 0x86, 0xe0, 0xff, 0x9e, 0x5f, 0x84, 0xbe, 0xf6, 0xf7, 0x2b, 0xfe, 0x1f, 0xf2, 0x8b, 0x88,
-	0x5c, 0xfc, 0x2f, 0x1f, 0xda, 0x16, 0x60, 0x3b, 0xe0, 0x5f, 0xe3, 0xad, 0xcd, 0x4f, 0x47, 0xfa,
-	0x43, 0x61, 0xd2, 0x07, 0xd1, 0x86, 0x48, 0xd8, 0x7b, 0x00, 0x12, 0xd3, 0x5c, 0xe1, 0x98, 0x02,
-	0x7d, 0x8d, 0x07, 0x06, 0x79, 0x81, 0xb7, 0xec, 0x31, 0x04, 0x2a, 0x96, 0x57, 0xa8, 0xc6, 0x22,
-	0x09, 0x37, 0xb5, 0xb7, 0x63, 0x80, 0xd3, 0x84, 0xed, 0x42, 0xb3, 0x50, 0xb1, 0x54, 0x61, 0x73,
-	0xe8, 0x8d, 0x9a, 0x91, 0x31, 0x88, 0x32, 0x8f, 0xaf, 0x70, 0x5c, 0x88, 0xb7, 0x18, 0xb6, 0xb4,
-	0xa7, 0x43, 0xc0, 0xb9, 0x78, 0x8b, 0x6c, 0x0f, 0x5a, 0x6f, 0xf4, 0xcd, 0xc3, 0xb6, 0x4e, 0x66,
-	0x2d, 0x16, 0x42, 0x7b, 0x22, 0x31, 0x56, 0x98, 0x84, 0x9d, 0xa1, 0x37, 0xf2, 0x23, 0x67, 0x92,
-	0xa7, 0x9c, 0x27, 0xda, 0x13, 0x18, 0x8f, 0x35, 0x19, 0x83, 0xcd, 0xb2, 0x14, 0x49, 0x08, 0x3a,
-	0x93, 0x3e, 0x53, 0xfe, 0x42, 0xc5, 0xaa, 0x2c, 0xc2, 0xae, 0xc9, 0x6f, 0x2c, 0x2a, 0x2a, 0x8d,
-	0x6f, 0xc6, 0x33, 0x91, 0x0a, 0x15, 0xf6, 0x4c, 0x51, 0x69, 0x7c, 0xf3, 0x23, 0xd9, 0xec, 0x7d,
-	0xe8, 0x5d, 0xe6, 0x72, 0x82, 0x63, 0x93, 0x39, 0xdc, 0x1a, 0x7a, 0xa3, 0x4e, 0xd4, 0xd5, 0xd8,
-	0x4b, 0x0d, 0xb1, 0x11, 0xb4, 0x0b, 0x94, 0xaf, 0xc5, 0x04, 0xc3, 0xfe, 0x4a, 0xeb, 0xcf, 0x0d,
-	0x1a, 0x39, 0x37, 0x45, 0xce, 0x65, 0x7e, 0x29, 0x66, 0x18, 0x6e, 0xaf, 0x44, 0x9e, 0x19, 0x34,
-	0x72, 0x6e, 0xfe, 0x87, 0x07, 0x5d, 0x12, 0xea, 0xbc, 0x4c, 0xd3, 0x58, 0x3a, 0x69, 0xbc, 0x85,
-	0x34, 0x4f, 0xa0, 0x8b, 0x99, 0x92, 0xb7, 0xe3, 0x49, 0x5e, 0x66, 0x4a, 0x6b, 0xd6, 0x8c, 0x40,
-	0x43, 0x47, 0x84, 0x90, 0x76, 0x54, 0xdc, 0xd8, 0x88, 0x60, 0xb5, 0x23, 0xe4, 0x5c, 0x0b, 0xf1,
-	0x2e, 0x74, 0xb4, 0x1b, 0x33, 0x27, 0x5d, 0x9b, 0xec, 0xe3, 0x2c, 0xa1, 0x1b, 0xe3, 0x2c, 0x9e,
-	0x17, 0x98, 0x8c, 0x95, 0x48, 0xd1, 0x0a, 0xd8, 0xb5, 0xd8, 0xcf, 0x22, 0x45, 0x1e, 0x41, 0xff,
-	0x28, 0x4f, 0xd3, 0x38, 0x4b, 0x22, 0x7c, 0x55, 0x62, 0xa1, 0xb4, 0x46, 0x06, 0xb1, 0x45, 0x3a,
-	0x93, 0x94, 0x88, 0xe5, 0xd5, 0x53, 0x3b, 0x56, 0xfa, 0x6c, 0xb1, 0x03, 0x5b, 0x96, 0x3e, 0xf3,
-	0x23, 0xd8, 0x5e, 0xe4, 0x2c, 0xe6, 0x79, 0x56, 0xe0, 0x03, 0x49, 0xf7, 0xa0, 0x25, 0xb1, 0x28,
-	0x67, 0xca, 0xa6, 0xb5, 0x16, 0xff, 0xd3, 0xb6, 0xcd, 0x95, 0x55, 0x6f, 0xdb, 0x62, 0x2a, 0x37,
-	0xd6, 0x4e, 0xa5, 0x5f, 0x9b, 0xca, 0x1d, 0xf0, 0x65, 0xfc, 0x46, 0x37, 0xa9, 0x13, 0xd1, 0x91,
-	0x1a, 0x44, 0xf3, 0x42, 0xb5, 0x60, 0xa6, 0x0a, 0xd7, 0xa0, 0x34, 0xbe, 0x39, 0xb2, 0xd0, 0x72,
-	0xa4, 0xae, 0xb1, 0x70, 0x73, 0xae, 0x47, 0xea, 0x1a, 0x0b, 0xce, 0xa1, 0x77, 0x4c, 0x42, 0xb9,
-	0x22, 0xdd, 0xac, 0x7a, 0xcb, 0x59, 0xe5, 0x1f, 0x40, 0xdf, 0xcd, 0xc4, 0x03, 0x51, 0x2f, 0xa0,
-	0x4b, 0x29, 0x5d, 0xc8, 0x2e, 0x34, 0xf5, 0x04, 0xd8, 0x18, 0x63, 0x68, 0x62, 0x81, 0xd2, 0x09,
-	0x40, 0x67, 0xc2, 0xa8, 0x36, 0x7d, 0xd9, 0x4e, 0xa4, 0xcf, 0xfc, 0xcc, 0x88, 0x8a, 0x99, 0x7a,
-	0x38, 0xdf, 0xc8, 0xa8, 0x82, 0x76, 0xec, 0x96, 0x43, 0xec, 0xd8, 0xce, 0xcd, 0x7f, 0x85, 0x5d,
-	0x8b, 0x7d, 0x8f, 0x33, 0x54, 0xff, 0x52, 0x67, 0xb8, 0x9a, 0x37, 0x58, 0xe4, 0x59, 0xdc, 0xc0,
-	0x5f, 0xde, 0x80, 0x7f, 0x0b, 0x7d, 0xb7, 0x5e, 0x95, 0x06, 0x51, 0x94, 0x57, 0xb9, 0x67, 0xb8,
-	0x5c, 0x4d, 0x9b, 0xd3, 0x9a, 0x07, 0x7f, 0xb7, 0xc1, 0x3f, 0x9c, 0x0b, 0xf6, 0x11, 0x74, 0x8e,
-	0xb3, 0x57, 0x25, 0xd2, 0x8b, 0x58, 0x7b, 0x32, 0x07, 0x35, 0x9b, 0x37, 0xd8, 0xc7, 0x00, 0xcf,
-	0x51, 0xb9, 0x17, 0x74, 0xcb, 0xfa, 0xcd, 0x7b, 0x7d, 0x6f, 0x78, 0x70, 0x22, 0x32, 0x51, 0x4c,
-	0xff, 0x5b, 0xf6, 0x2f, 0xa0, 0x77, 0x82, 0x6a, 0x32, 0xb5, 0xca, 0xb3, 0x47, 0xb5, 0xd7, 0xc1,
-	0x5c, 0x74, 0x50, 0x7b, 0x34, 0x78, 0x83, 0x3d, 0x03, 0xd0, 0xc4, 0xe7, 0x32, 0x9e, 0x4f, 0xd7,
-	0xd1, 0x7a, 0x16, 0xd6, 0x41, 0xbc, 0xc1, 0xbe, 0x82, 0x2d, 0x4d, 0xa2, 0xff, 0x17, 0xd9, 0x65,
-	0xbe, 0x8e, 0xb7, 0x5d, 0xa9, 0x93, 0xe2, 0x78, 0x83, 0x3d, 0x85, 0xde, 0x59, 0x5e, 0xa8, 0x05,
-	0xb3, 0x1e, 0x72, 0x6f, 0x89, 0xdd, 0x43, 0x39, 0x99, 0x8a, 0xd7, 0x48, 0x41, 0xcc, 0x15, 0xa3,
-	0x17, 0x61, 0xc0, 0x2a, 0x7c, 0xfb, 0xe2, 0xf1, 0xc6, 0xc8, 0x63, 0x5f, 0xc2, 0xce, 0x09, 0x3d,
-	0xb4, 0xff, 0x9f, 0xb9, 0x0f, 0xc1, 0xe2, 0x72, 0xac, 0x1a, 0xe4, 0x6e, 0x55, 0xfd, 0x7c, 0xf2,
-	0x06, 0xfb, 0xd4, 0x76, 0x50, 0x67, 0x65, 0xef, 0x54, 0xff, 0x63, 0x0d, 0xe3, 0x43, 0x08, 0xa8,
-	0x07, 0x86, 0xb0, 0x5a, 0xd4, 0x8a, 0xc5, 0x1b, 0xec, 0x13, 0x08, 0x68, 0x4d, 0x4d, 0xa8, 0x2b,
-	0xa6, 0xb2, 0xb8, 0x77, 0x08, 0x9f, 0x43, 0xcf, 0x2e, 0x8e, 0xe1, 0x3c, 0xaa, 0x6d, 0xd8, 0x1a,
-	0xda, 0x37, 0xb0, 0x65, 0x16, 0xcd, 0xc6, 0xb1, 0xc7, 0xab, 0xbc, 0x95, 0x2d, 0xbc, 0xc3, 0xde,
-	0x87, 0xce, 0x59, 0xa9, 0x7e, 0x3a, 0x2c, 0xd5, 0x94, 0xed, 0x58, 0x9f, 0xb6, 0x5e, 0x16, 0x95,
-	0xe1, 0x5e, 0x2a, 0xfa, 0x19, 0xf4, 0xbe, 0x13, 0x59, 0x42, 0x5e, 0xdd, 0xe5, 0xbb, 0x9c, 0x3b,
-	0x88, 0x99, 0x3a, 0x53, 0x86, 0xdd, 0xde, 0xc5, 0xdd, 0x56, 0xb7, 0xf9, 0xbe, 0xa9, 0xfb, 0x1a,
-	0xda, 0xf6, 0x0b, 0xb1, 0xd2, 0x90, 0xe5, 0x57, 0x68, 0xb0, 0x57, 0x87, 0xcd, 0x87, 0x84, 0x37,
-	0x2e, 0x5a, 0xda, 0xf1, 0xec, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x52, 0x9f, 0x07, 0x2e, 0x7c,
-	0x09, 0x00, 0x00,
+	// 2195 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x58, 0xcd, 0x72, 0xdc, 0xc6,
+	0x11, 0x26, 0x16, 0xfb, 0x87, 0xc6, 0x92, 0xa2, 0xc7, 0x94, 0x0c, 0xaf, 0x95, 0x88, 0x81, 0x2f,
+	0x74, 0x2a, 0xa1, 0x63, 0x59, 0x89, 0x25, 0x95, 0x2b, 0x15, 0x4a, 0x96, 0x2c, 0xc6, 0x96, 0xc3,
+	0x02, 0xe5, 0x4a, 0x55, 0x72, 0xd8, 0xc2, 0x2e, 0x86, 0xc4, 0x98, 0xbb, 0x00, 0x04, 0x0c, 0x48,
+	0xae, 0xaa, 0xf2, 0x00, 0xbe, 0xe4, 0x9a, 0x43, 0xae, 0xa9, 0xbc, 0x41, 0x9e, 0x21, 0x79, 0x8b,
+	0x5c, 0xf2, 0x06, 0xa9, 0xdc, 0x53, 0xdd, 0x33, 0x83, 0x05, 0x96, 0xbb, 0x94, 0x94, 0x43, 0x4e,
+	0x98, 0xfe, 0xa6, 0x7b, 0xa6, 0xa7, 0x7f, 0x66, 0xba, 0x01, 0x4e, 0x98, 0x89, 0xfd, 0x2c, 0x4f,
+	0x65, 0xca, 0x3a, 0xf4, 0x19, 0xc2, 0x09, 0xe7, 0x91, 0x82, 0xfc, 0xdf, 0x43, 0xf7, 0xb7, 0x69,
+	0x7e, 0xc6, 0x73, 0xb6, 0x05, 0xad, 0xc3, 0xc8, 0xb3, 0x76, 0xad, 0x3d, 0x27, 0x68, 0x1d, 0x46,
+	0xec, 0x0e, 0xb4, 0x91, 0xcf, 0x6b, 0xed, 0x5a, 0x7b, 0xee, 0x5d, 0x57, 0xf1, 0xef, 0x3f, 0xe5,
+	0x3c, 0x0a, 0x68, 0x82, 0xed, 0x82, 0xfd, 0x5d, 0x3a, 0xf6, 0x6c, 0x9a, 0xdf, 0xaa, 0xcd, 0xff,
+	0x3a, 0x1d, 0x07, 0x38, 0xe5, 0xff, 0xd5, 0x86, 0x9e, 0x06, 0xd8, 0x36, 0xd8, 0x67, 0x7c, 0xae,
+	0xd7, 0xc7, 0x21, 0x6e, 0x28, 0xd4, 0xf2, 0x4e, 0xd0, 0x12, 0x11, 0xfb, 0x01, 0x40, 0xce, 0x67,
+	0xa9, 0xe4, 0x23, 0x64, 0xb4, 0x09, 0x77, 0x14, 0xf2, 0x15, 0x9f, 0xb3, 0x0f, 0xc0, 0x91, 0x61,
+	0x7e, 0xca, 0xe5, 0x48, 0x44, 0x5e, 0x9b, 0x66, 0xfb, 0x0a, 0x38, 0x8c, 0xd8, 0x0e, 0x74, 0x0a,
+	0x19, 0xe6, 0xd2, 0xeb, 0xec, 0x5a, 0x7b, 0x9d, 0x40, 0x11, 0x28, 0x92, 0x85, 0xa7, 0x7c, 0x54,
+	0x88, 0x57, 0xdc, 0xeb, 0xd2, 0x4c, 0x1f, 0x81, 0x63, 0xf1, 0x8a, 0xb3, 0x5b, 0xd0, 0xbd, 0xa0,
+	0x93, 0x7b, 0x3d, 0x5a, 0x4c, 0x53, 0xcc, 0x83, 0xde, 0x24, 0xe7, 0xa1, 0xe4, 0x91, 0xd7, 0xdf,
+	0xb5, 0xf6, 0xec, 0xc0, 0x90, 0x38, 0x53, 0x66, 0x11, 0xcd, 0x38, 0x6a, 0x46, 0x93, 0x8c, 0x41,
+	0xbb, 0x2c, 0x45, 0xe4, 0x01, 0xad, 0x44, 0x63, 0x5c, 0xbf, 0x90, 0xa1, 0x2c, 0x0b, 0xcf, 0x55,
+	0xeb, 0x2b, 0x0a, 0x95, 0x9a, 0x85, 0x97, 0xa3, 0xa9, 0x98, 0x09, 0xe9, 0x0d, 0x94, 0x52, 0xb3,
+	0xf0, 0xf2, 0x6b, 0xa4, 0xd9, 0x8f, 0x60, 0x70, 0x92, 0xe6, 0x13, 0x3e, 0x52, 0x2b, 0x7b, 0x9b,
+	0xbb, 0xd6, 0x5e, 0x3f, 0x70, 0x09, 0xfb, 0x96, 0x20, 0xb6, 0x07, 0xbd, 0x82, 0xe7, 0xe7, 0x62,
+	0xc2, 0xbd, 0xad, 0x86, 0xe9, 0x8f, 0x15, 0x1a, 0x98, 0x69, 0xe4, 0xcc, 0xf2, 0xf4, 0x44, 0x4c,
+	0xb9, 0x77, 0xa3, 0xc1, 0x79, 0xa4, 0xd0, 0xc0, 0x4c, 0xfb, 0x7f, 0xb6, 0xc0, 0x45, 0x47, 0x1d,
+	0x97, 0xb3, 0x59, 0x98, 0x1b, 0xd7, 0x58, 0x95, 0x6b, 0xee, 0x80, 0xcb, 0x13, 0x99, 0xcf, 0x47,
+	0x93, 0xb4, 0x4c, 0x24, 0xf9, 0xac, 0x13, 0x00, 0x41, 0x8f, 0x11, 0x41, 0xdf, 0xa1, 0x72, 0x23,
+	0xe5, 0x04, 0xed, 0x3b, 0x44, 0x8e, 0xc9, 0x11, 0xef, 0x43, 0x9f, 0xa6, 0x79, 0x62, 0x5c, 0xd7,
+	0x43, 0xfa, 0x49, 0x12, 0xe1, 0x89, 0xf9, 0x34, 0xcc, 0x0a, 0x1e, 0x8d, 0xa4, 0x98, 0x71, 0xed,
+	0x40, 0x57, 0x63, 0x2f, 0xc4, 0x8c, 0xfb, 0x01, 0x6c, 0x3d, 0x4e, 0x67, 0xb3, 0x30, 0x89, 0x02,
+	0xfe, 0xb2, 0xe4, 0x85, 0x24, 0x1f, 0x29, 0x44, 0x2b, 0x69, 0x48, 0xf4, 0x44, 0x98, 0x9f, 0x7e,
+	0xa2, 0xc3, 0x8a, 0xc6, 0x1a, 0xbb, 0xab, 0xd5, 0xa2, 0xb1, 0xff, 0x18, 0x6e, 0x54, 0x6b, 0x16,
+	0x59, 0x9a, 0x14, 0xfc, 0x9a, 0x45, 0x6f, 0x41, 0x37, 0xe7, 0x45, 0x39, 0x95, 0x7a, 0x59, 0x4d,
+	0xf9, 0xff, 0xd2, 0x66, 0x33, 0x6a, 0x2d, 0x9b, 0xad, 0x8a, 0xca, 0xd6, 0xda, 0xa8, 0xb4, 0x97,
+	0xa2, 0x72, 0x1b, 0xec, 0x3c, 0xbc, 0x20, 0x23, 0xf5, 0x03, 0x1c, 0xa2, 0x81, 0x30, 0x5e, 0x50,
+	0x17, 0x9e, 0xc8, 0xc2, 0x18, 0x68, 0x16, 0x5e, 0x3e, 0xd6, 0xd0, 0x22, 0xa4, 0xce, 0x78, 0xe1,
+	0x75, 0x6b, 0x21, 0x75, 0xc6, 0x0b, 0x8a, 0xcd, 0xa2, 0x8a, 0x72, 0x1a, 0xe3, 0x81, 0x62, 0x11,
+	0x45, 0x3c, 0xa1, 0x10, 0xef, 0x07, 0x9a, 0x42, 0x85, 0x5f, 0x96, 0x3c, 0x9f, 0x53, 0x7c, 0x3b,
+	0x81, 0x22, 0xfc, 0x31, 0x0c, 0x9e, 0xa0, 0xab, 0xcd, 0x31, 0x4d, 0xb4, 0x5b, 0xb5, 0x68, 0x5f,
+	0xd6, 0xb2, 0xf5, 0x1a, 0x2d, 0xed, 0xa6, 0x96, 0xfe, 0x3d, 0xd8, 0x32, 0x51, 0x79, 0xcd, 0x2e,
+	0x4b, 0x57, 0x86, 0xff, 0x15, 0xb8, 0x28, 0x6e, 0x44, 0x76, 0xa0, 0x43, 0x31, 0xa9, 0x65, 0x14,
+	0x51, 0x19, 0xa0, 0x55, 0x33, 0x00, 0x83, 0x36, 0xea, 0x41, 0x6a, 0xf4, 0x03, 0x1a, 0xfb, 0x47,
+	0x2a, 0xcc, 0x78, 0x22, 0xaf, 0x5f, 0x6f, 0x4f, 0xc5, 0x09, 0xd7, 0x89, 0xb0, 0x48, 0x2b, 0x23,
+	0x6d, 0xa6, 0xfd, 0xdf, 0xc1, 0x8e, 0xc6, 0xbe, 0xe0, 0x53, 0x2e, 0x5f, 0xa3, 0xa7, 0xd7, 0x5c,
+	0xd7, 0xa9, 0xd6, 0xa9, 0x4e, 0x60, 0x2f, 0x4e, 0xe0, 0x9f, 0xc1, 0x36, 0x39, 0xe5, 0x49, 0x24,
+	0xe4, 0xff, 0x74, 0xfe, 0x71, 0x1a, 0x99, 0x5b, 0x96, 0xc6, 0x98, 0xa4, 0x79, 0x78, 0x31, 0x22,
+	0x5c, 0x27, 0x69, 0x1e, 0x5e, 0x3c, 0x4a, 0xa3, 0xb9, 0xff, 0x4b, 0x60, 0xb4, 0xd9, 0x9b, 0x1c,
+	0x63, 0xc5, 0x76, 0xfe, 0x73, 0x70, 0x9f, 0x89, 0xa8, 0xe1, 0x5a, 0x64, 0xb1, 0x9a, 0x21, 0xa9,
+	0x6e, 0x73, 0x93, 0x63, 0x8a, 0x42, 0xde, 0x58, 0x44, 0x95, 0xa7, 0x70, 0xec, 0xfb, 0x00, 0xc7,
+	0x65, 0x56, 0x53, 0xa3, 0x10, 0xc9, 0x84, 0xd3, 0x72, 0x76, 0xa0, 0x08, 0xff, 0x73, 0x70, 0x8e,
+	0xcb, 0x4c, 0xdf, 0x99, 0x37, 0xa1, 0x5b, 0x94, 0xd9, 0xa8, 0x8a, 0xa6, 0x4e, 0x51, 0x66, 0x87,
+	0x8d, 0x0b, 0xbd, 0xd5, 0xb8, 0xd0, 0xfd, 0x07, 0xe0, 0xd2, 0x0e, 0xfa, 0x6a, 0xf8, 0xb1, 0x61,
+	0x2c, 0x3c, 0x6b, 0xd7, 0xde, 0x73, 0xef, 0x6e, 0x9b, 0x3b, 0xd7, 0x6c, 0x61, 0x44, 0x0b, 0xff,
+	0xef, 0x2d, 0x18, 0x1c, 0x97, 0xe3, 0x62, 0x92, 0x8b, 0x4c, 0x8a, 0x94, 0x92, 0x4a, 0xa6, 0x99,
+	0x98, 0x98, 0xbd, 0x89, 0xc0, 0x44, 0x8f, 0xcb, 0xb1, 0x3e, 0x2c, 0x0e, 0xd9, 0x10, 0xfa, 0x93,
+	0x70, 0x3a, 0x1d, 0x87, 0x93, 0x33, 0xed, 0x97, 0x8a, 0xa6, 0xc7, 0x84, 0x4f, 0x72, 0x2e, 0xb5,
+	0x67, 0x34, 0x85, 0x32, 0xe7, 0x3c, 0x17, 0x27, 0x82, 0x47, 0x74, 0x31, 0xf4, 0x83, 0x8a, 0x66,
+	0x1f, 0xc2, 0xe6, 0x94, 0x87, 0x05, 0x1f, 0xf1, 0xcb, 0x4c, 0xe4, 0xfa, 0x66, 0xb0, 0x83, 0x01,
+	0x81, 0x4f, 0x14, 0x56, 0x37, 0x41, 0xaf, 0xf9, 0xa6, 0xdd, 0x82, 0x6e, 0x24, 0x4e, 0x79, 0x21,
+	0xe9, 0x8e, 0x70, 0x02, 0x4d, 0x99, 0x67, 0xdf, 0x59, 0xfb, 0xec, 0xe3, 0x5d, 0x90, 0xf1, 0x24,
+	0x12, 0xc9, 0xe9, 0x68, 0x96, 0x46, 0x5c, 0xbf, 0x8a, 0xae, 0xc6, 0x9e, 0xa7, 0x11, 0x47, 0xdd,
+	0x0c, 0x0b, 0xa9, 0x43, 0x6f, 0xa4, 0x1d, 0x18, 0xb9, 0xaf, 0x11, 0xf3, 0xff, 0x61, 0x81, 0x7b,
+	0x54, 0x16, 0x71, 0xcd, 0xd1, 0x2b, 0x0c, 0xc9, 0xa0, 0x4d, 0xbb, 0xe8, 0x78, 0xc3, 0x31, 0xbb,
+	0x0d, 0xce, 0x24, 0x0e, 0xa7, 0x53, 0x9e, 0x9c, 0x72, 0xf3, 0x1a, 0x55, 0xc0, 0xc2, 0x30, 0x05,
+	0x9f, 0xa4, 0x49, 0x54, 0x78, 0xed, 0x9a, 0x61, 0x8e, 0x15, 0x56, 0x65, 0x08, 0x5a, 0x75, 0xa0,
+	0x33, 0xe4, 0x36, 0x38, 0x85, 0x38, 0x4d, 0x42, 0x59, 0xe6, 0xaa, 0x9e, 0x70, 0x82, 0x05, 0xd0,
+	0xf0, 0x5f, 0xaf, 0xe9, 0x3f, 0xff, 0x1b, 0x18, 0xa8, 0x93, 0xe8, 0x80, 0x6a, 0x28, 0x68, 0x2d,
+	0x2b, 0xa8, 0x4d, 0xdc, 0x5a, 0x5f, 0x59, 0xfd, 0xdb, 0x82, 0x1b, 0xcf, 0xca, 0x71, 0x23, 0xce,
+	0x8c, 0x21, 0xac, 0x9a, 0x21, 0xde, 0x83, 0x1e, 0xd6, 0x6a, 0xa3, 0xea, 0xd6, 0xec, 0x22, 0xa9,
+	0x0a, 0x26, 0x65, 0x4b, 0xbb, 0x6e, 0xcb, 0xfa, 0x11, 0xda, 0x6b, 0x43, 0xb0, 0xd3, 0x08, 0xc1,
+	0x2b, 0xd6, 0xec, 0xae, 0xb0, 0xa6, 0x07, 0x3d, 0x13, 0x85, 0x3a, 0xcc, 0x34, 0x69, 0xf2, 0xa0,
+	0xbf, 0xc8, 0x83, 0x5a, 0x01, 0xe6, 0x34, 0x0a, 0x30, 0xff, 0x8f, 0x16, 0xb8, 0xcf, 0xca, 0xf1,
+	0x17, 0x7c, 0x2a, 0xce, 0x79, 0x3e, 0x5f, 0x51, 0x53, 0x0e, 0xa1, 0x1f, 0x4a, 0xc9, 0x67, 0x59,
+	0xf5, 0x04, 0x55, 0x34, 0x86, 0x65, 0xc2, 0x2f, 0xe5, 0x48, 0x03, 0x74, 0x72, 0x3b, 0x70, 0x11,
+	0x3b, 0x50, 0x50, 0x7d, 0xeb, 0x76, 0x63, 0x6b, 0xba, 0xeb, 0xf2, 0x3c, 0xcd, 0xf5, 0xe1, 0x15,
+	0xe1, 0xff, 0x10, 0x06, 0x07, 0x13, 0x99, 0xe6, 0x6b, 0x0a, 0x00, 0x3f, 0x81, 0x0e, 0xcd, 0xd7,
+	0x4b, 0x31, 0xeb, 0xda, 0x52, 0x0c, 0x2b, 0xa9, 0xac, 0x1c, 0x4f, 0xc5, 0x84, 0xaa, 0x60, 0xe5,
+	0x34, 0x47, 0x21, 0x58, 0x05, 0xdf, 0x06, 0xe7, 0x24, 0x9d, 0x4e, 0xd3, 0x0b, 0x9e, 0x9b, 0x47,
+	0x74, 0x01, 0xf8, 0x7f, 0x80, 0x3e, 0xed, 0x87, 0x9c, 0x35, 0xd7, 0x5b, 0x0d, 0xd7, 0xdf, 0x01,
+	0x37, 0xcb, 0xc5, 0x79, 0x28, 0x79, 0x6d, 0x0b, 0xd0, 0x10, 0x4a, 0x36, 0x55, 0xb0, 0x97, 0x55,
+	0x58, 0x6b, 0x24, 0xff, 0xfb, 0x16, 0x0c, 0x0e, 0x93, 0x71, 0x7a, 0x69, 0xec, 0xb1, 0x56, 0x87,
+	0x1d, 0xe8, 0x84, 0xa8, 0xa8, 0xde, 0x5d, 0x11, 0x18, 0x62, 0x33, 0x2e, 0xe3, 0x34, 0xd2, 0x9b,
+	0x6a, 0x0a, 0x23, 0x3b, 0x0b, 0x65, 0xac, 0x43, 0x92, 0xc6, 0x88, 0xc5, 0x69, 0x61, 0x82, 0x91,
+	0xc6, 0xec, 0x21, 0xf4, 0x62, 0x1e, 0x46, 0x68, 0x9a, 0x2e, 0x5d, 0xd3, 0xbb, 0xda, 0xca, 0x75,
+	0xa5, 0xf6, 0x9f, 0x29, 0x16, 0x55, 0xd2, 0x18, 0x81, 0x2a, 0xdf, 0x7b, 0x8b, 0x7c, 0x1f, 0x3e,
+	0x84, 0x41, 0x9d, 0x79, 0x45, 0xbc, 0xed, 0x40, 0xe7, 0x3c, 0x9c, 0x96, 0xe6, 0xf6, 0x51, 0xc4,
+	0xc3, 0xd6, 0x7d, 0xcb, 0xff, 0x10, 0x36, 0xf5, 0xae, 0x3a, 0xe5, 0x19, 0xb4, 0xe5, 0x3c, 0xab,
+	0xd2, 0x13, 0xc7, 0xfe, 0x5f, 0x2c, 0xe8, 0x3f, 0xd5, 0xde, 0x7b, 0x5b, 0x63, 0xed, 0x40, 0x47,
+	0xe0, 0x06, 0x26, 0x83, 0x89, 0xc0, 0x20, 0x2f, 0xe2, 0x30, 0xc7, 0x65, 0x68, 0x52, 0x99, 0xcc,
+	0x55, 0x18, 0x29, 0x84, 0x56, 0x4e, 0xc7, 0xdf, 0xf1, 0x49, 0x95, 0xc8, 0x8a, 0xaa, 0xfb, 0xb5,
+	0xdb, 0xf4, 0xeb, 0x7f, 0x2c, 0xd8, 0x3e, 0x98, 0x48, 0x71, 0x2e, 0xe4, 0xfc, 0x9a, 0xe4, 0x5b,
+	0x7b, 0xd9, 0xdc, 0x84, 0xee, 0x19, 0x9f, 0x23, 0xae, 0x75, 0x3d, 0xe3, 0x73, 0x75, 0xae, 0xba,
+	0x92, 0xfa, 0x04, 0x98, 0xc2, 0x7a, 0x2f, 0x7d, 0xf9, 0x56, 0x74, 0x23, 0xbd, 0xbb, 0xaf, 0x49,
+	0xef, 0xde, 0xb5, 0xe9, 0xdd, 0x5f, 0x93, 0xde, 0x4e, 0x3d, 0xbd, 0xbf, 0xb7, 0x60, 0xcb, 0x74,
+	0x55, 0xd7, 0x94, 0x2e, 0xde, 0xa2, 0x23, 0xd3, 0x85, 0x9b, 0x26, 0xd1, 0x46, 0x65, 0x3e, 0xd5,
+	0xa7, 0xc6, 0x21, 0xca, 0x27, 0xe1, 0x8c, 0x9b, 0x50, 0xc6, 0x31, 0x3d, 0x86, 0x65, 0x11, 0x8f,
+	0xaa, 0xab, 0x57, 0xf9, 0x65, 0x80, 0xe0, 0x63, 0xf3, 0x82, 0xfc, 0xd3, 0x02, 0xe7, 0x39, 0x8f,
+	0x44, 0xf8, 0x68, 0x9a, 0x8e, 0x29, 0xfa, 0xc3, 0x22, 0x36, 0x6a, 0xe0, 0x98, 0xea, 0x6b, 0x31,
+	0xe3, 0x23, 0x8a, 0x32, 0xa5, 0x48, 0x1f, 0x81, 0x17, 0xf3, 0x8c, 0xa2, 0x2f, 0xe7, 0x27, 0xe6,
+	0xca, 0xa0, 0x31, 0x5a, 0x33, 0xcd, 0xb3, 0x38, 0x4c, 0xaa, 0x4c, 0xae, 0xe8, 0xba, 0xa9, 0x3a,
+	0x57, 0x4c, 0x75, 0x21, 0x22, 0x19, 0x6b, 0x07, 0x28, 0x82, 0x3a, 0x0a, 0x2e, 0x4e, 0x63, 0x65,
+	0xf7, 0x4e, 0xa0, 0x29, 0xf6, 0x11, 0x74, 0x65, 0x5c, 0xce, 0xc6, 0x85, 0xd7, 0xa7, 0x8c, 0x7c,
+	0x47, 0x67, 0x24, 0x1d, 0xe5, 0x05, 0xce, 0x04, 0x9a, 0xc1, 0x97, 0x00, 0x0b, 0x14, 0x15, 0xa6,
+	0x06, 0x49, 0x9f, 0xb0, 0x10, 0xaf, 0x2a, 0x73, 0xb6, 0x16, 0xe6, 0xac, 0x94, 0xb1, 0x57, 0x2b,
+	0xd3, 0x6e, 0x28, 0x63, 0xac, 0xd6, 0x59, 0x58, 0xcd, 0x7f, 0xa0, 0xcd, 0x1a, 0xa0, 0x45, 0x76,
+	0xa0, 0x93, 0x5e, 0x24, 0x95, 0x7b, 0x15, 0x41, 0xcb, 0x85, 0x45, 0xcc, 0xf1, 0x49, 0xb1, 0x31,
+	0xac, 0x15, 0xe5, 0xff, 0xc9, 0x02, 0x97, 0x64, 0x9f, 0x0b, 0x0c, 0x17, 0x2c, 0xa0, 0x55, 0x97,
+	0x5c, 0x65, 0x70, 0x8f, 0xe8, 0xc3, 0xe8, 0xff, 0xfc, 0x2e, 0xdd, 0xfd, 0xdb, 0x26, 0xd8, 0x07,
+	0x99, 0x60, 0x3f, 0x81, 0xfe, 0x93, 0xe4, 0x65, 0xc9, 0xf1, 0x07, 0xcc, 0x52, 0x1d, 0x31, 0x5c,
+	0xa2, 0xfd, 0x0d, 0xf6, 0x53, 0x80, 0x2f, 0xb9, 0xd4, 0x34, 0xdb, 0xd4, 0xf3, 0xea, 0xf7, 0xd0,
+	0x4a, 0x76, 0xe7, 0xa9, 0x48, 0x44, 0x11, 0xbf, 0xe9, 0xea, 0x8e, 0x2a, 0x95, 0xdf, 0x8c, 0x7d,
+	0x1f, 0x20, 0xe0, 0x54, 0x43, 0xbc, 0x19, 0xff, 0x67, 0x30, 0x78, 0xca, 0xe5, 0x24, 0xd6, 0x0f,
+	0x2a, 0xbb, 0xb9, 0xf4, 0xc0, 0xaa, 0xfc, 0x1d, 0x2e, 0xbd, 0xbb, 0xfe, 0x06, 0xfb, 0x14, 0x80,
+	0x04, 0xbf, 0xcc, 0xc3, 0x2c, 0x5e, 0x27, 0x36, 0xd0, 0x30, 0x31, 0xf9, 0x1b, 0xec, 0x01, 0x6c,
+	0x92, 0x10, 0xee, 0x2f, 0x92, 0x93, 0x74, 0x9d, 0xdc, 0x8d, 0x9a, 0x9e, 0xc8, 0xe7, 0x6f, 0xb0,
+	0x4f, 0x60, 0x70, 0x94, 0x16, 0xb2, 0x92, 0x5c, 0x66, 0x59, 0xa9, 0xa2, 0x7b, 0x90, 0x4f, 0x62,
+	0x71, 0xce, 0x91, 0x89, 0x19, 0x65, 0xe8, 0x51, 0x1a, 0xb2, 0x9a, 0xbc, 0xfe, 0x7f, 0xe3, 0x6f,
+	0xec, 0x59, 0xec, 0x3e, 0x6c, 0x3f, 0x4d, 0xf3, 0x09, 0x7f, 0x7b, 0xc9, 0x7d, 0x70, 0xaa, 0xc3,
+	0xb1, 0x3a, 0x93, 0x39, 0x55, 0xfd, 0x67, 0xa0, 0xbf, 0xc1, 0x7e, 0xa6, 0x2d, 0xa8, 0x1e, 0xc9,
+	0x77, 0xeb, 0x7b, 0xac, 0x91, 0xf8, 0x08, 0x1c, 0xb4, 0x81, 0x12, 0x68, 0x2a, 0xd5, 0xa0, 0xfc,
+	0x0d, 0xf6, 0x31, 0x38, 0xd8, 0xe2, 0x2b, 0x56, 0xa3, 0x4c, 0xad, 0xe9, 0xbf, 0x22, 0xf0, 0x73,
+	0x18, 0xe8, 0xa6, 0x5b, 0xc9, 0xdc, 0x5c, 0xea, 0xce, 0xd7, 0x88, 0x7d, 0x0e, 0x9b, 0xaa, 0xbb,
+	0xd5, 0x7c, 0xec, 0x83, 0xa6, 0x5c, 0xa3, 0xf5, 0xbd, 0x22, 0x7d, 0x0f, 0x1c, 0x6c, 0xc4, 0xd5,
+	0x8e, 0xef, 0xd5, 0x27, 0x6b, 0xfd, 0xf9, 0x15, 0xa9, 0xfb, 0xe0, 0xaa, 0x65, 0x95, 0xdc, 0xfb,
+	0xf5, 0xe9, 0xeb, 0xf7, 0xfb, 0x18, 0x1c, 0x6c, 0xa8, 0x9b, 0x56, 0xa9, 0xb5, 0xd8, 0x57, 0x04,
+	0xee, 0x02, 0xe0, 0xf4, 0x41, 0x29, 0xe3, 0x34, 0x5f, 0x29, 0x71, 0x35, 0xec, 0xf6, 0xa1, 0x7f,
+	0x54, 0xca, 0xdf, 0xa0, 0x0c, 0x33, 0x0d, 0x2f, 0x51, 0xdf, 0x16, 0x3c, 0x5f, 0xc1, 0x7f, 0x0f,
+	0x06, 0x8f, 0x44, 0x12, 0xe1, 0x2c, 0x85, 0xce, 0x55, 0x99, 0x2b, 0x88, 0xbf, 0xc1, 0x7e, 0x01,
+	0x70, 0x10, 0x45, 0xfa, 0x99, 0xad, 0xbc, 0xd5, 0x7c, 0x76, 0x57, 0xe5, 0xd1, 0x03, 0xe3, 0xb0,
+	0xb7, 0x17, 0x7d, 0x08, 0x3d, 0xfd, 0xf3, 0xaf, 0x11, 0x1d, 0x8b, 0x1f, 0x8c, 0xc3, 0x5b, 0xcb,
+	0xb0, 0x2a, 0xe2, 0x48, 0xf6, 0x06, 0x05, 0x7b, 0xd5, 0xf9, 0x17, 0xec, 0x9d, 0xc5, 0xcf, 0x00,
+	0x23, 0xcf, 0xea, 0x50, 0x25, 0xfb, 0x19, 0x38, 0xba, 0x63, 0x1b, 0xf3, 0x2a, 0x4f, 0xea, 0x3d,
+	0xdc, 0x70, 0x15, 0x48, 0x82, 0x80, 0xed, 0xe3, 0x37, 0xa9, 0x14, 0x27, 0x0b, 0x7f, 0xd7, 0x7a,
+	0xe3, 0xe1, 0xbb, 0x0d, 0xac, 0xda, 0xf1, 0x57, 0x30, 0x58, 0xb4, 0x89, 0x63, 0xce, 0xcc, 0xb9,
+	0x96, 0x7a, 0xc7, 0xe1, 0x1a, 0x9c, 0xae, 0x2b, 0x95, 0xdc, 0xaa, 0x8f, 0x31, 0xdb, 0xd4, 0xbb,
+	0x9e, 0xe1, 0xa0, 0x0e, 0x52, 0x58, 0x53, 0x76, 0xab, 0x6a, 0xf3, 0xdd, 0x15, 0x25, 0xf8, 0x70,
+	0xa7, 0x09, 0x1a, 0x75, 0xc7, 0x5d, 0x82, 0x3f, 0xfd, 0xef, 0x00, 0x07, 0x21, 0x4c, 0x22, 0xb4,
+	0x18, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -871,6 +2572,10 @@ type ApiClient interface {
 	EnqueJob(ctx context.Context, in *FeedJob, opts ...grpc.CallOption) (*FeedJob, error)
 	GetFeedJob(ctx context.Context, in *Worker, opts ...grpc.CallOption) (*FeedJob, error)
 	FinishJob(ctx context.Context, in *FeedJob, opts ...grpc.CallOption) (*FeedJob, error)
+	// Checkpoint progress of running job, eg: start of friendfeed archiving.
+	UpdateJob(ctx context.Context, in *FeedJob, opts ...grpc.CallOption) (*FeedJob, error)
+	// Requeue running job not finished, eg: worker shutting down or throttled.
+	ReleaseJob(ctx context.Context, in *FeedJob, opts ...grpc.CallOption) (*FeedJob, error)
 	FetchProfile(ctx context.Context, in *ProfileRequest, opts ...grpc.CallOption) (*Profile, error)
 	FetchGraph(ctx context.Context, in *ProfileRequest, opts ...grpc.CallOption) (*Graph, error)
 	FetchFeedinfo(ctx context.Context, in *ProfileRequest, opts ...grpc.CallOption) (*Feedinfo, error)
@@ -888,12 +2593,29 @@ type ApiClient interface {
 	LikeEntry(ctx context.Context, in *LikeRequest, opts ...grpc.CallOption) (*Entry, error)
 	CommentEntry(ctx context.Context, in *CommentRequest, opts ...grpc.CallOption) (*Entry, error)
 	DeleteComment(ctx context.Context, in *CommentDeleteRequest, opts ...grpc.CallOption) (*Entry, error)
+	EditEntry(ctx context.Context, in *EntryEditRequest, opts ...grpc.CallOption) (*Entry, error)
+	DeleteEntry(ctx context.Context, in *EntryDeleteRequest, opts ...grpc.CallOption) (*Entry, error)
+	HideEntry(ctx context.Context, in *HideRequest, opts ...grpc.CallOption) (*Entry, error)
+	HideAuthor(ctx context.Context, in *HideRequest, opts ...grpc.CallOption) (*Profile, error)
 	PutOAuth(ctx context.Context, in *OAuthUser, opts ...grpc.CallOption) (*Profile, error)
 	// rpc BindAuth(OAuthUser) returns (OAuthUser) {}
 	BindUserFeed(ctx context.Context, in *OAuthUser, opts ...grpc.CallOption) (*OAuthUser, error)
 	// service
+	AddService(ctx context.Context, in *ServiceRequest, opts ...grpc.CallOption) (*Feedinfo, error)
 	DeleteService(ctx context.Context, in *ServiceRequest, opts ...grpc.CallOption) (*Feedinfo, error)
 	Command(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	// Simple Update Protocol, feeds updated since
+	FetchSupUpdates(ctx context.Context, in *SupRequest, opts ...grpc.CallOption) (*SupResponse, error)
+	// Subscribe to imported feed, job enqueued once the feed updated.
+	Subscribe(ctx context.Context, in *Subscription, opts ...grpc.CallOption) (*Subscription, error)
+	// WebSub intent verification and content distribution.
+	PushNotify(ctx context.Context, in *PushRequest, opts ...grpc.CallOption) (*PushResponse, error)
+	// WebSub hub of our own feeds, intent verified asynchronously.
+	HubSubscribe(ctx context.Context, in *HubSubscription, opts ...grpc.CallOption) (*HubSubscription, error)
+	// ActivityPub actor of our feed, key pair generated on first fetch.
+	FetchActor(ctx context.Context, in *ActorRequest, opts ...grpc.CallOption) (*Actor, error)
+	// ActivityPub inbox of our feed, requests signed by remote actors.
+	PostInbox(ctx context.Context, in *InboxRequest, opts ...grpc.CallOption) (*InboxResponse, error)
 }
 
 type apiClient struct {
@@ -931,6 +2653,24 @@ func (c *apiClient) FinishJob(ctx context.Context, in *FeedJob, opts ...grpc.Cal
 	return out, nil
 }
 
+func (c *apiClient) UpdateJob(ctx context.Context, in *FeedJob, opts ...grpc.CallOption) (*FeedJob, error) {
+	out := new(FeedJob)
+	err := c.cc.Invoke(ctx, "/proto.Api/UpdateJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiClient) ReleaseJob(ctx context.Context, in *FeedJob, opts ...grpc.CallOption) (*FeedJob, error) {
+	out := new(FeedJob)
+	err := c.cc.Invoke(ctx, "/proto.Api/ReleaseJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *apiClient) FetchProfile(ctx context.Context, in *ProfileRequest, opts ...grpc.CallOption) (*Profile, error) {
 	out := new(Profile)
 	err := c.cc.Invoke(ctx, "/proto.Api/FetchProfile", in, out, opts...)
@@ -1053,36 +2793,72 @@ func (c *apiClient) FetchEntry(ctx context.Context, in *EntryRequest, opts ...gr
 	return out, nil
 }
 
-func (c *apiClient) PostEntry(ctx context.Context, in *Entry, opts ...grpc.CallOption) (*Entry, error) {
+func (c *apiClient) PostEntry(ctx context.Context, in *Entry, opts ...grpc.CallOption) (*Entry, error) {
+	out := new(Entry)
+	err := c.cc.Invoke(ctx, "/proto.Api/PostEntry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiClient) LikeEntry(ctx context.Context, in *LikeRequest, opts ...grpc.CallOption) (*Entry, error) {
+	out := new(Entry)
+	err := c.cc.Invoke(ctx, "/proto.Api/LikeEntry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiClient) CommentEntry(ctx context.Context, in *CommentRequest, opts ...grpc.CallOption) (*Entry, error) {
+	out := new(Entry)
+	err := c.cc.Invoke(ctx, "/proto.Api/CommentEntry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiClient) DeleteComment(ctx context.Context, in *CommentDeleteRequest, opts ...grpc.CallOption) (*Entry, error) {
+	out := new(Entry)
+	err := c.cc.Invoke(ctx, "/proto.Api/DeleteComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiClient) EditEntry(ctx context.Context, in *EntryEditRequest, opts ...grpc.CallOption) (*Entry, error) {
 	out := new(Entry)
-	err := c.cc.Invoke(ctx, "/proto.Api/PostEntry", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/proto.Api/EditEntry", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *apiClient) LikeEntry(ctx context.Context, in *LikeRequest, opts ...grpc.CallOption) (*Entry, error) {
+func (c *apiClient) DeleteEntry(ctx context.Context, in *EntryDeleteRequest, opts ...grpc.CallOption) (*Entry, error) {
 	out := new(Entry)
-	err := c.cc.Invoke(ctx, "/proto.Api/LikeEntry", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/proto.Api/DeleteEntry", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *apiClient) CommentEntry(ctx context.Context, in *CommentRequest, opts ...grpc.CallOption) (*Entry, error) {
+func (c *apiClient) HideEntry(ctx context.Context, in *HideRequest, opts ...grpc.CallOption) (*Entry, error) {
 	out := new(Entry)
-	err := c.cc.Invoke(ctx, "/proto.Api/CommentEntry", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/proto.Api/HideEntry", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *apiClient) DeleteComment(ctx context.Context, in *CommentDeleteRequest, opts ...grpc.CallOption) (*Entry, error) {
-	out := new(Entry)
-	err := c.cc.Invoke(ctx, "/proto.Api/DeleteComment", in, out, opts...)
+func (c *apiClient) HideAuthor(ctx context.Context, in *HideRequest, opts ...grpc.CallOption) (*Profile, error) {
+	out := new(Profile)
+	err := c.cc.Invoke(ctx, "/proto.Api/HideAuthor", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1107,6 +2883,15 @@ func (c *apiClient) BindUserFeed(ctx context.Context, in *OAuthUser, opts ...grp
 	return out, nil
 }
 
+func (c *apiClient) AddService(ctx context.Context, in *ServiceRequest, opts ...grpc.CallOption) (*Feedinfo, error) {
+	out := new(Feedinfo)
+	err := c.cc.Invoke(ctx, "/proto.Api/AddService", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *apiClient) DeleteService(ctx context.Context, in *ServiceRequest, opts ...grpc.CallOption) (*Feedinfo, error) {
 	out := new(Feedinfo)
 	err := c.cc.Invoke(ctx, "/proto.Api/DeleteService", in, out, opts...)
@@ -1125,11 +2910,69 @@ func (c *apiClient) Command(ctx context.Context, in *CommandRequest, opts ...grp
 	return out, nil
 }
 
+func (c *apiClient) FetchSupUpdates(ctx context.Context, in *SupRequest, opts ...grpc.CallOption) (*SupResponse, error) {
+	out := new(SupResponse)
+	err := c.cc.Invoke(ctx, "/proto.Api/FetchSupUpdates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiClient) Subscribe(ctx context.Context, in *Subscription, opts ...grpc.CallOption) (*Subscription, error) {
+	out := new(Subscription)
+	err := c.cc.Invoke(ctx, "/proto.Api/Subscribe", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiClient) PushNotify(ctx context.Context, in *PushRequest, opts ...grpc.CallOption) (*PushResponse, error) {
+	out := new(PushResponse)
+	err := c.cc.Invoke(ctx, "/proto.Api/PushNotify", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiClient) HubSubscribe(ctx context.Context, in *HubSubscription, opts ...grpc.CallOption) (*HubSubscription, error) {
+	out := new(HubSubscription)
+	err := c.cc.Invoke(ctx, "/proto.Api/HubSubscribe", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiClient) FetchActor(ctx context.Context, in *ActorRequest, opts ...grpc.CallOption) (*Actor, error) {
+	out := new(Actor)
+	err := c.cc.Invoke(ctx, "/proto.Api/FetchActor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiClient) PostInbox(ctx context.Context, in *InboxRequest, opts ...grpc.CallOption) (*InboxResponse, error) {
+	out := new(InboxResponse)
+	err := c.cc.Invoke(ctx, "/proto.Api/PostInbox", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ApiServer is the server API for Api service.
 type ApiServer interface {
 	EnqueJob(context.Context, *FeedJob) (*FeedJob, error)
 	GetFeedJob(context.Context, *Worker) (*FeedJob, error)
 	FinishJob(context.Context, *FeedJob) (*FeedJob, error)
+	// Checkpoint progress of running job, eg: start of friendfeed archiving.
+	UpdateJob(context.Context, *FeedJob) (*FeedJob, error)
+	// Requeue running job not finished, eg: worker shutting down or throttled.
+	ReleaseJob(context.Context, *FeedJob) (*FeedJob, error)
 	FetchProfile(context.Context, *ProfileRequest) (*Profile, error)
 	FetchGraph(context.Context, *ProfileRequest) (*Graph, error)
 	FetchFeedinfo(context.Context, *ProfileRequest) (*Feedinfo, error)
@@ -1147,12 +2990,29 @@ type ApiServer interface {
 	LikeEntry(context.Context, *LikeRequest) (*Entry, error)
 	CommentEntry(context.Context, *CommentRequest) (*Entry, error)
 	DeleteComment(context.Context, *CommentDeleteRequest) (*Entry, error)
+	EditEntry(context.Context, *EntryEditRequest) (*Entry, error)
+	DeleteEntry(context.Context, *EntryDeleteRequest) (*Entry, error)
+	HideEntry(context.Context, *HideRequest) (*Entry, error)
+	HideAuthor(context.Context, *HideRequest) (*Profile, error)
 	PutOAuth(context.Context, *OAuthUser) (*Profile, error)
 	// rpc BindAuth(OAuthUser) returns (OAuthUser) {}
 	BindUserFeed(context.Context, *OAuthUser) (*OAuthUser, error)
 	// service
+	AddService(context.Context, *ServiceRequest) (*Feedinfo, error)
 	DeleteService(context.Context, *ServiceRequest) (*Feedinfo, error)
 	Command(context.Context, *CommandRequest) (*CommandResponse, error)
+	// Simple Update Protocol, feeds updated since
+	FetchSupUpdates(context.Context, *SupRequest) (*SupResponse, error)
+	// Subscribe to imported feed, job enqueued once the feed updated.
+	Subscribe(context.Context, *Subscription) (*Subscription, error)
+	// WebSub intent verification and content distribution.
+	PushNotify(context.Context, *PushRequest) (*PushResponse, error)
+	// WebSub hub of our own feeds, intent verified asynchronously.
+	HubSubscribe(context.Context, *HubSubscription) (*HubSubscription, error)
+	// ActivityPub actor of our feed, key pair generated on first fetch.
+	FetchActor(context.Context, *ActorRequest) (*Actor, error)
+	// ActivityPub inbox of our feed, requests signed by remote actors.
+	PostInbox(context.Context, *InboxRequest) (*InboxResponse, error)
 }
 
 func RegisterApiServer(s *grpc.Server, srv ApiServer) {
@@ -1213,6 +3073,42 @@ func _Api_FinishJob_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Api_UpdateJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeedJob)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).UpdateJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Api/UpdateJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).UpdateJob(ctx, req.(*FeedJob))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Api_ReleaseJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeedJob)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).ReleaseJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Api/ReleaseJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).ReleaseJob(ctx, req.(*FeedJob))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Api_FetchProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ProfileRequest)
 	if err := dec(in); err != nil {
@@ -1445,6 +3341,78 @@ func _Api_DeleteComment_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Api_EditEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EntryEditRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).EditEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Api/EditEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).EditEntry(ctx, req.(*EntryEditRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Api_DeleteEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EntryDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).DeleteEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Api/DeleteEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).DeleteEntry(ctx, req.(*EntryDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Api_HideEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HideRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).HideEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Api/HideEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).HideEntry(ctx, req.(*HideRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Api_HideAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HideRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).HideAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Api/HideAuthor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).HideAuthor(ctx, req.(*HideRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Api_PutOAuth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(OAuthUser)
 	if err := dec(in); err != nil {
@@ -1481,6 +3449,24 @@ func _Api_BindUserFeed_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Api_AddService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).AddService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Api/AddService",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).AddService(ctx, req.(*ServiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Api_DeleteService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ServiceRequest)
 	if err := dec(in); err != nil {
@@ -1517,6 +3503,114 @@ func _Api_Command_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Api_FetchSupUpdates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).FetchSupUpdates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Api/FetchSupUpdates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).FetchSupUpdates(ctx, req.(*SupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Api_Subscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Subscription)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).Subscribe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Api/Subscribe",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).Subscribe(ctx, req.(*Subscription))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Api_PushNotify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PushRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).PushNotify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Api/PushNotify",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).PushNotify(ctx, req.(*PushRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Api_HubSubscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HubSubscription)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).HubSubscribe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Api/HubSubscribe",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).HubSubscribe(ctx, req.(*HubSubscription))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Api_FetchActor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).FetchActor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Api/FetchActor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).FetchActor(ctx, req.(*ActorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Api_PostInbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).PostInbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Api/PostInbox",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).PostInbox(ctx, req.(*InboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Api_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "proto.Api",
 	HandlerType: (*ApiServer)(nil),
@@ -1533,6 +3627,14 @@ var _Api_serviceDesc = grpc.ServiceDesc{
 			MethodName: "FinishJob",
 			Handler:    _Api_FinishJob_Handler,
 		},
+		{
+			MethodName: "UpdateJob",
+			Handler:    _Api_UpdateJob_Handler,
+		},
+		{
+			MethodName: "ReleaseJob",
+			Handler:    _Api_ReleaseJob_Handler,
+		},
 		{
 			MethodName: "FetchProfile",
 			Handler:    _Api_FetchProfile_Handler,
@@ -1573,6 +3675,22 @@ var _Api_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteComment",
 			Handler:    _Api_DeleteComment_Handler,
 		},
+		{
+			MethodName: "EditEntry",
+			Handler:    _Api_EditEntry_Handler,
+		},
+		{
+			MethodName: "DeleteEntry",
+			Handler:    _Api_DeleteEntry_Handler,
+		},
+		{
+			MethodName: "HideEntry",
+			Handler:    _Api_HideEntry_Handler,
+		},
+		{
+			MethodName: "HideAuthor",
+			Handler:    _Api_HideAuthor_Handler,
+		},
 		{
 			MethodName: "PutOAuth",
 			Handler:    _Api_PutOAuth_Handler,
@@ -1581,6 +3699,10 @@ var _Api_serviceDesc = grpc.ServiceDesc{
 			MethodName: "BindUserFeed",
 			Handler:    _Api_BindUserFeed_Handler,
 		},
+		{
+			MethodName: "AddService",
+			Handler:    _Api_AddService_Handler,
+		},
 		{
 			MethodName: "DeleteService",
 			Handler:    _Api_DeleteService_Handler,
@@ -1589,6 +3711,30 @@ var _Api_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Command",
 			Handler:    _Api_Command_Handler,
 		},
+		{
+			MethodName: "FetchSupUpdates",
+			Handler:    _Api_FetchSupUpdates_Handler,
+		},
+		{
+			MethodName: "Subscribe",
+			Handler:    _Api_Subscribe_Handler,
+		},
+		{
+			MethodName: "PushNotify",
+			Handler:    _Api_PushNotify_Handler,
+		},
+		{
+			MethodName: "HubSubscribe",
+			Handler:    _Api_HubSubscribe_Handler,
+		},
+		{
+			MethodName: "FetchActor",
+			Handler:    _Api_FetchActor_Handler,
+		},
+		{
+			MethodName: "PostInbox",
+			Handler:    _Api_PostInbox_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
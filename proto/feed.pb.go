@@ -840,17 +840,19 @@ func (m *Feedinfo) GetRemoteKey() string {
 //   * profile? - the profile URL for this service, if any
 //   * username? - the username for this service, if any
 type Service struct {
-	Id                   string     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name                 string     `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Icon                 string     `protobuf:"bytes,3,opt,name=icon,proto3" json:"icon,omitempty"`
-	Profile              string     `protobuf:"bytes,4,opt,name=profile,proto3" json:"profile,omitempty"`
-	Username             string     `protobuf:"bytes,5,opt,name=username,proto3" json:"username,omitempty"`
-	Oauth                *OAuthUser `protobuf:"bytes,6,opt,name=oauth,proto3" json:"oauth,omitempty"`
-	Created              int64      `protobuf:"varint,7,opt,name=created,proto3" json:"created,omitempty"`
-	Updated              int64      `protobuf:"varint,8,opt,name=updated,proto3" json:"updated,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+	Id       string     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name     string     `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Icon     string     `protobuf:"bytes,3,opt,name=icon,proto3" json:"icon,omitempty"`
+	Profile  string     `protobuf:"bytes,4,opt,name=profile,proto3" json:"profile,omitempty"`
+	Username string     `protobuf:"bytes,5,opt,name=username,proto3" json:"username,omitempty"`
+	Oauth    *OAuthUser `protobuf:"bytes,6,opt,name=oauth,proto3" json:"oauth,omitempty"`
+	Created  int64      `protobuf:"varint,7,opt,name=created,proto3" json:"created,omitempty"`
+	Updated  int64      `protobuf:"varint,8,opt,name=updated,proto3" json:"updated,omitempty"`
+	// feed url of rss/atom service
+	Url                  string   `protobuf:"bytes,9,opt,name=url,proto3" json:"url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Service) Reset()         { *m = Service{} }
@@ -934,6 +936,13 @@ func (m *Service) GetUpdated() int64 {
 	return 0
 }
 
+func (m *Service) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
 // Entry
 // id - The FriendFeed entry id, used to add comments/likes to the entry
 // url - URL of the entry page on the FriendFeed website.
@@ -981,7 +990,9 @@ type Entry struct {
 	Commands   []string     `protobuf:"bytes,15,rep,name=commands,proto3" json:"commands,omitempty"`
 	// custom filed
 	// TODO: duplicated with from field
-	ProfileUuid          string   `protobuf:"bytes,16,opt,name=profile_uuid,json=profileUuid,proto3" json:"profile_uuid,omitempty"`
+	ProfileUuid string `protobuf:"bytes,16,opt,name=profile_uuid,json=profileUuid,proto3" json:"profile_uuid,omitempty"`
+	// hidden by the requesting user
+	Hidden               bool     `protobuf:"varint,17,opt,name=hidden,proto3" json:"hidden,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1124,6 +1135,13 @@ func (m *Entry) GetProfileUuid() string {
 	return ""
 }
 
+func (m *Entry) GetHidden() bool {
+	if m != nil {
+		return m.Hidden
+	}
+	return false
+}
+
 // Comment
 // id - The id of the comment
 // date - The date the comment was posted.
@@ -1591,87 +1609,88 @@ func init() {
 func init() { proto.RegisterFile("feed.proto", fileDescriptor_d7a672c1337cb5ac) }
 
 var fileDescriptor_d7a672c1337cb5ac = []byte{
-	// 1302 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x57, 0xdd, 0x6e, 0xdc, 0xc4,
-	0x17, 0xaf, 0xbd, 0xf6, 0xae, 0xf7, 0x38, 0x49, 0xf7, 0x3f, 0x7f, 0xa0, 0x56, 0xda, 0xd2, 0x64,
-	0x55, 0xaa, 0xaa, 0x94, 0xa8, 0x14, 0x54, 0x21, 0x6e, 0x50, 0x81, 0x02, 0x55, 0xab, 0x52, 0xb9,
-	0x1f, 0xb7, 0x2b, 0xc7, 0x9e, 0x64, 0x47, 0xeb, 0xb5, 0xad, 0xb1, 0x9d, 0x2a, 0xdc, 0x72, 0xc3,
-	0x73, 0x70, 0xdd, 0x17, 0xe0, 0x39, 0x78, 0x08, 0x24, 0x5e, 0x81, 0x1b, 0x74, 0xce, 0xcc, 0x78,
-	0x6d, 0xc7, 0x41, 0x15, 0xed, 0x0d, 0x57, 0x99, 0x33, 0xbf, 0x73, 0xce, 0x9e, 0xcf, 0xdf, 0x38,
-	0x00, 0x47, 0x9c, 0x27, 0x07, 0x85, 0xcc, 0xab, 0x9c, 0xb9, 0xf4, 0x67, 0xfe, 0xda, 0x86, 0xe9,
-	0x8f, 0xf7, 0xeb, 0x6a, 0xf9, 0xa2, 0xe4, 0x92, 0x31, 0x70, 0xea, 0x5a, 0x24, 0x81, 0xb5, 0x67,
-	0xdd, 0x9c, 0x86, 0x74, 0x66, 0x97, 0x60, 0x52, 0x97, 0x5c, 0x2e, 0x44, 0x12, 0xd8, 0x74, 0x3d,
-	0x46, 0xf1, 0x61, 0x82, 0xca, 0x59, 0xb4, 0xe6, 0xc1, 0x48, 0x29, 0xe3, 0x99, 0x5d, 0x86, 0x69,
-	0x26, 0xe2, 0xd5, 0x82, 0x00, 0x87, 0x00, 0x0f, 0x2f, 0x9e, 0x20, 0x78, 0x15, 0x20, 0x3a, 0x89,
-	0x2a, 0x2e, 0x17, 0xb5, 0x4c, 0x03, 0x97, 0xd0, 0xa9, 0xba, 0x79, 0x21, 0x53, 0xf6, 0x1e, 0xb8,
-	0x7c, 0x1d, 0x89, 0x34, 0x18, 0x13, 0xa2, 0x04, 0xb6, 0x0f, 0x5b, 0x51, 0x1c, 0xf3, 0xb2, 0x5c,
-	0x54, 0xf9, 0x8a, 0x67, 0xc1, 0x84, 0x40, 0x5f, 0xdd, 0x3d, 0xc7, 0x2b, 0x76, 0x00, 0xff, 0x6f,
-	0xab, 0x2c, 0x4a, 0x1e, 0x4b, 0x5e, 0x05, 0x40, 0x9a, 0xff, 0x6b, 0x69, 0x3e, 0x23, 0x80, 0xed,
-	0x82, 0x57, 0xc8, 0xfc, 0x44, 0x24, 0x5c, 0x06, 0x9e, 0x8a, 0xd1, 0xc8, 0x18, 0xa3, 0xe4, 0xeb,
-	0xbc, 0xe2, 0x8b, 0x15, 0x3f, 0x0d, 0xa6, 0x2a, 0x46, 0x75, 0xf3, 0x88, 0x9f, 0xce, 0xff, 0xb2,
-	0x60, 0xf2, 0x54, 0xe6, 0x47, 0x22, 0xe5, 0x83, 0xc5, 0xda, 0x01, 0xbb, 0xa9, 0x93, 0x2d, 0x86,
-	0x6b, 0x14, 0xc0, 0xa4, 0x10, 0x71, 0x55, 0x4b, 0xae, 0xfd, 0x1b, 0x11, 0xb5, 0xab, 0xd3, 0xc2,
-	0x14, 0x8e, 0xce, 0xa4, 0x2d, 0x05, 0x16, 0x89, 0x2a, 0xe6, 0x85, 0x46, 0x64, 0xef, 0xc3, 0xb8,
-	0xac, 0x0b, 0xec, 0x8b, 0x2e, 0x58, 0x59, 0x17, 0x0f, 0x13, 0xb6, 0x07, 0x7e, 0xc2, 0xcb, 0x58,
-	0x8a, 0xa2, 0x12, 0x79, 0x53, 0xaf, 0xd6, 0x55, 0x2f, 0x47, 0xaf, 0x97, 0x23, 0xfe, 0x62, 0xc2,
-	0x53, 0x5e, 0xf1, 0x84, 0x4a, 0xe8, 0x85, 0x46, 0x9c, 0xbf, 0x76, 0xc1, 0xfd, 0x5e, 0x46, 0xc5,
-	0x92, 0x7d, 0x05, 0x7e, 0x59, 0x1f, 0xa2, 0xcb, 0x43, 0x2e, 0xcb, 0xc0, 0xda, 0x1b, 0xdd, 0xf4,
-	0xef, 0x5e, 0x55, 0xa3, 0x75, 0x40, 0x2a, 0x07, 0xcf, 0x36, 0xf8, 0x83, 0xac, 0x92, 0xa7, 0x61,
-	0xdb, 0x82, 0x3d, 0x80, 0x6d, 0x2d, 0x52, 0x4c, 0x65, 0x60, 0x93, 0x8b, 0x6b, 0x43, 0x2e, 0x94,
-	0x86, 0x72, 0xd2, 0xb5, 0x62, 0x77, 0x60, 0x1c, 0x25, 0x6b, 0x91, 0x95, 0xc1, 0x88, 0xec, 0x83,
-	0x8e, 0xfd, 0x7d, 0x82, 0x94, 0xa1, 0xd6, 0x63, 0x9f, 0x80, 0x8b, 0x5b, 0x50, 0x06, 0x0e, 0x19,
-	0x5c, 0xea, 0x18, 0x7c, 0x87, 0x88, 0xd2, 0x57, 0x5a, 0xec, 0x1e, 0x78, 0x25, 0x97, 0x27, 0x22,
-	0xe6, 0x65, 0xe0, 0x92, 0xc5, 0x6e, 0x37, 0x44, 0x0d, 0x2a, 0xa3, 0x46, 0x77, 0xf7, 0x09, 0xcc,
-	0xfa, 0x05, 0x60, 0x33, 0x18, 0x61, 0xc1, 0xd5, 0xbc, 0xe0, 0x91, 0x5d, 0x07, 0xf7, 0x24, 0x4a,
-	0x6b, 0x4e, 0x13, 0xe3, 0xdf, 0xdd, 0xd1, 0xae, 0xf5, 0x84, 0x85, 0x0a, 0xfc, 0xd2, 0xfe, 0xc2,
-	0xda, 0x7d, 0x0a, 0xec, 0x6c, 0x35, 0xde, 0xca, 0xe3, 0x43, 0xf0, 0x5b, 0xf5, 0x79, 0x2b, 0x57,
-	0x3f, 0x00, 0x6c, 0x2a, 0xf7, 0x56, 0x9e, 0x1e, 0xc1, 0x76, 0xa7, 0xa2, 0x6f, 0xee, 0x4c, 0x9b,
-	0xb5, 0x9c, 0xcd, 0x7f, 0xb3, 0x61, 0xfa, 0xf2, 0x53, 0xb3, 0xae, 0x1f, 0xc0, 0xb8, 0xac, 0xa2,
-	0xaa, 0x2e, 0xb5, 0x33, 0x2d, 0xbd, 0xd1, 0xca, 0xee, 0x02, 0xb1, 0x58, 0x9f, 0xd5, 0x08, 0xfb,
-	0x10, 0xa0, 0x50, 0x3f, 0xf1, 0xa2, 0x61, 0xb5, 0xd6, 0x0d, 0xbb, 0xdd, 0x9a, 0xa0, 0x31, 0x4d,
-	0xd0, 0x4c, 0x87, 0xdc, 0xc4, 0xb6, 0x99, 0x1b, 0x76, 0xaf, 0xbf, 0x17, 0x93, 0x73, 0x4c, 0x7a,
-	0x8b, 0x70, 0x03, 0x5c, 0x99, 0xe7, 0xeb, 0x32, 0xf0, 0xce, 0xd1, 0x57, 0x30, 0xea, 0xa5, 0xa2,
-	0xac, 0xca, 0x60, 0x7a, 0x9e, 0x1e, 0xc1, 0xf3, 0x5f, 0x6d, 0x70, 0xb0, 0xa7, 0xef, 0x82, 0xe5,
-	0xfc, 0x2e, 0xcb, 0x6d, 0x78, 0xcb, 0xf9, 0x07, 0xde, 0x72, 0xcf, 0xf2, 0x96, 0xa1, 0xc7, 0xf1,
-	0x30, 0x3d, 0x4e, 0xba, 0xf4, 0xb8, 0x0b, 0x5e, 0x9c, 0xaf, 0xd7, 0x51, 0x96, 0xa8, 0xa2, 0x4c,
-	0xc3, 0x46, 0x66, 0x37, 0x60, 0xc2, 0xb3, 0x4a, 0x0a, 0x6e, 0xea, 0xb0, 0xa5, 0xeb, 0xa0, 0xd6,
-	0xd8, 0x80, 0x3d, 0xa6, 0x84, 0xfe, 0x6b, 0xf0, 0x8b, 0x03, 0x1e, 0x16, 0x49, 0x64, 0x47, 0xf9,
-	0xbb, 0x28, 0xd4, 0xec, 0x3f, 0x5b, 0xa8, 0x3b, 0xdd, 0xf7, 0x00, 0x48, 0xb7, 0xbf, 0xe7, 0x9d,
-	0x07, 0xe0, 0xf3, 0xfe, 0xa0, 0xfb, 0x83, 0x36, 0x67, 0xc6, 0xdc, 0xf0, 0xfd, 0xd6, 0xa0, 0xba,
-	0x61, 0xf9, 0xeb, 0x86, 0xe5, 0xb7, 0x07, 0xd5, 0x34, 0xb9, 0xdf, 0x6a, 0xad, 0xe6, 0x4e, 0x47,
-	0xd1, 0xb0, 0xc9, 0x66, 0x31, 0xbb, 0xa3, 0x70, 0xb1, 0x3f, 0x0a, 0xbf, 0x5b, 0x30, 0xd1, 0x46,
-	0xba, 0xeb, 0xd6, 0x99, 0xae, 0xdb, 0xad, 0xae, 0x33, 0x70, 0x44, 0x9c, 0x67, 0x66, 0x12, 0xf0,
-	0xac, 0x5a, 0x44, 0x01, 0xea, 0x86, 0x1b, 0x11, 0x5b, 0x84, 0x1f, 0x5d, 0xe4, 0x45, 0xf5, 0xbb,
-	0x91, 0x71, 0xa3, 0xf3, 0xa8, 0xae, 0x96, 0xd4, 0xed, 0xcd, 0x46, 0x37, 0x1f, 0x75, 0xa1, 0x82,
-	0xd1, 0x7b, 0x2c, 0x79, 0x84, 0xcf, 0x3a, 0x0e, 0xc0, 0x28, 0x34, 0x22, 0x22, 0x75, 0x91, 0x10,
-	0xe2, 0x29, 0x44, 0x8b, 0xf3, 0x3f, 0x46, 0xe0, 0x2a, 0x1e, 0xee, 0xe7, 0x34, 0x83, 0x11, 0x7e,
-	0xc4, 0xa9, 0x94, 0xf0, 0x88, 0x19, 0xa1, 0x91, 0xc9, 0x08, 0xcf, 0x78, 0x77, 0x98, 0x27, 0xa7,
-	0xe6, 0x83, 0x06, 0xcf, 0xf8, 0x6b, 0x32, 0x7a, 0xf5, 0x35, 0x5e, 0xab, 0x54, 0x8c, 0xa8, 0x91,
-	0xc7, 0x22, 0x5b, 0xe9, 0xc9, 0x35, 0x22, 0xbb, 0x06, 0xce, 0x91, 0xcc, 0xd7, 0x14, 0xb8, 0x7f,
-	0xd7, 0xd7, 0x29, 0xe2, 0xea, 0x85, 0x04, 0xb0, 0xcb, 0x60, 0x57, 0xb9, 0xe6, 0xbe, 0x0e, 0x6c,
-	0x57, 0x39, 0xb6, 0x19, 0x07, 0x9a, 0x67, 0x0d, 0xed, 0x99, 0x36, 0x7f, 0xa3, 0xae, 0xc3, 0x06,
-	0x67, 0xfb, 0xc8, 0x8f, 0x2b, 0x6e, 0x46, 0xd8, 0xf8, 0x7a, 0x2c, 0x56, 0x44, 0x8d, 0x2b, 0x9a,
-	0x75, 0xa8, 0x96, 0xf5, 0xfa, 0x30, 0x8b, 0x44, 0x6a, 0xc6, 0xd6, 0x54, 0xfd, 0xb9, 0x01, 0xc2,
-	0x96, 0x0e, 0x3a, 0xc5, 0x36, 0x9a, 0xa1, 0x6d, 0x02, 0x54, 0xa3, 0x88, 0x08, 0xbb, 0x02, 0xa3,
-	0x13, 0x11, 0x05, 0xdb, 0x94, 0x20, 0x18, 0x56, 0x16, 0x51, 0x88, 0xd7, 0x6c, 0x1f, 0x46, 0xc7,
-	0x3c, 0x0f, 0x76, 0x08, 0xbd, 0x68, 0x62, 0xca, 0xe3, 0x08, 0xb7, 0x22, 0x44, 0xac, 0xb3, 0xc5,
-	0x17, 0x7b, 0x5b, 0xbc, 0x0f, 0x5b, 0x7a, 0x92, 0x16, 0x44, 0x51, 0x8a, 0x67, 0x7c, 0xf3, 0x48,
-	0xd5, 0x22, 0x99, 0xff, 0x69, 0xc1, 0x44, 0x57, 0x63, 0x68, 0x7e, 0xa9, 0xb3, 0xf6, 0x40, 0x67,
-	0x47, 0xc3, 0x9d, 0x75, 0xba, 0x9d, 0x35, 0xfd, 0x73, 0xcf, 0xeb, 0x9f, 0x4e, 0x7f, 0x3c, 0x9c,
-	0x7e, 0x3b, 0xb7, 0x49, 0x2f, 0xb7, 0x3d, 0xf0, 0x8b, 0x34, 0x8a, 0xf9, 0x32, 0x4f, 0xcd, 0xf7,
-	0xbc, 0x17, 0xb6, 0xaf, 0x70, 0x54, 0xb3, 0x7a, 0x4d, 0xdf, 0xda, 0x6e, 0x88, 0xc7, 0xf9, 0xcf,
-	0x16, 0x38, 0xd8, 0xd1, 0x26, 0x33, 0xab, 0x95, 0x99, 0x89, 0xd5, 0x3e, 0x2f, 0xd6, 0xa1, 0xd4,
-	0xff, 0x4d, 0x14, 0xaf, 0x60, 0xda, 0x8c, 0x8b, 0xd9, 0x27, 0xab, 0xb3, 0x4f, 0x29, 0xae, 0x82,
-	0xae, 0x3a, 0x9e, 0xf1, 0x5f, 0xa4, 0x57, 0x22, 0xa9, 0x96, 0xf4, 0xdb, 0x6e, 0xa8, 0x04, 0xfc,
-	0xb2, 0x59, 0x72, 0x71, 0xbc, 0xac, 0xa8, 0xec, 0x6e, 0xa8, 0x25, 0xbc, 0x2f, 0xd2, 0xe8, 0x94,
-	0x4b, 0xbd, 0x68, 0x5a, 0x9a, 0x2f, 0xc1, 0xc1, 0xd1, 0x1b, 0xfe, 0x4d, 0x7a, 0x38, 0xec, 0xd6,
-	0xc3, 0x31, 0xf4, 0x66, 0x19, 0xf6, 0x72, 0x5a, 0xec, 0xc5, 0xc0, 0x29, 0xc5, 0x4f, 0x8a, 0x9f,
-	0xdc, 0x90, 0xce, 0xf3, 0x8f, 0x61, 0xf4, 0x52, 0x44, 0x8d, 0x0b, 0xab, 0xe5, 0xe2, 0x0c, 0x81,
-	0xcc, 0xbf, 0x05, 0xcf, 0x8c, 0x34, 0x76, 0x3c, 0x8d, 0x2a, 0x51, 0xd5, 0x89, 0xb2, 0xb2, 0xc2,
-	0x46, 0x66, 0x57, 0x60, 0x9a, 0xe6, 0xd9, 0xb1, 0x02, 0x6d, 0x02, 0x37, 0x17, 0xb7, 0x6e, 0xab,
-	0x27, 0xf9, 0x39, 0x86, 0xee, 0x81, 0x83, 0x34, 0x39, 0xbb, 0xc0, 0xa6, 0xe0, 0x1e, 0xcb, 0xbc,
-	0x2e, 0x66, 0x16, 0xf3, 0x61, 0x52, 0x16, 0x3c, 0x16, 0x51, 0x3a, 0xb3, 0x6f, 0x7d, 0x04, 0xf0,
-	0x8c, 0x3e, 0x03, 0x49, 0xdf, 0x6f, 0xde, 0xc8, 0xd9, 0x05, 0x06, 0x30, 0x2e, 0xea, 0xc3, 0x54,
-	0xc4, 0x33, 0xeb, 0x70, 0x4c, 0x43, 0xf0, 0xd9, 0xdf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x25, 0x20,
-	0x93, 0x03, 0x41, 0x0f, 0x00, 0x00,
+	// 1318 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x57, 0xcf, 0x8e, 0xdc, 0x44,
+	0x13, 0x8f, 0x3d, 0xf6, 0x8c, 0xa7, 0xbc, 0xbb, 0x99, 0xf4, 0xf7, 0x7d, 0x89, 0xb5, 0x49, 0xbe,
+	0xec, 0x8e, 0x20, 0x8a, 0x42, 0x58, 0x85, 0x80, 0x22, 0xc4, 0x05, 0x05, 0x08, 0x10, 0x25, 0x0a,
+	0x91, 0xf3, 0xe7, 0x3a, 0xf2, 0xda, 0xbd, 0x3b, 0xad, 0xf1, 0xd8, 0x56, 0xdb, 0xde, 0x68, 0xb9,
+	0x72, 0xe1, 0x39, 0x38, 0xe7, 0x05, 0x78, 0x20, 0x2e, 0xbc, 0x02, 0x07, 0x50, 0x55, 0x77, 0x7b,
+	0x6c, 0xaf, 0x17, 0x45, 0x24, 0x17, 0x4e, 0xdb, 0x55, 0xbf, 0xea, 0x9a, 0xee, 0xaa, 0x5f, 0xfd,
+	0xda, 0x0b, 0x70, 0xc4, 0x79, 0x72, 0x50, 0xc8, 0xbc, 0xca, 0x99, 0x4b, 0x7f, 0xe6, 0x6f, 0x6c,
+	0x98, 0xfe, 0xf0, 0xa0, 0xae, 0x96, 0x2f, 0x4b, 0x2e, 0x19, 0x03, 0xa7, 0xae, 0x45, 0x12, 0x58,
+	0x7b, 0xd6, 0xad, 0x69, 0x48, 0x6b, 0x76, 0x05, 0x26, 0x75, 0xc9, 0xe5, 0x42, 0x24, 0x81, 0x4d,
+	0xee, 0x31, 0x9a, 0x8f, 0x12, 0x0c, 0xce, 0xa2, 0x35, 0x0f, 0x46, 0x2a, 0x18, 0xd7, 0xec, 0x2a,
+	0x4c, 0x33, 0x11, 0xaf, 0x16, 0x04, 0x38, 0x04, 0x78, 0xe8, 0x78, 0x8a, 0xe0, 0x75, 0x80, 0xe8,
+	0x24, 0xaa, 0xb8, 0x5c, 0xd4, 0x32, 0x0d, 0x5c, 0x42, 0xa7, 0xca, 0xf3, 0x52, 0xa6, 0xec, 0xbf,
+	0xe0, 0xf2, 0x75, 0x24, 0xd2, 0x60, 0x4c, 0x88, 0x32, 0xd8, 0x3e, 0x6c, 0x45, 0x71, 0xcc, 0xcb,
+	0x72, 0x51, 0xe5, 0x2b, 0x9e, 0x05, 0x13, 0x02, 0x7d, 0xe5, 0x7b, 0x81, 0x2e, 0x76, 0x00, 0xff,
+	0x69, 0x87, 0x2c, 0x4a, 0x1e, 0x4b, 0x5e, 0x05, 0x40, 0x91, 0x97, 0x5a, 0x91, 0xcf, 0x09, 0x60,
+	0xbb, 0xe0, 0x15, 0x32, 0x3f, 0x11, 0x09, 0x97, 0x81, 0xa7, 0xce, 0x68, 0x6c, 0x3c, 0xa3, 0xe4,
+	0xeb, 0xbc, 0xe2, 0x8b, 0x15, 0x3f, 0x0d, 0xa6, 0xea, 0x8c, 0xca, 0xf3, 0x98, 0x9f, 0xce, 0xff,
+	0xb0, 0x60, 0xf2, 0x4c, 0xe6, 0x47, 0x22, 0xe5, 0x83, 0xc5, 0xda, 0x01, 0xbb, 0xa9, 0x93, 0x2d,
+	0x86, 0x6b, 0x14, 0xc0, 0xa4, 0x10, 0x71, 0x55, 0x4b, 0xae, 0xf3, 0x1b, 0x13, 0xa3, 0xab, 0xd3,
+	0xc2, 0x14, 0x8e, 0xd6, 0x14, 0x2d, 0x05, 0x16, 0x89, 0x2a, 0xe6, 0x85, 0xc6, 0x64, 0xff, 0x83,
+	0x71, 0x59, 0x17, 0xd8, 0x17, 0x5d, 0xb0, 0xb2, 0x2e, 0x1e, 0x25, 0x6c, 0x0f, 0xfc, 0x84, 0x97,
+	0xb1, 0x14, 0x45, 0x25, 0xf2, 0xa6, 0x5e, 0x2d, 0x57, 0xef, 0x8e, 0x5e, 0xef, 0x8e, 0xf8, 0x8b,
+	0x09, 0x4f, 0x79, 0xc5, 0x13, 0x2a, 0xa1, 0x17, 0x1a, 0x73, 0xfe, 0xc6, 0x05, 0xf7, 0x3b, 0x19,
+	0x15, 0x4b, 0xf6, 0x25, 0xf8, 0x65, 0x7d, 0x88, 0x29, 0x0f, 0xb9, 0x2c, 0x03, 0x6b, 0x6f, 0x74,
+	0xcb, 0xbf, 0x77, 0x5d, 0x51, 0xeb, 0x80, 0x42, 0x0e, 0x9e, 0x6f, 0xf0, 0x87, 0x59, 0x25, 0x4f,
+	0xc3, 0xf6, 0x0e, 0xf6, 0x10, 0xb6, 0xb5, 0x49, 0x67, 0x2a, 0x03, 0x9b, 0x52, 0xdc, 0x18, 0x4a,
+	0xa1, 0x22, 0x54, 0x92, 0xee, 0x2e, 0x76, 0x17, 0xc6, 0x51, 0xb2, 0x16, 0x59, 0x19, 0x8c, 0x68,
+	0x7f, 0xd0, 0xd9, 0xff, 0x80, 0x20, 0xb5, 0x51, 0xc7, 0xb1, 0x8f, 0xc1, 0xc5, 0x29, 0x28, 0x03,
+	0x87, 0x36, 0x5c, 0xe9, 0x6c, 0xf8, 0x16, 0x11, 0x15, 0xaf, 0xa2, 0xd8, 0x7d, 0xf0, 0x4a, 0x2e,
+	0x4f, 0x44, 0xcc, 0xcb, 0xc0, 0xa5, 0x1d, 0xbb, 0xdd, 0x23, 0x6a, 0x50, 0x6d, 0x6a, 0x62, 0x77,
+	0x9f, 0xc2, 0xac, 0x5f, 0x00, 0x36, 0x83, 0x11, 0x16, 0x5c, 0xf1, 0x05, 0x97, 0xec, 0x03, 0x70,
+	0x4f, 0xa2, 0xb4, 0xe6, 0xc4, 0x18, 0xff, 0xde, 0x8e, 0x4e, 0xad, 0x19, 0x16, 0x2a, 0xf0, 0x0b,
+	0xfb, 0x73, 0x6b, 0xf7, 0x19, 0xb0, 0xb3, 0xd5, 0x78, 0xa7, 0x8c, 0x8f, 0xc0, 0x6f, 0xd5, 0xe7,
+	0x9d, 0x52, 0x7d, 0x0f, 0xb0, 0xa9, 0xdc, 0x3b, 0x65, 0x7a, 0x0c, 0xdb, 0x9d, 0x8a, 0xbe, 0x7d,
+	0x32, 0xbd, 0xad, 0x95, 0x6c, 0xfe, 0xab, 0x0d, 0xd3, 0x57, 0x9f, 0x98, 0x71, 0xbd, 0x0c, 0xe3,
+	0xb2, 0x8a, 0xaa, 0xba, 0xd4, 0xc9, 0xb4, 0xf5, 0x56, 0x23, 0xbb, 0x0b, 0xa4, 0x62, 0x7d, 0x55,
+	0x23, 0xec, 0xff, 0x00, 0x85, 0xfa, 0x89, 0x97, 0x8d, 0xaa, 0xb5, 0x3c, 0xec, 0x4e, 0x8b, 0x41,
+	0x63, 0x62, 0xd0, 0x4c, 0x1f, 0xb9, 0x39, 0xdb, 0x86, 0x37, 0xec, 0x7e, 0x7f, 0x2e, 0x26, 0xe7,
+	0x6c, 0xe9, 0x0d, 0xc2, 0x4d, 0x70, 0x65, 0x9e, 0xaf, 0xcb, 0xc0, 0x3b, 0x27, 0x5e, 0xc1, 0x18,
+	0x97, 0x8a, 0xb2, 0x2a, 0x83, 0xe9, 0x79, 0x71, 0x04, 0xcf, 0x7f, 0xb1, 0xc1, 0xc1, 0x9e, 0xbe,
+	0x0f, 0x95, 0xf3, 0xbb, 0x2a, 0xb7, 0xd1, 0x2d, 0xe7, 0x6f, 0x74, 0xcb, 0x3d, 0xab, 0x5b, 0x46,
+	0x1e, 0xc7, 0xc3, 0xf2, 0x38, 0xe9, 0xca, 0xe3, 0x2e, 0x78, 0x71, 0xbe, 0x5e, 0x47, 0x59, 0xa2,
+	0x8a, 0x32, 0x0d, 0x1b, 0x9b, 0xdd, 0x84, 0x09, 0xcf, 0x2a, 0x29, 0xb8, 0xa9, 0xc3, 0x96, 0xae,
+	0x83, 0x1a, 0x63, 0x03, 0xf6, 0x94, 0x12, 0xfa, 0xaf, 0xc1, 0xcf, 0x0e, 0x78, 0x58, 0x24, 0x91,
+	0x1d, 0xe5, 0xef, 0xa3, 0x50, 0xb3, 0x7f, 0x6d, 0xa1, 0xee, 0x76, 0xdf, 0x03, 0xd8, 0x1b, 0xb5,
+	0x46, 0xd3, 0x50, 0xab, 0x1d, 0xc2, 0x3e, 0xeb, 0x13, 0xdd, 0x1f, 0xdc, 0x73, 0x86, 0xe6, 0x46,
+	0xef, 0xb7, 0x06, 0xc3, 0x35, 0x8a, 0x22, 0xa1, 0x54, 0x7e, 0x7b, 0x30, 0x4c, 0x81, 0xec, 0x76,
+	0x6b, 0x34, 0x77, 0xf6, 0x46, 0x03, 0x6a, 0xb2, 0x19, 0xcc, 0x2e, 0x15, 0x2e, 0xf6, 0xa9, 0xf0,
+	0x9b, 0x05, 0x13, 0xbd, 0x49, 0x77, 0xdd, 0x3a, 0xd3, 0x75, 0xbb, 0xd5, 0x75, 0x06, 0x8e, 0x88,
+	0xf3, 0xcc, 0x30, 0x01, 0xd7, 0xaa, 0x45, 0x74, 0x40, 0xdd, 0x70, 0x63, 0x62, 0x8b, 0xf0, 0xa3,
+	0x8b, 0xb2, 0xa8, 0x7e, 0x37, 0x36, 0x4e, 0x74, 0x1e, 0xd5, 0xd5, 0x92, 0xba, 0xbd, 0x99, 0xe8,
+	0xe6, 0xa3, 0x2e, 0x54, 0x30, 0x66, 0x8f, 0x25, 0x8f, 0xf0, 0x59, 0x47, 0x02, 0x8c, 0x42, 0x63,
+	0x22, 0x52, 0x17, 0x09, 0x21, 0x9e, 0x42, 0xb4, 0x89, 0xea, 0x8b, 0x9f, 0x6a, 0xea, 0x33, 0x05,
+	0x97, 0xf3, 0x3f, 0x47, 0xe0, 0x2a, 0x65, 0xee, 0xdf, 0x52, 0xc7, 0xda, 0x4d, 0x2c, 0xde, 0x11,
+	0xd3, 0x98, 0x3b, 0xe2, 0x1a, 0x7d, 0x87, 0x79, 0x72, 0x6a, 0x3e, 0x71, 0x70, 0x8d, 0xbf, 0x2f,
+	0xa3, 0xd7, 0x5f, 0xa1, 0x5b, 0x5d, 0xce, 0x98, 0x1a, 0x79, 0x22, 0xb2, 0x95, 0xe6, 0xb2, 0x31,
+	0xd9, 0x0d, 0x70, 0x8e, 0x64, 0xbe, 0xa6, 0xab, 0xf8, 0xf7, 0x7c, 0x7d, 0x69, 0x1c, 0xc6, 0x90,
+	0x00, 0x76, 0x15, 0xec, 0x2a, 0xd7, 0x6a, 0xd8, 0x81, 0xed, 0x2a, 0xc7, 0xc6, 0x23, 0xc5, 0x79,
+	0xd6, 0x08, 0xa1, 0x69, 0xfc, 0xd7, 0xca, 0x1d, 0x36, 0x38, 0xdb, 0x47, 0xc5, 0x5c, 0x71, 0x43,
+	0x6a, 0x93, 0xeb, 0x89, 0x58, 0x91, 0x58, 0xae, 0x88, 0xfd, 0x50, 0x2d, 0xeb, 0xf5, 0x61, 0x16,
+	0x89, 0xd4, 0x10, 0xd9, 0xf4, 0xe1, 0x85, 0x01, 0xc2, 0x56, 0x0c, 0x26, 0xc5, 0xc6, 0x1a, 0x1a,
+	0x37, 0x07, 0x54, 0xe4, 0x44, 0x84, 0x5d, 0x83, 0xd1, 0x89, 0x88, 0x82, 0x6d, 0xba, 0x20, 0x18,
+	0x9d, 0x16, 0x51, 0x88, 0x6e, 0xb6, 0x0f, 0xa3, 0x63, 0x9e, 0x07, 0x3b, 0x84, 0x5e, 0x34, 0x67,
+	0xca, 0xe3, 0x08, 0xe7, 0x24, 0x44, 0xac, 0x33, 0xd7, 0x17, 0x7b, 0x73, 0xbd, 0x0f, 0x5b, 0x9a,
+	0x5b, 0x0b, 0x12, 0x2d, 0xa5, 0x3c, 0xbe, 0x79, 0xb6, 0x50, 0xbb, 0x2e, 0xc3, 0x78, 0x29, 0x92,
+	0x84, 0x67, 0xc1, 0x25, 0xd2, 0x0b, 0x6d, 0xcd, 0x7f, 0xb7, 0x60, 0xa2, 0xab, 0x34, 0xc4, 0x74,
+	0xea, 0xb8, 0x3d, 0xd0, 0xf1, 0xd1, 0x70, 0xc7, 0x9d, 0x6e, 0xc7, 0x4d, 0x5f, 0xdd, 0xf3, 0xfa,
+	0xaa, 0xcb, 0x32, 0x1e, 0x2e, 0x4b, 0xfb, 0xce, 0x93, 0xde, 0x9d, 0xf7, 0xc0, 0x2f, 0xd2, 0x28,
+	0xe6, 0xcb, 0x3c, 0x35, 0x5f, 0xfe, 0x5e, 0xd8, 0x76, 0x21, 0x85, 0xb3, 0x7a, 0x4d, 0x74, 0x77,
+	0x43, 0x5c, 0xce, 0x7f, 0xb2, 0xc0, 0xc1, 0x4e, 0x37, 0x37, 0xb3, 0x5a, 0x37, 0x33, 0x67, 0xb5,
+	0xcf, 0x3b, 0xeb, 0xd0, 0xd5, 0xff, 0xc9, 0x29, 0x5e, 0xc3, 0xb4, 0xa1, 0x91, 0x99, 0x33, 0xab,
+	0x33, 0x67, 0x29, 0x8e, 0x88, 0xae, 0x3a, 0xae, 0xf1, 0x9f, 0xa9, 0xd7, 0x22, 0xa9, 0x96, 0xf4,
+	0xdb, 0x6e, 0xa8, 0x0c, 0xea, 0x29, 0x17, 0xc7, 0xcb, 0x8a, 0xca, 0xee, 0x86, 0xda, 0x42, 0x7f,
+	0x91, 0x46, 0xa7, 0x5c, 0xea, 0x01, 0xd4, 0xd6, 0x7c, 0x09, 0x0e, 0x52, 0x72, 0xf8, 0x37, 0xe9,
+	0x89, 0xb1, 0x5b, 0x4f, 0xcc, 0xd0, 0xeb, 0x66, 0x74, 0xce, 0x69, 0xe9, 0x1c, 0x03, 0xa7, 0x14,
+	0x3f, 0x2a, 0x25, 0x73, 0x43, 0x5a, 0xcf, 0x3f, 0x82, 0xd1, 0x2b, 0x11, 0x35, 0x29, 0xac, 0x56,
+	0x8a, 0x33, 0xc2, 0x32, 0xff, 0x06, 0x3c, 0x43, 0x75, 0xec, 0x78, 0x1a, 0x55, 0xa2, 0xaa, 0x13,
+	0xb5, 0xcb, 0x0a, 0x1b, 0x9b, 0x5d, 0x83, 0x69, 0x9a, 0x67, 0xc7, 0x0a, 0xb4, 0x09, 0xdc, 0x38,
+	0x6e, 0xdf, 0x51, 0x8f, 0xf7, 0x0b, 0x3c, 0xba, 0x07, 0x0e, 0x0a, 0xea, 0xec, 0x02, 0x9b, 0x82,
+	0x7b, 0x2c, 0xf3, 0xba, 0x98, 0x59, 0xcc, 0x87, 0x49, 0x59, 0xf0, 0x58, 0x44, 0xe9, 0xcc, 0xbe,
+	0xfd, 0x21, 0xc0, 0x73, 0xfa, 0x60, 0xa4, 0x78, 0xbf, 0x79, 0x4d, 0x67, 0x17, 0x18, 0xc0, 0xb8,
+	0xa8, 0x0f, 0x53, 0x11, 0xcf, 0xac, 0xc3, 0x31, 0x91, 0xe0, 0xd3, 0xbf, 0x06, 0x00, 0xec, 0x93,
+	0x16, 0x37, 0x6b, 0x0f, 0x00, 0x00,
 }
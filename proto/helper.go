@@ -34,6 +34,11 @@ func (e *Entry) RebuildCommand(profile *Profile, graph *Graph) {
 			commands = append(commands, "like")
 		}
 	}
+	if e.Hidden {
+		commands = append(commands, "unhide")
+	} else {
+		commands = append(commands, "hide")
+	}
 	e.Commands = commands
 	return
 }
@@ -50,29 +55,90 @@ func (e *Entry) RebuildCommentsCommand(profile *Profile, graph *Graph) {
 	}
 }
 
+// Collapsing limits for maxcomments and maxlikes, other positive value N
+// returns at most N comments or likes.
+const (
+	// same number as displayed by default on friendfeed.com
+	MaxAuto int32 = 0
+	// do not collapse
+	MaxAll int32 = 1
+	// do not include any comments or likes
+	MaxNone int32 = -1
+)
+
+// FormatComments collapses comments by max, see CollapseComments.
 func (e *Entry) FormatComments(max int32) {
-	// collapse comments
-	length := len(e.Comments)
-	if max == 0 && length > 4 {
-		collapsing := &Comment{
-			Body:        fmt.Sprintf("%d more comments", length-2),
-			Num:         int32(length - 2),
-			Placeholder: true,
+	switch {
+	case max == MaxAll:
+		return
+	case max < 0:
+		e.Comments = nil
+		return
+	case max == MaxAuto:
+		if len(e.Comments) <= 3 {
+			return
 		}
-		e.Comments = []*Comment{e.Comments[0], collapsing, e.Comments[length-1]}
+		max = 2
 	}
+	e.CollapseComments(int(max))
 }
 
+// CollapseComments keeps the first comment and the latest ones, keep in
+// total, a placeholder in between carries the number of excluded comments.
+func (e *Entry) CollapseComments(keep int) {
+	length := len(e.Comments)
+	if keep <= 0 || length <= keep {
+		return
+	}
+
+	num := length - keep
+	body := fmt.Sprintf("%d more comments", num)
+	if num == 1 {
+		body = "1 more comment"
+	}
+	collapsing := &Comment{
+		Body:        body,
+		Num:         int32(num),
+		Placeholder: true,
+	}
+	comments := []*Comment{e.Comments[0], collapsing}
+	e.Comments = append(comments, e.Comments[length-keep+1:]...)
+}
+
+// FormatLikes collapses likes by max, see CollapseLikes.
 func (e *Entry) FormatLikes(max int32) {
-	// collapse likes
-	length := len(e.Likes)
-	if max == 0 && length > 4 {
-		collapsing := &Like{
-			Body:        fmt.Sprintf("%d other people", length-3),
-			Num:         int32(length - 2),
-			Placeholder: true,
+	switch {
+	case max == MaxAll:
+		return
+	case max < 0:
+		e.Likes = nil
+		return
+	case max == MaxAuto:
+		if len(e.Likes) <= 4 {
+			return
 		}
-		e.Likes = e.Likes[:3]
-		e.Likes = append(e.Likes, collapsing)
+		max = 3
+	}
+	e.CollapseLikes(int(max))
+}
+
+// CollapseLikes keeps the first keep likes followed by a placeholder carries
+// the number of excluded likes.
+func (e *Entry) CollapseLikes(keep int) {
+	length := len(e.Likes)
+	if keep <= 0 || length <= keep {
+		return
+	}
+
+	num := length - keep
+	body := fmt.Sprintf("%d other people", num)
+	if num == 1 {
+		body = "1 other person"
+	}
+	collapsing := &Like{
+		Body:        body,
+		Num:         int32(num),
+		Placeholder: true,
 	}
+	e.Likes = append(e.Likes[:keep:keep], collapsing)
 }
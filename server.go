@@ -52,6 +52,12 @@ func main() {
 
 	go apiServer.RefetchJobTicker()
 	go apiServer.IndexJobTicker()
+	go apiServer.SupJobTicker()
+	go apiServer.PushJobTicker()
+	go apiServer.HubJobTicker()
+	go apiServer.ActivityJobTicker()
+	go apiServer.MediaJobTicker()
+	go apiServer.MediaMirrorTicker()
 	go waitShutdown(rpcServer, apiServer)
 
 	pb.RegisterApiServer(rpcServer, apiServer)
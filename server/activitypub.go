@@ -0,0 +1,464 @@
+package server
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	uuid "github.com/satori/go.uuid"
+	"github.com/yinhm/friendfeed/activitypub"
+	pb "github.com/yinhm/friendfeed/proto"
+	store "github.com/yinhm/friendfeed/storage"
+	"golang.org/x/net/context"
+)
+
+var apClient = &http.Client{Timeout: hubTimeout}
+
+// FetchActor returns actor of feed, key pair generated on first fetch.
+func (s *ApiServer) FetchActor(ctx context.Context, req *pb.ActorRequest) (*pb.Actor, error) {
+	profile, err := s.federatedProfile(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	key, err := s.actorKey(profile.Id)
+	if err != nil {
+		return nil, err
+	}
+	followers, err := store.GetFollowers(s.mdb, profile.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Actor{
+		Profile:   profile,
+		PublicKey: key.PublicKey,
+		Followers: int32(len(followers)),
+	}, nil
+}
+
+// federatedProfile returns profile of feed, private feeds not federated.
+func (s *ApiServer) federatedProfile(feedId string) (*pb.Profile, error) {
+	profile, err := store.GetProfile(s.mdb, feedId)
+	if err != nil || profile == nil || profile.Id == "" {
+		return nil, fmt.Errorf("404")
+	}
+	if profile.Private {
+		return nil, fmt.Errorf("403: private feed")
+	}
+	return profile, nil
+}
+
+// actorKey returns key pair of feed, generated if not exists.
+func (s *ApiServer) actorKey(feedId string) (*pb.ActorKey, error) {
+	key, err := store.GetActorKey(s.mdb, feedId)
+	if err != nil {
+		return nil, err
+	}
+	if key.PrivateKey != "" {
+		return key, nil
+	}
+
+	s.keyMu.Lock()
+	defer s.keyMu.Unlock()
+	// generated by others while waiting
+	key, err = store.GetActorKey(s.mdb, feedId)
+	if err != nil {
+		return nil, err
+	}
+	if key.PrivateKey != "" {
+		return key, nil
+	}
+	private, public, err := activitypub.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	key = &pb.ActorKey{
+		FeedId:     feedId,
+		PrivateKey: private,
+		PublicKey:  public,
+		Created:    time.Now().Unix(),
+	}
+	if err := store.PutActorKey(s.mdb, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (s *ApiServer) privateKey(feedId string) (*rsa.PrivateKey, error) {
+	key, err := s.actorKey(feedId)
+	if err != nil {
+		return nil, err
+	}
+	return activitypub.ParsePrivateKey(key.PrivateKey)
+}
+
+// PostInbox accepts activities of remote actors signed by HTTP signatures:
+// Follow and Undo of it, Like and replies to our entries.
+func (s *ApiServer) PostInbox(ctx context.Context, req *pb.InboxRequest) (*pb.InboxResponse, error) {
+	profile, err := s.federatedProfile(req.FeedId)
+	if err != nil {
+		return nil, err
+	}
+
+	activity := new(activitypub.Activity)
+	if err := json.Unmarshal(req.Body, activity); err != nil || activity.Actor == "" {
+		return nil, fmt.Errorf("bad request: bad activity")
+	}
+	resp := &pb.InboxResponse{Type: activity.Type}
+	if activity.Type == "Delete" {
+		// deleted accounts can not be verified as their actors are gone
+		return resp, nil
+	}
+
+	remote, err := s.verifyInbox(ctx, req, activity)
+	if err != nil {
+		return nil, err
+	}
+
+	base := activitypub.BaseURL(req.Actor, profile.Id)
+	switch activity.Type {
+	case "Follow":
+		if activity.ObjectId() != req.Actor {
+			return nil, fmt.Errorf("bad request: follow object mismatch")
+		}
+		err = s.acceptFollow(profile, req.Actor, remote, activity)
+	case "Undo":
+		undone := new(activitypub.Activity)
+		if err := activity.Embedded(undone); err != nil {
+			return nil, fmt.Errorf("bad request: %s", err)
+		}
+		if undone.Actor != remote.Id {
+			return nil, fmt.Errorf("403: actor mismatch")
+		}
+		switch undone.Type {
+		case "Follow":
+			err = store.DeleteFollower(s.mdb, profile.Id, remote.Id)
+		case "Like":
+			err = s.remoteLike(profile, base, remote, undone.ObjectId(), false)
+		}
+	case "Like":
+		err = s.remoteLike(profile, base, remote, activity.ObjectId(), true)
+	case "Create":
+		note := new(activitypub.Note)
+		if err := activity.Embedded(note); err != nil {
+			return nil, fmt.Errorf("bad request: %s", err)
+		}
+		if note.Type == "Note" {
+			err = s.remoteComment(profile, base, remote, note)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// verifyInbox verifies signature of request by the public key of actor,
+// returns actor of activity.
+func (s *ApiServer) verifyInbox(ctx context.Context, req *pb.InboxRequest, activity *activitypub.Activity) (*activitypub.Actor, error) {
+	target, err := url.ParseRequestURI(req.Path)
+	if err != nil {
+		return nil, fmt.Errorf("bad request: bad path")
+	}
+	hreq := &http.Request{
+		Method: req.Method,
+		URL:    target,
+		Host:   req.Host,
+		Header: make(http.Header),
+	}
+	for k, v := range req.Headers {
+		hreq.Header.Set(k, v)
+	}
+
+	key, err := s.privateKey(req.FeedId)
+	if err != nil {
+		return nil, err
+	}
+	var remote *activitypub.Actor
+	lookup := func(keyId string) (*rsa.PublicKey, error) {
+		actorId := keyId
+		if i := strings.Index(actorId, "#"); i >= 0 {
+			actorId = actorId[:i]
+		}
+		actor, err := activitypub.FetchActor(ctx, apClient, actorId, activitypub.KeyId(req.Actor), key)
+		if err != nil {
+			return nil, err
+		}
+		if actor.PublicKey == nil || actor.PublicKey.Id != keyId {
+			return nil, fmt.Errorf("activitypub: key %s not found", keyId)
+		}
+		remote = actor
+		return activitypub.ParsePublicKey(actor.PublicKey.PublicKeyPem)
+	}
+	if _, err := activitypub.Verify(hreq, req.Body, lookup); err != nil {
+		return nil, fmt.Errorf("401: %s", err)
+	}
+	if remote.Id != activity.Actor {
+		return nil, fmt.Errorf("403: actor mismatch")
+	}
+	return remote, nil
+}
+
+// acceptFollow saves follower, Accept delivered to its inbox.
+func (s *ApiServer) acceptFollow(profile *pb.Profile, actor string, remote *activitypub.Actor, follow *activitypub.Activity) error {
+	f := &pb.Follower{
+		FeedId:  profile.Id,
+		Actor:   remote.Id,
+		Inbox:   remote.Inbox,
+		Object:  actor,
+		Created: time.Now().Unix(),
+	}
+	if remote.Endpoints != nil {
+		f.SharedInbox = remote.Endpoints.SharedInbox
+	}
+	if err := store.PutFollower(s.mdb, f); err != nil {
+		return err
+	}
+
+	id := actor + "#accepts/" + uuid.NewV5(uuid.NamespaceURL, follow.Id).String()
+	accept, err := activitypub.NewActivity(id, "Accept", actor, follow)
+	if err != nil {
+		return err
+	}
+	return s.enqueActivity(profile.Id, actor, remote.Inbox, accept)
+}
+
+// remoteProfile returns transient profile of remote actor, as author of
+// likes and comments.
+func remoteProfile(actor *activitypub.Actor) *pb.Profile {
+	name := actor.Name
+	if name == "" {
+		name = actor.PreferredUsername
+	}
+	if u, err := url.Parse(actor.Id); err == nil && actor.PreferredUsername != "" {
+		name = fmt.Sprintf("%s (@%s@%s)", name, actor.PreferredUsername, u.Host)
+	}
+	return &pb.Profile{Id: actor.Id, Name: name, Type: "remote"}
+}
+
+// federatedEntry returns entry of note id, only entries of feed accepted.
+func (s *ApiServer) federatedEntry(profile *pb.Profile, base, noteId string) (*pb.Entry, error) {
+	entryId, ok := activitypub.EntryId(base, noteId)
+	if !ok {
+		return nil, fmt.Errorf("404")
+	}
+	entry, err := store.GetEntry(s.rdb, entryId)
+	if err != nil || entry.Id == "" || entry.ProfileUuid != profile.Uuid {
+		return nil, fmt.Errorf("404")
+	}
+	return entry, nil
+}
+
+func (s *ApiServer) remoteLike(profile *pb.Profile, base string, remote *activitypub.Actor, noteId string, like bool) error {
+	entry, err := s.federatedEntry(profile, base, noteId)
+	if err != nil {
+		return err
+	}
+	if !like {
+		_, err = store.DeleteLike(s.rdb, remoteProfile(remote), entry)
+		return err
+	}
+	key, entry, err := store.Like(s.rdb, remoteProfile(remote), entry)
+	if err != nil {
+		return err
+	}
+	s.spread(key, entry)
+	return nil
+}
+
+// remoteComment saves reply to our entry as comment, comment id is uuid v5
+// of note id so that redelivery updates the comment.
+func (s *ApiServer) remoteComment(profile *pb.Profile, base string, remote *activitypub.Actor, note *activitypub.Note) error {
+	if note.AttributedTo != "" && note.AttributedTo != remote.Id {
+		return fmt.Errorf("403: actor mismatch")
+	}
+	entry, err := s.federatedEntry(profile, base, note.InReplyToId())
+	if err != nil {
+		return err
+	}
+
+	author := remoteProfile(remote)
+	text := activitypub.PlainText(note.Content)
+	date := note.Published
+	if _, err := time.Parse(time.RFC3339, date); err != nil {
+		date = time.Now().UTC().Format(time.RFC3339)
+	}
+	comment := &pb.Comment{
+		Id:      uuid.NewV5(uuid.NamespaceURL, note.Id).String(),
+		Date:    date,
+		Body:    html.EscapeString(text),
+		RawBody: text,
+		From: &pb.Feed{
+			Id:   author.Id,
+			Name: author.Name,
+			Type: author.Type,
+		},
+	}
+	comment.Via = &pb.Via{Name: "ActivityPub", Url: note.Url}
+	if comment.Via.Url == "" {
+		comment.Via.Url = note.Id
+	}
+	key, entry, err := store.Comment(s.rdb, author, entry, comment)
+	if err != nil {
+		return err
+	}
+	s.spread(key, entry)
+	return nil
+}
+
+// federate delivers Create, Update or Delete of entry to followers of the
+// author, one delivery per inbox. Entries of private feeds not federated.
+func (s *ApiServer) federate(typ string, entry *pb.Entry) {
+	if entry.From == nil || entry.From.Id == "" || !activitypub.IsPublic(entry) {
+		return
+	}
+	feedId := entry.From.Id
+	profile, err := store.GetProfile(s.mdb, feedId)
+	if err != nil || profile == nil || profile.Private {
+		return
+	}
+	followers, err := store.GetFollowers(s.mdb, feedId)
+	if err != nil {
+		log.Println("activitypub: get followers failed:", err)
+		return
+	}
+
+	inboxes := make(map[string]bool)
+	for _, f := range followers {
+		inbox := f.SharedInbox
+		if inbox == "" {
+			inbox = f.Inbox
+		}
+		if inboxes[inbox] {
+			continue
+		}
+		inboxes[inbox] = true
+
+		base := activitypub.BaseURL(f.Object, feedId)
+		activity, err := activitypub.EntryActivity(typ, entry, base)
+		if err != nil {
+			log.Println("activitypub: build activity failed:", err)
+			return
+		}
+		if err := s.enqueActivity(feedId, f.Object, inbox, activity); err != nil {
+			log.Println("activitypub: enque delivery failed:", err)
+		}
+	}
+}
+
+func (s *ApiServer) enqueActivity(feedId, actor, inbox string, activity *activitypub.Activity) error {
+	body, err := json.Marshal(activity)
+	if err != nil {
+		return err
+	}
+	now := time.Now().Unix()
+	d := &pb.ActivityDelivery{
+		FeedId:      feedId,
+		KeyId:       activitypub.KeyId(actor),
+		Inbox:       inbox,
+		Activity:    body,
+		NextAttempt: now,
+		Created:     now,
+	}
+	if err := store.PutActivityDelivery(s.mdb, d); err != nil {
+		return err
+	}
+	select {
+	case s.apCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// DeliverActivities posts pending activities due to inboxes, failed
+// deliveries are retried later.
+func (s *ApiServer) DeliverActivities() (int, error) {
+	now := time.Now()
+	var due []*pb.ActivityDelivery
+	_, err := store.ForwardTableScan(s.mdb, store.TableActivityDelivery, func(i int, k, v []byte) error {
+		d := new(pb.ActivityDelivery)
+		if err := proto.Unmarshal(v, d); err != nil {
+			return err
+		}
+		if d.NextAttempt <= now.Unix() {
+			due = append(due, d)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, d := range due {
+		err := s.deliverActivity(d)
+		if err == nil {
+			n++
+			if err := store.DeleteActivityDelivery(s.mdb, d.Key); err != nil {
+				return n, err
+			}
+			continue
+		}
+
+		d.Attempts++
+		d.Error = err.Error()
+		if err == activitypub.ErrGone || d.Attempts >= hubMaxAttempts {
+			log.Printf("activitypub: give up delivery to %s: %s", d.Inbox, err)
+			if err == activitypub.ErrGone {
+				s.removeInbox(d.FeedId, d.Inbox)
+			}
+			if err := store.DeleteActivityDelivery(s.mdb, d.Key); err != nil {
+				return n, err
+			}
+			continue
+		}
+		d.NextAttempt = now.Add(hubBackoff(d.Attempts)).Unix()
+		if err := store.PutActivityDelivery(s.mdb, d); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+func (s *ApiServer) deliverActivity(d *pb.ActivityDelivery) error {
+	key, err := s.privateKey(d.FeedId)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), hubTimeout)
+	defer cancel()
+	return activitypub.Deliver(ctx, apClient, d.Inbox, d.KeyId, key, d.Activity)
+}
+
+// removeInbox removes followers of feed delivered to inbox gone.
+func (s *ApiServer) removeInbox(feedId, inbox string) {
+	followers, err := store.GetFollowers(s.mdb, feedId)
+	if err != nil {
+		return
+	}
+	for _, f := range followers {
+		if f.Inbox == inbox || f.SharedInbox == inbox {
+			store.DeleteFollower(s.mdb, feedId, f.Actor)
+		}
+	}
+}
+
+// ActivityJobTicker delivers activities once queued, retries periodically.
+func (s *ApiServer) ActivityJobTicker() {
+	t := time.Tick(hubRetryDelay)
+	for {
+		select {
+		case <-t:
+		case <-s.apCh:
+		}
+		if _, err := s.DeliverActivities(); err != nil {
+			log.Println("activitypub: deliver failed:", err)
+		}
+	}
+}
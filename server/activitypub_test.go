@@ -0,0 +1,231 @@
+package server
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/yinhm/friendfeed/activitypub"
+	pb "github.com/yinhm/friendfeed/proto"
+	store "github.com/yinhm/friendfeed/storage"
+	"golang.org/x/net/context"
+)
+
+func TestActivityPub(t *testing.T) {
+	setup()
+	defer teardown()
+
+	Convey("Given remote follower, accept activities and deliver entries", t, func() {
+		ctx := context.Background()
+
+		user := &pb.Profile{
+			Uuid: "d6f8dca854f011ddb489003048343a41",
+			Id:   "heming",
+			Name: "Heming",
+			Type: "user",
+		}
+		So(store.UpdateProfile(srv.mdb, user), ShouldBeNil)
+		base := "http://ff.example.com"
+		actor := activitypub.ActorURL(base, user.Id)
+
+		ours, err := srv.FetchActor(ctx, &pb.ActorRequest{Id: user.Id})
+		So(err, ShouldBeNil)
+		So(ours.PublicKey, ShouldStartWith, "-----BEGIN PUBLIC KEY-----")
+		ourKey, err := activitypub.ParsePublicKey(ours.PublicKey)
+		So(err, ShouldBeNil)
+		_, err = srv.FetchActor(ctx, &pb.ActorRequest{Id: "nobody"})
+		So(err, ShouldNotBeNil)
+
+		// keys existing read without server lock
+		srv.RLock()
+		key, err := srv.actorKey(user.Id)
+		srv.RUnlock()
+		So(err, ShouldBeNil)
+		So(key.PublicKey, ShouldEqual, ours.PublicKey)
+
+		// stand-in remote server, activities delivered signed by our key
+		private, public, err := activitypub.GenerateKey()
+		So(err, ShouldBeNil)
+		remoteKey, _ := activitypub.ParsePrivateKey(private)
+		var received []*activitypub.Activity
+		remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			host := "http://" + r.Host
+			switch {
+			case r.Method == "GET" && r.URL.Path == "/users/bob":
+				w.Header().Set("Content-Type", activitypub.ContentType)
+				json.NewEncoder(w).Encode(&activitypub.Actor{
+					Id:                host + "/users/bob",
+					Type:              "Person",
+					PreferredUsername: "bob",
+					Name:              "Bob",
+					Inbox:             host + "/users/bob/inbox",
+					Endpoints:         &activitypub.Endpoints{SharedInbox: host + "/inbox"},
+					PublicKey: &activitypub.PublicKey{
+						Id:           host + "/users/bob#main-key",
+						Owner:        host + "/users/bob",
+						PublicKeyPem: public,
+					},
+				})
+			case r.Method == "POST":
+				body, _ := ioutil.ReadAll(r.Body)
+				lookup := func(keyId string) (*rsa.PublicKey, error) {
+					if keyId != activitypub.KeyId(actor) {
+						return nil, fmt.Errorf("unknown key")
+					}
+					return ourKey, nil
+				}
+				if _, err := activitypub.Verify(r, body, lookup); err != nil {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				activity := new(activitypub.Activity)
+				json.Unmarshal(body, activity)
+				received = append(received, activity)
+				w.WriteHeader(http.StatusAccepted)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer remote.Close()
+		bob := remote.URL + "/users/bob"
+
+		inbox := func(activity interface{}) *pb.InboxRequest {
+			body, _ := json.Marshal(activity)
+			req, _ := http.NewRequest("POST", actor+"/inbox", bytes.NewReader(body))
+			So(activitypub.Sign(req, body, bob+"#main-key", remoteKey), ShouldBeNil)
+			headers := make(map[string]string)
+			for k := range req.Header {
+				headers[k] = req.Header.Get(k)
+			}
+			return &pb.InboxRequest{
+				FeedId:  user.Id,
+				Actor:   actor,
+				Method:  "POST",
+				Path:    req.URL.RequestURI(),
+				Host:    req.URL.Host,
+				Headers: headers,
+				Body:    body,
+			}
+		}
+
+		follow := map[string]interface{}{
+			"id": bob + "#follows/1", "type": "Follow", "actor": bob, "object": actor,
+		}
+		resp, err := srv.PostInbox(ctx, inbox(follow))
+		So(err, ShouldBeNil)
+		So(resp.Type, ShouldEqual, "Follow")
+		followers, err := store.GetFollowers(srv.mdb, user.Id)
+		So(err, ShouldBeNil)
+		So(len(followers), ShouldEqual, 1)
+		So(followers[0].SharedInbox, ShouldEqual, remote.URL+"/inbox")
+
+		n, err := srv.DeliverActivities()
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 1)
+		So(received[0].Type, ShouldEqual, "Accept")
+		So(received[0].ObjectId(), ShouldEqual, bob+"#follows/1")
+
+		// bad signature, body tampered after signing
+		req := inbox(follow)
+		req.Body = bytes.Replace(req.Body, []byte("Follow"), []byte("Like"), 1)
+		_, err = srv.PostInbox(ctx, req)
+		So(err, ShouldNotBeNil)
+		// signed by another actor
+		forged := map[string]interface{}{
+			"id": "x", "type": "Follow", "actor": remote.URL + "/users/eve", "object": actor,
+		}
+		_, err = srv.PostInbox(ctx, inbox(forged))
+		So(err, ShouldNotBeNil)
+
+		entry := &pb.Entry{
+			Id:          "7a6c8a2ad0c04b3a9f1f3d5a6b7c8d95",
+			Date:        "2015-04-09T07:40:22Z",
+			Body:        "hello fediverse",
+			From:        &pb.Feed{Id: user.Id, Name: user.Name, Type: user.Type},
+			ProfileUuid: user.Uuid,
+		}
+		_, err = srv.PostEntry(ctx, entry)
+		So(err, ShouldBeNil)
+		n, err = srv.DeliverActivities()
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 1)
+		So(received[1].Type, ShouldEqual, "Create")
+		So(received[1].Actor, ShouldEqual, actor)
+		note := activitypub.EntryURL(base, entry.Id)
+		So(received[1].ObjectId(), ShouldEqual, note)
+
+		// direct messages never federated
+		friend := &pb.Profile{Uuid: "9a6c8a2ad0c04b3a9f1f3d5a6b7c8d96", Id: "friend", Name: "friend", Type: "user"}
+		So(store.UpdateProfile(srv.mdb, friend), ShouldBeNil)
+		dm := &pb.Entry{
+			Id:          "8a6c8a2ad0c04b3a9f1f3d5a6b7c8d96",
+			Date:        "2015-04-09T07:41:22Z",
+			Body:        "hello friend",
+			From:        &pb.Feed{Id: user.Id, Name: user.Name, Type: user.Type},
+			To:          []*pb.Feed{{Id: friend.Id}},
+			ProfileUuid: user.Uuid,
+		}
+		_, err = srv.PostEntry(ctx, dm)
+		So(err, ShouldBeNil)
+		So(dm.To[0].Type, ShouldEqual, "user")
+		n, err = srv.DeliverActivities()
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 0)
+
+		like := map[string]interface{}{
+			"id": bob + "#likes/1", "type": "Like", "actor": bob, "object": note,
+		}
+		_, err = srv.PostInbox(ctx, inbox(like))
+		So(err, ShouldBeNil)
+		reply := map[string]interface{}{
+			"id": bob + "/statuses/1/activity", "type": "Create", "actor": bob,
+			"object": map[string]interface{}{
+				"id": bob + "/statuses/1", "type": "Note", "attributedTo": bob,
+				"inReplyTo": note, "published": "2015-04-09T08:00:00Z",
+				"content": "<p>nice &lt;script&gt;</p>",
+			},
+		}
+		_, err = srv.PostInbox(ctx, inbox(reply))
+		So(err, ShouldBeNil)
+
+		saved, err := store.GetEntry(srv.rdb, entry.Id)
+		So(err, ShouldBeNil)
+		So(len(saved.Likes), ShouldEqual, 1)
+		So(saved.Likes[0].From.Id, ShouldEqual, bob)
+		So(len(saved.Comments), ShouldEqual, 1)
+		So(saved.Comments[0].Body, ShouldEqual, "nice &lt;script&gt;")
+		So(saved.Comments[0].From.Name, ShouldContainSubstring, "@bob@")
+
+		// replies to unknown notes rejected
+		reply["object"].(map[string]interface{})["inReplyTo"] = base + "/e/00000000000000000000000000000000"
+		_, err = srv.PostInbox(ctx, inbox(reply))
+		So(err, ShouldNotBeNil)
+
+		undoLike := map[string]interface{}{
+			"id": bob + "#likes/1/undo", "type": "Undo", "actor": bob, "object": like,
+		}
+		_, err = srv.PostInbox(ctx, inbox(undoLike))
+		So(err, ShouldBeNil)
+		saved, _ = store.GetEntry(srv.rdb, entry.Id)
+		So(len(saved.Likes), ShouldEqual, 0)
+
+		undoFollow := map[string]interface{}{
+			"id": bob + "#follows/1/undo", "type": "Undo", "actor": bob, "object": follow,
+		}
+		_, err = srv.PostInbox(ctx, inbox(undoFollow))
+		So(err, ShouldBeNil)
+		followers, _ = store.GetFollowers(srv.mdb, user.Id)
+		So(len(followers), ShouldEqual, 0)
+
+		user.Private = true
+		So(store.UpdateProfile(srv.mdb, user), ShouldBeNil)
+		_, err = srv.FetchActor(ctx, &pb.ActorRequest{Id: user.Id})
+		So(err.Error(), ShouldStartWith, "403")
+	})
+}
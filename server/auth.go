@@ -1,9 +1,15 @@
 package server
 
 import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	uuid "github.com/satori/go.uuid"
+	"github.com/yinhm/friendfeed/importer"
 	pb "github.com/yinhm/friendfeed/proto"
 	store "github.com/yinhm/friendfeed/storage"
 	"golang.org/x/net/context"
@@ -28,14 +34,14 @@ func (s *ApiServer) PutOAuth(ctx context.Context, authinfo *pb.OAuthUser) (*pb.P
 		}
 
 		// build services if profile present
-		if authinfo.Provider == "twitter" {
+		if authinfo.Provider == importer.TwitterServiceId {
 			feedinfo, err := store.GetFeedinfo(s.rdb, profile.Uuid)
 			if err != nil {
 				return nil, err
 			}
 			// WARN: goth user.NickName == screen_name which is twitter id
 			service := &pb.Service{
-				Id:       "twitter",
+				Id:       importer.TwitterServiceId,
 				Name:     "Twitter",
 				Icon:     "/static/images/icons/twitter.png",
 				Profile:  "https://twitter.com/" + user.NickName,
@@ -61,6 +67,9 @@ func (s *ApiServer) BindUserFeed(ctx context.Context, user *pb.OAuthUser) (*pb.O
 }
 
 func (s *ApiServer) FetchProfile(ctx context.Context, req *pb.ProfileRequest) (*pb.Profile, error) {
+	if req.Uuid == "" && req.Id != "" {
+		return store.GetProfile(s.mdb, req.Id)
+	}
 	uuid1, err := uuid.FromString(req.Uuid)
 	if err != nil {
 		return nil, err
@@ -74,13 +83,110 @@ func (s *ApiServer) DeleteService(ctx context.Context, req *pb.ServiceRequest) (
 		return nil, err
 	}
 
-	for i, item := range feedinfo.Services {
-		if item.Id == req.Service {
-			feedinfo.Services = append(feedinfo.Services[:i], feedinfo.Services[i+1:]...)
+	var removed []*pb.Service
+	services := feedinfo.Services[:0]
+	for _, item := range feedinfo.Services {
+		if item.Id == req.Service && (req.Url == "" || item.Url == req.Url) {
+			removed = append(removed, item)
+			continue
 		}
+		services = append(services, item)
 	}
+	feedinfo.Services = services
 	if err := store.SaveFeedinfo(s.rdb, feedinfo.Uuid, feedinfo); err != nil {
 		return nil, err
 	}
+
+	for _, service := range removed {
+		if err := s.unsubscribeService(ctx, req.User, service); err != nil {
+			log.Printf("unsubscribe hub of %s failed: %s", service.Url, err)
+		}
+	}
+	return feedinfo, nil
+}
+
+// AddService adds service configured by url, eg: rss/atom feed, mastodon
+// account, as service of user. Entries of service imported by agent.
+func (s *ApiServer) AddService(ctx context.Context, req *pb.ServiceRequest) (*pb.Feedinfo, error) {
+	if req.Service == "" {
+		req.Service = importer.FeedServiceId
+	}
+	src, ok := importer.Lookup(req.Service)
+	if !ok || !src.Url {
+		return nil, fmt.Errorf("bad request: unknown service %s", req.Service)
+	}
+	u, err := url.Parse(req.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("bad request: invalid feed url")
+	}
+
+	uuid1, err := uuid.FromString(req.User)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := store.GetProfileFromUuid(s.mdb, uuid1)
+	if err != nil {
+		return nil, err
+	}
+	feedinfo, err := store.GetFeedinfo(s.rdb, profile.Uuid)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range feedinfo.Services {
+		if item.Id == src.Id && item.Url == u.String() {
+			return feedinfo, nil
+		}
+	}
+
+	name := req.Name
+	if name == "" {
+		name = u.Host
+	}
+	profileUrl := u.Scheme + "://" + u.Host
+	if src.Id == importer.MastodonServiceId {
+		profileUrl = strings.TrimSuffix(u.String(), "/outbox")
+	}
+	service := &pb.Service{
+		Id:      src.Id,
+		Name:    name,
+		Icon:    "/static/images/icons/" + src.Id + ".png",
+		Profile: profileUrl,
+		Url:     u.String(),
+		Created: time.Now().Unix(),
+		Updated: time.Now().Unix(),
+	}
+	feedinfo.Services = append(feedinfo.Services, service)
+	if err := store.SaveFeedinfo(s.rdb, profile.Uuid, feedinfo); err != nil {
+		return nil, err
+	}
+
+	// initial job imports all items of feed, later ones imports items since
+	// service added
+	initial := proto.Clone(service).(*pb.Service)
+	initial.Updated = 0
+	job := &pb.FeedJob{
+		Uuid:    profile.Uuid,
+		Id:      profile.Id,
+		Profile: profile,
+		Service: initial,
+	}
+	if _, err := s.EnqueJob(ctx, job); err != nil {
+		return nil, err
+	}
+	s.markScheduled(profile, service)
+
+	if req.PushCallback != "" {
+		pushJob := &pb.FeedJob{
+			Uuid:    profile.Uuid,
+			Id:      profile.Id,
+			Profile: profile,
+			Service: service,
+		}
+		go func() {
+			if err := s.subscribeService(context.Background(), pushJob, req.PushCallback); err != nil {
+				log.Printf("subscribe %s failed: %s", service.Url, err)
+			}
+		}()
+	}
 	return feedinfo, nil
 }
@@ -6,19 +6,17 @@ import (
 )
 
 func FormatFeedEntry(mdb *store.Store, req *pb.FeedRequest, entry *pb.Entry) error {
-	if err := fmtEntryProfile(mdb, entry); err != nil {
-		return err
-	}
-	// fmtComments(req, entry)
-	// fmtLikes(req, entry)
-	return nil
+	return FormatEntry(mdb, req.MaxComments, req.MaxLikes, entry)
 }
 
-func FormatEntry(mdb *store.Store, req *pb.FeedRequest, entry *pb.Entry) error {
+// FormatEntry refreshes author profile and collapses comments and likes,
+// see FeedRequest for maxComments and maxLikes.
+func FormatEntry(mdb *store.Store, maxComments, maxLikes int32, entry *pb.Entry) error {
 	if err := fmtEntryProfile(mdb, entry); err != nil {
 		return err
 	}
-	// fmtLikes(req, entry)
+	entry.FormatComments(maxComments)
+	entry.FormatLikes(maxLikes)
 	return nil
 }
 
@@ -32,20 +30,16 @@ func fmtEntryProfile(mdb *store.Store, entry *pb.Entry) error {
 	return nil
 }
 
-func fmtComments(req *pb.FeedRequest, entry *pb.Entry) {
-	entry.FormatComments(req.MaxComments)
-}
-
-func fmtLikes(req *pb.FeedRequest, entry *pb.Entry) {
-	entry.FormatLikes(req.MaxLikes)
-}
-
 func BuildGraph(info *pb.Feedinfo) *pb.Graph {
 	graph := &pb.Graph{
+		Subscribers:   make(map[string]*pb.Profile),
 		Subscriptions: make(map[string]*pb.Profile),
 		Admins:        make(map[string]*pb.Profile),
 		Services:      make(map[string]*pb.Service),
 	}
+	for _, item := range info.Subscribers {
+		graph.Subscribers[item.Id] = item
+	}
 	for _, item := range info.Subscriptions {
 		graph.Subscriptions[item.Id] = item
 	}
@@ -53,7 +47,16 @@ func BuildGraph(info *pb.Feedinfo) *pb.Graph {
 		graph.Admins[item.Id] = item
 	}
 	for _, item := range info.Services {
-		graph.Services[item.Id] = item
+		graph.Services[serviceKey(item)] = item
 	}
 	return graph
 }
+
+// serviceKey identifies service of user, multiple rss/atom feeds of the same
+// service type keyed by url.
+func serviceKey(service *pb.Service) string {
+	if service.Url != "" {
+		return service.Id + ":" + service.Url
+	}
+	return service.Id
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	pb "github.com/yinhm/friendfeed/proto"
+)
+
+// expandEntry restores collapsed comments and likes of friendfeed.com
+// responses, excluded ones are replaced by anonymous items.
+func expandEntry(entry *pb.Entry) *pb.Entry {
+	expanded := *entry
+	expanded.Comments = nil
+	for _, cmt := range entry.Comments {
+		if !cmt.Placeholder {
+			expanded.Comments = append(expanded.Comments, cmt)
+			continue
+		}
+		for i := 0; i < int(cmt.Num); i++ {
+			from := &pb.Feed{Id: fmt.Sprintf("c%d", i)}
+			expanded.Comments = append(expanded.Comments, &pb.Comment{From: from})
+		}
+	}
+	expanded.Likes = nil
+	for _, like := range entry.Likes {
+		if !like.Placeholder {
+			expanded.Likes = append(expanded.Likes, like)
+			continue
+		}
+		for i := 0; i < int(like.Num); i++ {
+			from := &pb.Feed{Id: fmt.Sprintf("l%d", i)}
+			expanded.Likes = append(expanded.Likes, &pb.Like{From: from})
+		}
+	}
+	return &expanded
+}
+
+func loadAutoFeed(t *testing.T) *pb.Feed {
+	bytes, err := ioutil.ReadFile("../ff/testdata/feed_auto.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed := new(pb.Feed)
+	if err := json.Unmarshal(bytes, feed); err != nil {
+		t.Fatal(err)
+	}
+	return feed
+}
+
+func TestFormatAutoCollapsing(t *testing.T) {
+	feed := loadAutoFeed(t)
+
+	Convey("Given friendfeed.com auto collapsed feed, collapse expanded entries the same way", t, func() {
+		for _, entry := range feed.Entries {
+			got := expandEntry(entry)
+			got.FormatComments(pb.MaxAuto)
+			got.FormatLikes(pb.MaxAuto)
+
+			So(len(got.Comments), ShouldEqual, len(entry.Comments))
+			for i, cmt := range entry.Comments {
+				So(got.Comments[i].Placeholder, ShouldEqual, cmt.Placeholder)
+				if cmt.Placeholder {
+					So(got.Comments[i].Num, ShouldEqual, cmt.Num)
+					So(got.Comments[i].Body, ShouldEqual, cmt.Body)
+				} else {
+					So(got.Comments[i].From.Id, ShouldEqual, cmt.From.Id)
+				}
+			}
+
+			So(len(got.Likes), ShouldEqual, len(entry.Likes))
+			for i, like := range entry.Likes {
+				So(got.Likes[i].Placeholder, ShouldEqual, like.Placeholder)
+				if like.Placeholder {
+					So(got.Likes[i].Num, ShouldEqual, like.Num)
+					So(got.Likes[i].Body, ShouldEqual, like.Body)
+				} else {
+					So(got.Likes[i].From.Id, ShouldEqual, like.From.Id)
+				}
+			}
+		}
+	})
+}
+
+func TestFormatCollapsing(t *testing.T) {
+	feed := loadAutoFeed(t)
+	// 13 comments, 34 likes after expanded
+	entry := expandEntry(feed.Entries[0])
+
+	tests := []struct {
+		max         int32
+		comments    int
+		likes       int
+		commentsNum int32
+		likesNum    int32
+		commentBody string
+		likeBody    string
+	}{
+		{pb.MaxNone, 0, 0, 0, 0, "", ""},
+		{pb.MaxAuto, 3, 4, 11, 31, "11 more comments", "31 other people"},
+		{pb.MaxAll, 13, 34, 0, 0, "", ""},
+		{2, 3, 3, 11, 32, "11 more comments", "32 other people"},
+		{5, 6, 6, 8, 29, "8 more comments", "29 other people"},
+		{12, 13, 13, 1, 22, "1 more comment", "22 other people"},
+		{33, 13, 34, 0, 1, "", "1 other person"},
+		{100, 13, 34, 0, 0, "", ""},
+	}
+
+	Convey("Given expanded entry, collapse comments and likes by limit", t, func() {
+		So(len(entry.Comments), ShouldEqual, 13)
+		So(len(entry.Likes), ShouldEqual, 34)
+
+		for _, tt := range tests {
+			got := expandEntry(entry)
+			got.FormatComments(tt.max)
+			got.FormatLikes(tt.max)
+
+			So(len(got.Comments), ShouldEqual, tt.comments)
+			So(len(got.Likes), ShouldEqual, tt.likes)
+
+			var num int32
+			for i, cmt := range got.Comments {
+				if cmt.Placeholder {
+					So(i, ShouldEqual, 1)
+					So(cmt.Body, ShouldEqual, tt.commentBody)
+					num = cmt.Num
+				}
+			}
+			So(num, ShouldEqual, tt.commentsNum)
+			if tt.comments > 0 {
+				So(got.Comments[0], ShouldEqual, entry.Comments[0])
+				So(got.Comments[len(got.Comments)-1], ShouldEqual, entry.Comments[12])
+			}
+
+			num = 0
+			for i, like := range got.Likes {
+				if like.Placeholder {
+					So(i, ShouldEqual, len(got.Likes)-1)
+					So(like.Body, ShouldEqual, tt.likeBody)
+					num = like.Num
+				}
+			}
+			So(num, ShouldEqual, tt.likesNum)
+		}
+	})
+
+	Convey("Given limit of 1, collapse by count, not as MaxAll", t, func() {
+		got := expandEntry(entry)
+		got.CollapseComments(1)
+		got.CollapseLikes(1)
+		So(len(got.Comments), ShouldEqual, 2)
+		So(got.Comments[0], ShouldEqual, entry.Comments[0])
+		So(got.Comments[1].Num, ShouldEqual, 12)
+		So(len(got.Likes), ShouldEqual, 2)
+		So(got.Likes[1].Num, ShouldEqual, 33)
+	})
+}
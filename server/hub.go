@@ -0,0 +1,263 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/yinhm/friendfeed/proto"
+	store "github.com/yinhm/friendfeed/storage"
+	"github.com/yinhm/friendfeed/syndication"
+	"github.com/yinhm/friendfeed/websub"
+	"golang.org/x/net/context"
+)
+
+const (
+	// entries distributed to subscribers
+	hubPageSize = 20
+	// given up after max attempts, retried with exponential backoff
+	hubMaxAttempts = 10
+	hubRetryDelay  = 30 * time.Second
+	hubMaxDelay    = 6 * time.Hour
+	hubTimeout     = 30 * time.Second
+)
+
+var hubClient = &http.Client{Timeout: hubTimeout}
+
+// HubSubscribe accepts subscribe or unsubscribe request to our feeds, intent
+// of subscriber is verified asynchronously.
+func (s *ApiServer) HubSubscribe(ctx context.Context, sub *pb.HubSubscription) (*pb.HubSubscription, error) {
+	if sub.Topic == "" || sub.Callback == "" || sub.FeedId == "" || sub.Hub == "" {
+		return nil, fmt.Errorf("bad request")
+	}
+	if sub.Mode != websub.ModeSubscribe && sub.Mode != websub.ModeUnsubscribe {
+		return nil, fmt.Errorf("bad request: unknown mode")
+	}
+
+	if sub.Mode == websub.ModeSubscribe && sub.FeedId != "public" {
+		profile, err := store.GetProfile(s.mdb, sub.FeedId)
+		if err != nil {
+			return nil, fmt.Errorf("404")
+		}
+		if profile.Private {
+			go websub.Deny(context.Background(), hubClient, sub.Topic, sub.Callback, "private feed")
+			return nil, fmt.Errorf("403: private feed")
+		}
+	}
+
+	go func() {
+		if err := s.verifyHubSubscription(sub); err != nil {
+			log.Printf("hub: %s %s failed: %s", sub.Mode, sub.Callback, err)
+		}
+	}()
+	return sub, nil
+}
+
+// verifyHubSubscription verifies intent of subscriber, subscription saved or
+// deleted if verified. Subscribing again renews the lease.
+func (s *ApiServer) verifyHubSubscription(sub *pb.HubSubscription) error {
+	ctx, cancel := context.WithTimeout(context.Background(), hubTimeout)
+	defer cancel()
+
+	lease := websub.Lease(time.Duration(sub.LeaseSeconds) * time.Second)
+	err := websub.VerifyIntent(ctx, hubClient, sub.Mode, sub.Topic, sub.Callback, lease)
+	if err != nil {
+		return err
+	}
+
+	meta := store.HubSubscriptionMeta(sub.FeedId, sub.Topic, sub.Callback)
+	if sub.Mode == websub.ModeUnsubscribe {
+		if err := store.DeleteHubDelivery(s.mdb, meta); err != nil {
+			return err
+		}
+		return store.DeleteHubSubscription(s.mdb, meta)
+	}
+
+	old, err := store.GetHubSubscription(s.mdb, meta)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	sub = proto.Clone(sub).(*pb.HubSubscription)
+	sub.Created = old.Created
+	if sub.Created == 0 {
+		sub.Created = now.Unix()
+	}
+	sub.LeaseSeconds = int64(lease.Seconds())
+	sub.Expires = now.Add(lease).Unix()
+	return store.PutHubSubscription(s.mdb, sub)
+}
+
+// publishHub schedules content distribution to subscribers of feeds
+// updated, at most one delivery pending per subscriber.
+func (s *ApiServer) publishHub(feedIds ...string) {
+	now := time.Now().Unix()
+	n := 0
+	for _, feedId := range feedIds {
+		subs, err := store.GetHubSubscriptions(s.mdb, feedId)
+		if err != nil {
+			log.Println("hub: get subscriptions failed:", err)
+			continue
+		}
+		for _, sub := range subs {
+			if sub.Expires < now {
+				continue
+			}
+			meta := store.HubSubscriptionMeta(sub.FeedId, sub.Topic, sub.Callback)
+			pending, err := store.GetHubDelivery(s.mdb, meta)
+			if err != nil || pending.Key != "" {
+				continue
+			}
+			d := &pb.HubDelivery{Key: meta, NextAttempt: now, Created: now}
+			if err := store.PutHubDelivery(s.mdb, d); err != nil {
+				log.Println("hub: put delivery failed:", err)
+				continue
+			}
+			n++
+		}
+	}
+	if n > 0 {
+		select {
+		case s.hubCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// DeliverHub distributes content of pending deliveries due, failed
+// deliveries are retried later.
+func (s *ApiServer) DeliverHub() (int, error) {
+	now := time.Now()
+	var due []*pb.HubDelivery
+	_, err := store.ForwardTableScan(s.mdb, store.TableHubDelivery, func(i int, k, v []byte) error {
+		d := new(pb.HubDelivery)
+		if err := proto.Unmarshal(v, d); err != nil {
+			return err
+		}
+		if d.NextAttempt <= now.Unix() {
+			due = append(due, d)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, d := range due {
+		sub, err := store.GetHubSubscription(s.mdb, d.Key)
+		if err != nil {
+			return n, err
+		}
+		if sub.Topic == "" || sub.Expires < now.Unix() {
+			// unsubscribed or expired
+			if err := store.DeleteHubSubscription(s.mdb, d.Key); err != nil {
+				return n, err
+			}
+			if err := store.DeleteHubDelivery(s.mdb, d.Key); err != nil {
+				return n, err
+			}
+			continue
+		}
+
+		err = s.distribute(sub)
+		if err == nil {
+			n++
+			if err := store.DeleteHubDelivery(s.mdb, d.Key); err != nil {
+				return n, err
+			}
+			continue
+		}
+
+		d.Attempts++
+		d.Error = err.Error()
+		if err == websub.ErrGone || d.Attempts >= hubMaxAttempts {
+			log.Printf("hub: give up delivery to %s: %s", sub.Callback, err)
+			if err == websub.ErrGone {
+				store.DeleteHubSubscription(s.mdb, d.Key)
+			}
+			if err := store.DeleteHubDelivery(s.mdb, d.Key); err != nil {
+				return n, err
+			}
+			continue
+		}
+		d.NextAttempt = now.Add(hubBackoff(d.Attempts)).Unix()
+		if err := store.PutHubDelivery(s.mdb, d); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+func hubBackoff(attempts int32) time.Duration {
+	delay := hubRetryDelay
+	for i := int32(1); i < attempts && delay < hubMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > hubMaxDelay {
+		delay = hubMaxDelay
+	}
+	return delay
+}
+
+// distribute posts the latest entries of feed to subscriber, in the format
+// of topic.
+func (s *ApiServer) distribute(sub *pb.HubSubscription) error {
+	ctx, cancel := context.WithTimeout(context.Background(), hubTimeout)
+	defer cancel()
+
+	req := &pb.FeedRequest{Id: sub.FeedId, PageSize: hubPageSize}
+	feed, err := s.FetchFeed(ctx, req)
+	if err != nil {
+		return err
+	}
+	if feed.Private {
+		return websub.ErrGone
+	}
+
+	topic, err := url.Parse(sub.Topic)
+	if err != nil {
+		return websub.ErrGone
+	}
+	link := *topic
+	query := link.Query()
+	format := query.Get("format")
+	query.Del("format")
+	link.RawQuery = query.Encode()
+	opt := &syndication.Options{
+		BaseURL: topic.Scheme + "://" + topic.Host,
+		Link:    link.String(),
+		Self:    sub.Topic,
+		Hub:     sub.Hub,
+	}
+
+	var content []byte
+	contentType := syndication.ContentTypeAtom
+	if format == "rss" {
+		contentType = syndication.ContentTypeRSS
+		content, err = syndication.RSS(feed, opt)
+	} else {
+		content, err = syndication.Atom(feed, opt)
+	}
+	if err != nil {
+		return err
+	}
+	return websub.Distribute(ctx, hubClient, sub.Hub, sub.Topic, sub.Callback, sub.Secret, contentType, content)
+}
+
+// HubJobTicker delivers content once feeds published, retries periodically.
+func (s *ApiServer) HubJobTicker() {
+	t := time.Tick(hubRetryDelay)
+	for {
+		select {
+		case <-t:
+		case <-s.hubCh:
+		}
+		if _, err := s.DeliverHub(); err != nil {
+			log.Println("hub: deliver failed:", err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	pb "github.com/yinhm/friendfeed/proto"
+	store "github.com/yinhm/friendfeed/storage"
+	"github.com/yinhm/friendfeed/websub"
+	"golang.org/x/net/context"
+)
+
+func TestHubDelivery(t *testing.T) {
+	setup()
+	defer teardown()
+
+	Convey("Given subscriber, distribute content once feed updated", t, func() {
+		ctx := context.Background()
+
+		user := &pb.Profile{
+			Uuid: "c6f8dca854f011ddb489003048343a40",
+			Id:   "yinhm",
+			Name: "yinhm",
+			Type: "user",
+		}
+		So(store.UpdateProfile(srv.mdb, user), ShouldBeNil)
+
+		// stand-in subscriber
+		status := http.StatusNoContent
+		var received []string
+		subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "GET" {
+				w.Write([]byte(r.URL.Query().Get("hub.challenge")))
+				return
+			}
+			body, _ := ioutil.ReadAll(r.Body)
+			if websub.VerifySignature("secret", r.Header.Get(websub.SignatureHeader), body) {
+				received = append(received, string(body))
+			}
+			w.WriteHeader(status)
+		}))
+		defer subscriber.Close()
+
+		sub := &pb.HubSubscription{
+			Mode:     websub.ModeSubscribe,
+			FeedId:   user.Id,
+			Topic:    "http://ff.example.com/feed/yinhm?format=atom",
+			Callback: subscriber.URL + "/callback",
+			Secret:   "secret",
+			Hub:      "http://ff.example.com/push/hub",
+		}
+		_, err := srv.HubSubscribe(ctx, &pb.HubSubscription{Mode: "publish"})
+		So(err, ShouldNotBeNil)
+		_, err = srv.HubSubscribe(ctx, &pb.HubSubscription{
+			Mode: websub.ModeSubscribe, FeedId: "nobody", Topic: sub.Topic, Callback: sub.Callback, Hub: sub.Hub,
+		})
+		So(err, ShouldNotBeNil)
+
+		So(srv.verifyHubSubscription(sub), ShouldBeNil)
+		subs, err := store.GetHubSubscriptions(srv.mdb, user.Id)
+		So(err, ShouldBeNil)
+		So(len(subs), ShouldEqual, 1)
+		So(subs[0].Expires, ShouldBeGreaterThan, time.Now().Unix())
+
+		entry := &pb.Entry{
+			Id:          "4f6c8a2ad0c04b3a9f1f3d5a6b7c8d94",
+			Date:        "2015-04-09T07:40:22Z",
+			Body:        "hello websub",
+			From:        &pb.Feed{Id: user.Id, Name: user.Name, Type: user.Type},
+			ProfileUuid: user.Uuid,
+		}
+		_, err = srv.PostEntry(ctx, entry)
+		So(err, ShouldBeNil)
+
+		n, err := srv.DeliverHub()
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 1)
+		So(len(received), ShouldEqual, 1)
+		So(strings.Contains(received[0], "hello websub"), ShouldBeTrue)
+		So(strings.Contains(received[0], `rel="hub"`), ShouldBeTrue)
+
+		// nothing pending
+		n, err = srv.DeliverHub()
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 0)
+
+		// failed delivery retried later
+		status = http.StatusInternalServerError
+		srv.publishHub(user.Id)
+		n, err = srv.DeliverHub()
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 0)
+
+		meta := store.HubSubscriptionMeta(sub.FeedId, sub.Topic, sub.Callback)
+		d, err := store.GetHubDelivery(srv.mdb, meta)
+		So(err, ShouldBeNil)
+		So(d.Attempts, ShouldEqual, 1)
+		So(d.NextAttempt, ShouldBeGreaterThan, time.Now().Unix())
+
+		// not due yet
+		status = http.StatusNoContent
+		n, err = srv.DeliverHub()
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 0)
+
+		d.NextAttempt = time.Now().Unix()
+		So(store.PutHubDelivery(srv.mdb, d), ShouldBeNil)
+		n, err = srv.DeliverHub()
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 1)
+
+		// subscriber gone
+		status = http.StatusGone
+		srv.publishHub(user.Id)
+		_, err = srv.DeliverHub()
+		So(err, ShouldBeNil)
+		subs, err = store.GetHubSubscriptions(srv.mdb, user.Id)
+		So(err, ShouldBeNil)
+		So(len(subs), ShouldEqual, 0)
+	})
+}
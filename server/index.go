@@ -73,6 +73,31 @@ func (f *FeedIndex) Push(uuid string) {
 	f.itemCh <- uuid
 }
 
+// Remove drops key from index, eg: entry deleted.
+func (f *FeedIndex) Remove(key string) {
+	f.Lock()
+	defer f.Unlock()
+
+	bufq := make([]string, MinQueue)
+	i := 0
+	for _, item := range f.bufq {
+		if item != key {
+			bufq[i] = item
+			i++
+		}
+	}
+	f.bufq = bufq
+
+	// pending items
+	n := f.iq.Length()
+	for j := 0; j < n; j++ {
+		item := f.iq.Remove().(string)
+		if item != key {
+			f.iq.Add(item)
+		}
+	}
+}
+
 func (f *FeedIndex) rebuild() {
 	if !f.dirty {
 		return
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"log"
@@ -8,11 +9,17 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	uuid "github.com/satori/go.uuid"
+	"github.com/yinhm/friendfeed/importer"
 	pb "github.com/yinhm/friendfeed/proto"
 	store "github.com/yinhm/friendfeed/storage"
+	"github.com/yinhm/friendfeed/sup"
 	"golang.org/x/net/context"
 )
 
+// jobTimeout is the longest a running job goes without checkpoint, longer
+// than the longest backoff of agents.
+const jobTimeout = time.Hour
+
 func (s *ApiServer) RefetchJobTicker() {
 	t := time.Tick(2 * time.Minute)
 	for _ = range t {
@@ -31,9 +38,24 @@ func (s *ApiServer) IndexJobTicker() {
 	}
 }
 
+// SupJobTicker purges sup updates older than the longest sup period.
+func (s *ApiServer) SupJobTicker() {
+	t := time.Tick(15 * time.Minute)
+	for _ = range t {
+		before := time.Now().Add(-time.Hour)
+		if err := store.DeleteSupUpdates(s.rdb, before); err != nil {
+			log.Println("purge sup updates failed:", err)
+		}
+	}
+}
+
 func (s *ApiServer) RefetchUserFeed() error {
 	prefix := store.TableProfile
 	j := 0
+	pushed, err := s.pushedJobs()
+	if err != nil {
+		return err
+	}
 	n, err := store.ForwardTableScan(s.mdb, prefix, func(i int, k, v []byte) error {
 		profile := &pb.Profile{}
 		if err := proto.Unmarshal(v, profile); err != nil {
@@ -41,73 +63,89 @@ func (s *ApiServer) RefetchUserFeed() error {
 		}
 
 		feedinfo, _ := store.GetFeedinfo(s.rdb, profile.Uuid)
-		// only sync twitter service
-		graph := BuildGraph(feedinfo)
-		if _, ok := graph.Services["twitter"]; !ok {
-			return nil
-		}
+		scheduled, err := s.scheduleServices(profile, feedinfo, pushed, false)
+		j += scheduled
+		return err
+	})
+	if err != nil {
+		log.Println("Error on scanning user profiles:", err)
+	}
+	log.Printf("Jobs pulled: %d scanned, %d user feeds scheduled.", n, j)
+	return err
+}
 
-		service := graph.Services["twitter"]
-		if service.Oauth == nil {
-			return nil
+// markScheduled records job of service scheduled.
+func (s *ApiServer) markScheduled(profile *pb.Profile, service *pb.Service) {
+	s.Lock()
+	defer s.Unlock()
+	if s.scheduled == nil {
+		s.scheduled = make(map[string]time.Time)
+	}
+	s.scheduled[profile.Uuid+"/"+serviceKey(service)] = time.Now()
+}
+
+// scheduleServices enqueues jobs of services registered in importer, a
+// service scheduled at most once per interval of its source unless forced.
+// Services in pushed are left to their subscriptions unless forced.
+func (s *ApiServer) scheduleServices(profile *pb.Profile, feedinfo *pb.Feedinfo, pushed map[string]bool, force bool) (int, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.scheduled == nil {
+		s.scheduled = make(map[string]time.Time)
+	}
+
+	now := time.Now()
+	n := 0
+	for _, service := range feedinfo.GetServices() {
+		src, ok := importer.Lookup(service.Id)
+		if !ok || !src.Ready(service) {
+			continue
+		}
+		key := profile.Uuid + "/" + serviceKey(service)
+		if pushed[key] && !force {
+			continue
+		}
+		if last, ok := s.scheduled[key]; ok && !force && now.Sub(last) < src.Interval {
+			continue
 		}
 		job := &pb.FeedJob{
-			Uuid:    feedinfo.Uuid,
-			Id:      feedinfo.Id,
+			Uuid:    profile.Uuid,
+			Id:      profile.Id,
 			Profile: profile,
 			Service: service,
 			Start:   0,
-			Created: time.Now().Unix(),
-			Updated: time.Now().Unix(),
 		}
-
-		_, err := s.EnqueJob(context.Background(), job)
-		j++
-		return err
-	})
-	if err != nil {
-		log.Println("Error on scanning user profiles:", err)
+		if _, err := s.EnqueJob(context.Background(), job); err != nil {
+			return n, err
+		}
+		s.scheduled[key] = now
+		n++
 	}
-	log.Printf("Jobs pulled: %d scanned, %d user feeds scheduled.", n, j)
-	return err
+	return n, nil
 }
 
 func (s *ApiServer) RefetchFriendFeed() error {
 	prefix := store.TableProfile
 	j := 0
+	pushed, err := s.pushedJobs()
+	if err != nil {
+		return err
+	}
 	n, err := store.ForwardTableScan(s.mdb, prefix, func(i int, k, v []byte) error {
 		profile := &pb.Profile{}
 		if err := proto.Unmarshal(v, profile); err != nil {
 			return err
 		}
 
-		if profile.RemoteKey != "" {
-			return nil
-		}
-
-		oldjob, err := store.GetArchiveHistory(s.mdb, profile.Id)
-		if err != nil {
+		job, err := s.friendfeedJob(profile)
+		if err != nil || job == nil {
 			return err
 		}
-
-		if oldjob.Id == "" || oldjob.RemoteKey == "" {
-			log.Println("Refetch Friendfeed: unknown remote key")
+		if pushed[pushKey(job)] {
+			// subscribed to friendfeed sup
 			return nil
 		}
 
-		job := &pb.FeedJob{
-			Uuid:        profile.Uuid,
-			Id:          oldjob.Id,
-			RemoteKey:   oldjob.RemoteKey,
-			TargetId:    profile.Id,
-			Start:       0,
-			PageSize:    100,
-			MaxLimit:    99,
-			ForceUpdate: true,
-			Created:     time.Now().Unix(),
-			Updated:     time.Now().Unix(),
-		}
-
 		log.Println(job)
 		_, err = s.EnqueJob(context.Background(), job)
 		j++
@@ -120,6 +158,38 @@ func (s *ApiServer) RefetchFriendFeed() error {
 	return err
 }
 
+// friendfeedJob returns job refetching archived friendfeed feed, nil if
+// profile never archived from friendfeed.
+func (s *ApiServer) friendfeedJob(profile *pb.Profile) (*pb.FeedJob, error) {
+	if profile.RemoteKey != "" {
+		return nil, nil
+	}
+
+	oldjob, err := store.GetArchiveHistory(s.mdb, profile.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if oldjob.Id == "" || oldjob.RemoteKey == "" {
+		log.Println("Refetch Friendfeed: unknown remote key")
+		return nil, nil
+	}
+
+	job := &pb.FeedJob{
+		Uuid:        profile.Uuid,
+		Id:          oldjob.Id,
+		RemoteKey:   oldjob.RemoteKey,
+		TargetId:    profile.Id,
+		Start:       0,
+		PageSize:    100,
+		MaxLimit:    99,
+		ForceUpdate: true,
+		Created:     time.Now().Unix(),
+		Updated:     time.Now().Unix(),
+	}
+	return job, nil
+}
+
 func (s *ApiServer) EnqueJob(ctx context.Context, job *pb.FeedJob) (*pb.FeedJob, error) {
 	// Time ordered job queue
 	key := store.NewFlakeKey(store.TableJobFeed, s.mdb.NextId())
@@ -132,7 +202,9 @@ func (s *ApiServer) EnqueJob(ctx context.Context, job *pb.FeedJob) (*pb.FeedJob,
 	if err != nil {
 		return nil, err
 	}
-	s.mdb.Put(key.Bytes(), bytes)
+	if err := s.mdb.Put(key.Bytes(), bytes); err != nil {
+		return nil, err
+	}
 	return job, nil
 }
 
@@ -204,6 +276,63 @@ func (s *ApiServer) FinishJob(ctx context.Context, job *pb.FeedJob) (*pb.FeedJob
 	return job, nil
 }
 
+// UpdateJob checkpoints progress of running job, requeued jobs of dead
+// workers resume from the last checkpoint.
+func (s *ApiServer) UpdateJob(ctx context.Context, job *pb.FeedJob) (*pb.FeedJob, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	kb, err := s.runningJobKey(job)
+	if err != nil {
+		return nil, err
+	}
+	job.Updated = time.Now().Unix()
+	data, err := proto.Marshal(job)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mdb.Put(kb, data); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// ReleaseJob requeues running job, resumed from its last checkpoint by the
+// next worker.
+func (s *ApiServer) ReleaseJob(ctx context.Context, job *pb.FeedJob) (*pb.FeedJob, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	kb, err := s.runningJobKey(job)
+	if err != nil {
+		return nil, err
+	}
+	job.Worker = ""
+	if _, err := s.EnqueJob(ctx, job); err != nil {
+		return nil, err
+	}
+	if err := s.mdb.Delete(kb); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// runningJobKey returns key of job if it is still running.
+func (s *ApiServer) runningJobKey(job *pb.FeedJob) ([]byte, error) {
+	kb, err := hex.DecodeString(job.Key)
+	if err != nil || !bytes.HasPrefix(kb, store.TableJobRunning.Bytes()) {
+		return nil, fmt.Errorf("bad request: job not running")
+	}
+	rawdata, err := s.mdb.Get(kb)
+	if err != nil {
+		return nil, err
+	}
+	if len(rawdata) == 0 {
+		return nil, fmt.Errorf("404")
+	}
+	return kb, nil
+}
+
 func (s *ApiServer) ListJobQueue(prefix store.Key) (jobs []*pb.FeedJob, err error) {
 	log.Println("listing running job...")
 	store.ForwardTableScan(s.mdb, prefix, func(i int, key, value []byte) error {
@@ -234,15 +363,26 @@ func (s *ApiServer) Command(ctx context.Context, cmd *pb.CommandRequest) (*pb.Co
 	case "FixTooMuchJobs":
 		s.FixTooMuchJobs()
 	case "RedoFailedJob":
-		s.RedoFailedJob()
+		if err := s.RedoFailedJob(); err != nil {
+			log.Println("redo failed jobs failed:", err)
+		}
 	case "RefetchUserFeed":
 		s.RefetchUserFeed()
 	case "RefetchFriendFeed":
 		s.RefetchFriendFeed()
+	case "SubscribeFriendFeed":
+		s.SubscribeFriendFeed()
+	case "SubscribeServiceHubs":
+		// callback of our site, eg: http://example.com/push/callback
+		s.SubscribeServiceHubs(cmd.Arg1)
 	case "TestJob":
 		s.TestJob()
 	case "FixComment":
 		s.FixComment()
+	case "AssignSupIds":
+		if err := s.AssignSupIds(); err != nil {
+			log.Println("assign sup ids failed:", err)
+		}
 	case "MarkDelete":
 		s.MarkDelete(cmd.Arg1)
 	}
@@ -365,8 +505,17 @@ func (s *ApiServer) FixTooMuchJobs() error {
 	return nil
 }
 
+// RedoFailedJob requeues running jobs not checkpointed within jobTimeout,
+// workers of these jobs considered dead.
 func (s *ApiServer) RedoFailedJob() error {
 	log.Println("redo failed jobs...")
+	return s.requeueJobs(time.Now().Add(-jobTimeout))
+}
+
+// requeueJobs requeues running jobs last updated before t.
+func (s *ApiServer) requeueJobs(t time.Time) error {
+	s.Lock()
+	defer s.Unlock()
 
 	prefix := store.TableJobRunning
 	_, err := store.ForwardTableScan(s.mdb, prefix, func(i int, k, v []byte) error {
@@ -374,12 +523,16 @@ func (s *ApiServer) RedoFailedJob() error {
 		if err := proto.Unmarshal(v, job); err != nil {
 			return err
 		}
+		if job.Updated >= t.Unix() {
+			return nil
+		}
 
-		_, err := s.EnqueJob(context.Background(), job)
-		if err != nil {
-			return s.mdb.Delete(k)
+		// resumed from the last checkpoint of job, running job kept until
+		// requeued
+		if _, err := s.EnqueJob(context.Background(), job); err != nil {
+			return err
 		}
-		return nil
+		return s.mdb.Delete(k)
 	})
 
 	if err != nil {
@@ -389,29 +542,12 @@ func (s *ApiServer) RedoFailedJob() error {
 }
 
 func (s *ApiServer) TestJob() error {
-	profile, _ := store.GetProfile(s.mdb, "yinhm")
-	feedinfo, _ := store.GetFeedinfo(s.rdb, profile.Uuid)
-	// only sync twitter service
-	graph := BuildGraph(feedinfo)
-	if _, ok := graph.Services["twitter"]; !ok {
-		return nil
-	}
-
-	service := graph.Services["twitter"]
-	if service.Oauth == nil {
-		return nil
-	}
-	job := &pb.FeedJob{
-		Uuid:    feedinfo.Uuid,
-		Id:      feedinfo.Id,
-		Profile: profile,
-		Service: service,
-		Start:   0,
-		Created: time.Now().Unix(),
-		Updated: time.Now().Unix(),
+	profile, err := store.GetProfile(s.mdb, "yinhm")
+	if err != nil {
+		return err
 	}
-
-	_, err := s.EnqueJob(context.Background(), job)
+	feedinfo, _ := store.GetFeedinfo(s.rdb, profile.Uuid)
+	_, err = s.scheduleServices(profile, feedinfo, nil, true)
 	return err
 }
 
@@ -445,3 +581,41 @@ func (s *ApiServer) FixComment() error {
 	}
 	return nil
 }
+
+// AssignSupIds saves sup ids of profiles generated on read before, so that
+// ids are kept once profiles renamed. Profiles archived from friendfeed are
+// left to sup ids imported.
+func (s *ApiServer) AssignSupIds() error {
+	var profiles []*pb.Profile
+	_, err := store.ForwardTableScan(s.mdb, store.TableProfile, func(i int, k, v []byte) error {
+		profile := &pb.Profile{}
+		if err := proto.Unmarshal(v, profile); err != nil {
+			return err
+		}
+		if profile.SupId == "" {
+			profiles = append(profiles, profile)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	n := 0
+	for _, profile := range profiles {
+		oldjob, err := store.GetArchiveHistory(s.mdb, profile.Id)
+		if err != nil {
+			return err
+		}
+		if oldjob.Id != "" {
+			continue
+		}
+		profile.SupId = sup.NewId(profile.Id)
+		if err := store.UpdateProfile(s.mdb, profile); err != nil {
+			return err
+		}
+		n++
+	}
+	log.Printf("Sup ids assigned: %d profiles.", n)
+	return nil
+}
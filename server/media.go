@@ -0,0 +1,348 @@
+package server
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/yinhm/friendfeed/media"
+	pb "github.com/yinhm/friendfeed/proto"
+	store "github.com/yinhm/friendfeed/storage"
+)
+
+const (
+	// blobs unreferenced longer than mediaGrace are collected
+	mediaGrace = 7 * 24 * time.Hour
+	// entries mirrored concurrently
+	mediaWorkers = 4
+)
+
+// mediaIndex keeps url index of content store in meta store.
+type mediaIndex struct {
+	s *ApiServer
+}
+
+func (idx *mediaIndex) Hash(src string) (string, error) {
+	return store.GetMediaHash(idx.s.mdb, src)
+}
+
+func (idx *mediaIndex) PutHash(src string, obj *media.Object) error {
+	idx.s.mediaMu.Lock()
+	defer idx.s.mediaMu.Unlock()
+	return store.PutMediaHash(idx.s.mdb, src, obj.Hash, obj.MimeType)
+}
+
+// pictureOwner returns owner of media refs of profile picture.
+func pictureOwner(id string) string {
+	return "picture/" + id
+}
+
+// refMedia references blobs of hashes from owner, blobs referenced before
+// replaced if replace.
+func (s *ApiServer) refMedia(owner string, replace bool, hashes ...string) error {
+	s.mediaMu.Lock()
+	defer s.mediaMu.Unlock()
+	if replace {
+		return store.SetMediaRefs(s.mdb, owner, hashes...)
+	}
+	return store.AddMediaRefs(s.mdb, owner, hashes...)
+}
+
+// CollectMedia deletes blobs unreferenced longer than grace, returns number
+// of blobs deleted. Mirroring waits until collected.
+func (s *ApiServer) CollectMedia(grace time.Duration) (int, error) {
+	s.mediaGC.Lock()
+	defer s.mediaGC.Unlock()
+	s.mediaMu.Lock()
+	defer s.mediaMu.Unlock()
+
+	blobs, err := store.GetOrphanedMedia(s.mdb, time.Now().Add(-grace))
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, blob := range blobs {
+		if err := s.fs.Delete(media.BlobPath(blob.Hash)); err != nil {
+			return n, err
+		}
+		if err := store.DeleteMediaBlob(s.mdb, blob.Hash); err != nil {
+			return n, err
+		}
+		// thumbnails collected next time
+		if err := store.SetMediaRefs(s.mdb, thumbOwner(blob.Hash)); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// MediaJobTicker collects orphaned media daily.
+func (s *ApiServer) MediaJobTicker() {
+	t := time.Tick(24 * time.Hour)
+	for _ = range t {
+		n, err := s.CollectMedia(mediaGrace)
+		if err != nil {
+			log.Println("collect media failed:", err)
+		}
+		log.Printf("%d media blobs collected.", n)
+	}
+}
+
+// hasMirrorable reports whether entry has any media to mirror, media of
+// twitter, mastodon or feeds never mirrored.
+func hasMirrorable(entry *pb.Entry) bool {
+	for _, thumb := range entry.Thumbnails {
+		if media.IsMirrorable(thumb.Link) || media.IsMirrorable(thumb.Url) {
+			return true
+		}
+	}
+	for _, file := range entry.Files {
+		if media.IsMirrorable(file.Url) {
+			return true
+		}
+	}
+	return false
+}
+
+// enqueMirror queues mirroring of media of entry stored, urls of entry
+// rewrote once mirrored.
+func (s *ApiServer) enqueMirror(entry *pb.Entry) error {
+	if !hasMirrorable(entry) {
+		return nil
+	}
+	now := time.Now().Unix()
+	m := &pb.MediaMirror{EntryId: entry.Id, NextAttempt: now, Created: now}
+	if err := store.PutMediaMirror(s.mdb, m); err != nil {
+		return err
+	}
+	select {
+	case s.mediaCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// MirrorPendingMedia mirrors media of entries due by mediaWorkers, failed
+// mirroring retried later. Returns number of entries mirrored.
+func (s *ApiServer) MirrorPendingMedia() (int, error) {
+	now := time.Now()
+	var due []*pb.MediaMirror
+	_, err := store.ForwardTableScan(s.mdb, store.TableMediaMirror, func(i int, k, v []byte) error {
+		m := new(pb.MediaMirror)
+		if err := proto.Unmarshal(v, m); err != nil {
+			return err
+		}
+		if m.NextAttempt <= now.Unix() {
+			due = append(due, m)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		n        int
+		firstErr error
+	)
+	jobs := make(chan *pb.MediaMirror)
+	for i := 0; i < mediaWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for m := range jobs {
+				mirrored, err := s.mirrorPending(m, now)
+				mu.Lock()
+				if mirrored {
+					n++
+				}
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, m := range due {
+		jobs <- m
+	}
+	close(jobs)
+	wg.Wait()
+	return n, firstErr
+}
+
+// mirrorPending mirrors media of m, reports whether mirrored. Mirroring
+// failed is retried with backoff, media mirrored partly rewrote once given
+// up. Errors returned are of the queue itself.
+func (s *ApiServer) mirrorPending(m *pb.MediaMirror, now time.Time) (bool, error) {
+	final := m.Attempts+1 >= hubMaxAttempts
+	err := s.mirrorEntry(m.EntryId, final)
+	if err == nil {
+		return true, store.DeleteMediaMirror(s.mdb, m.EntryId)
+	}
+
+	m.Attempts++
+	m.Error = err.Error()
+	if final {
+		log.Printf("give up mirroring media of %s: %s", m.EntryId, err)
+		return false, store.DeleteMediaMirror(s.mdb, m.EntryId)
+	}
+	m.NextAttempt = now.Add(hubBackoff(m.Attempts)).Unix()
+	return false, store.PutMediaMirror(s.mdb, m)
+}
+
+// mirrorEntry mirrors media of entry stored, urls rewrote into entry once
+// all mirrored, or mirrored partly if final. Stored entry untouched until
+// then, so that original urls are mirrored again.
+func (s *ApiServer) mirrorEntry(id string, final bool) error {
+	entry, err := store.GetEntry(s.rdb, id)
+	if err != nil {
+		return err
+	}
+	if entry.Id == "" {
+		return nil // deleted
+	}
+
+	mirrored := proto.Clone(entry).(*pb.Entry)
+	err = s.mirrorMedia(s.fs, mirrored)
+	if err != nil && !final {
+		return err
+	}
+	if perr := s.rewriteMedia(mirrored); perr != nil {
+		return perr
+	}
+	return err
+}
+
+// rewriteMedia saves media urls of entry mirrored, entry reloaded so that
+// comments and likes meanwhile kept.
+func (s *ApiServer) rewriteMedia(mirrored *pb.Entry) error {
+	entry, err := store.GetEntry(s.rdb, mirrored.Id)
+	if err != nil {
+		return err
+	}
+	if entry.Id == "" {
+		// deleted while mirroring
+		return s.refMedia(mirrored.Id, true)
+	}
+	entry.Thumbnails = mirrored.Thumbnails
+	entry.Files = mirrored.Files
+	_, err = store.PutEntry(s.rdb, entry, true)
+	return err
+}
+
+// MediaMirrorTicker mirrors media once queued, retries periodically.
+func (s *ApiServer) MediaMirrorTicker() {
+	t := time.Tick(hubRetryDelay)
+	for {
+		select {
+		case <-t:
+		case <-s.mediaCh:
+		}
+		if _, err := s.MirrorPendingMedia(); err != nil {
+			log.Println("mirror media failed:", err)
+		}
+	}
+}
+
+// thumbOwner returns owner of media refs of thumbnails of blob of hash.
+func thumbOwner(hash string) string {
+	return "thumbs/" + hash
+}
+
+// mirrorThumbnail mirrors image of thumb, link first and url if link is not
+// an image of ff media. Thumbnails of image rewrote into thumb, returns
+// hashes of blobs mirrored, error if none mirrored of ff media.
+func (s *ApiServer) mirrorThumbnail(client media.Storage, thumb *pb.Thumbnail) ([]string, error) {
+	var hashes []string
+	var lastErr error
+	for _, src := range []string{thumb.Link, thumb.Url} {
+		if !media.IsMirrorable(src) {
+			continue
+		}
+		obj, err := client.FromUrl("", src, "")
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		hashes = append(hashes, obj.Hash)
+
+		blob, err := s.thumbnails(src, obj)
+		small, large := thumbOf(blob, media.ThumbSmall.Name), thumbOf(blob, media.ThumbLarge.Name)
+		if err != nil || small == nil {
+			// not an image, mirrored as is
+			if src == thumb.Url {
+				thumb.Url = obj.Url
+			}
+			continue
+		}
+		thumb.Url, thumb.Width, thumb.Height = small.Url, small.Width, small.Height
+		if src == thumb.Link && large != nil {
+			thumb.Link = large.Url
+		}
+		break
+	}
+	if len(hashes) == 0 {
+		return nil, lastErr
+	}
+	return hashes, nil
+}
+
+// thumbnails returns blob of obj mirrored from src with thumbnails, made once.
+func (s *ApiServer) thumbnails(src string, obj *media.Object) (*pb.MediaBlob, error) {
+	blob, err := store.GetMediaBlob(s.mdb, obj.Hash)
+	if err != nil {
+		return nil, err
+	}
+	if len(blob.Thumbs) > 0 {
+		return blob, nil
+	}
+
+	thumbs, err := s.thumbs.Thumbnails(&media.Object{Url: src, Content: obj.Content, Hash: obj.Hash})
+	if err != nil {
+		return nil, err
+	}
+	var mts []*pb.MediaThumb
+	var hashes []string
+	for _, t := range thumbs.Thumbs {
+		mts = append(mts, &pb.MediaThumb{
+			Size:   t.Size,
+			Url:    t.Url,
+			Width:  int32(t.Width),
+			Height: int32(t.Height),
+			Hash:   t.Hash,
+		})
+		// small images thumbnailed as the same content, never self referenced
+		if t.Hash != obj.Hash {
+			hashes = append(hashes, t.Hash)
+		}
+	}
+
+	s.mediaMu.Lock()
+	defer s.mediaMu.Unlock()
+	err = store.PutMediaThumbs(s.mdb, obj.Hash, int32(thumbs.Width), int32(thumbs.Height), mts)
+	if err != nil {
+		return nil, err
+	}
+	// thumbnails collected with blob
+	if err := store.SetMediaRefs(s.mdb, thumbOwner(obj.Hash), hashes...); err != nil {
+		return nil, err
+	}
+	return store.GetMediaBlob(s.mdb, obj.Hash)
+}
+
+func thumbOf(blob *pb.MediaBlob, size string) *pb.MediaThumb {
+	if blob == nil {
+		return nil
+	}
+	for _, t := range blob.Thumbs {
+		if t.Size == size {
+			return t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/yinhm/friendfeed/cassette"
+	"github.com/yinhm/friendfeed/media"
+	pb "github.com/yinhm/friendfeed/proto"
+	store "github.com/yinhm/friendfeed/storage"
+	"golang.org/x/net/context"
+)
+
+func TestMirrorMedia(t *testing.T) {
+	setup()
+	defer teardown()
+
+	Convey("Given entry of ff media, blobs referenced until entry deleted", t, func() {
+		ctx := context.Background()
+		root, err := ioutil.TempDir("", "media")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(root)
+
+		rec, err := cassette.New("../media/testdata/cassettes/fetch", cassette.Replay)
+		So(err, ShouldBeNil)
+		cs := media.NewContentStore(media.NewLocalStorage(&media.Config{LocalRoot: root}), &mediaIndex{srv})
+		cs.Fetcher = media.NewFetcher(rec.Client())
+		srv.fs = cs
+		thumbnailer := media.NewImageThumbnailer(cs)
+		thumbnailer.Fetcher = cs.Fetcher
+		srv.thumbs = thumbnailer
+
+		user := &pb.Profile{
+			Uuid: "c6f8dca854f011ddb489003048343a40",
+			Id:   "yinhm",
+			Name: "yinhm",
+			Type: "user",
+		}
+		So(store.UpdateProfile(srv.mdb, user), ShouldBeNil)
+
+		src := "http://m.friendfeed-media.com/46b97c2da4b7596dfb4f78613d65080cbdca2439"
+		entry := &pb.Entry{
+			Id:          "ab439960a83546c683fd989a40a68462",
+			Date:        "2015-04-09T07:40:22Z",
+			Body:        "picture",
+			From:        &pb.Feed{Id: user.Id, Name: user.Name, Type: user.Type},
+			ProfileUuid: user.Uuid,
+			Thumbnails:  []*pb.Thumbnail{{Url: src, Link: src}},
+		}
+		_, err = srv.PostEntry(ctx, entry)
+		So(err, ShouldBeNil)
+		So(srv.mirrorMedia(srv.fs, entry), ShouldBeNil)
+
+		hash, err := store.GetMediaHash(srv.mdb, src)
+		So(err, ShouldBeNil)
+		So(len(hash), ShouldEqual, 64)
+		blob, _ := store.GetMediaBlob(srv.mdb, hash)
+		So(blob.Refs, ShouldEqual, 1)
+		So(blob.MimeType, ShouldEqual, "image/png")
+
+		// thumbnails rewrote into entry
+		So(blob.Width, ShouldEqual, 4)
+		So(blob.Height, ShouldEqual, 3)
+		So(len(blob.Thumbs), ShouldEqual, 2)
+		small, large := thumbOf(blob, "small"), thumbOf(blob, "large")
+		thumb := entry.Thumbnails[0]
+		So(thumb.Url, ShouldEqual, small.Url)
+		So(thumb.Url, ShouldEqual, "/media/"+media.BlobPath(small.Hash))
+		So(thumb.Width, ShouldEqual, 4)
+		So(thumb.Height, ShouldEqual, 3)
+		So(thumb.Link, ShouldEqual, large.Url)
+		// image smaller than thumbnails, thumbnailed as itself
+		So(small.Hash, ShouldEqual, hash)
+		So(large.Hash, ShouldEqual, hash)
+
+		// mirrored again, counted once, thumbnails made once
+		thumb.Url, thumb.Link = src, src
+		So(srv.mirrorMedia(srv.fs, entry), ShouldBeNil)
+		blob, _ = store.GetMediaBlob(srv.mdb, hash)
+		So(blob.Refs, ShouldEqual, 1)
+		So(thumbOf(blob, "small").Url, ShouldEqual, small.Url)
+		So(thumb.Url, ShouldEqual, small.Url)
+
+		n, err := srv.CollectMedia(0)
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 0)
+
+		_, err = srv.DeleteEntry(ctx, &pb.EntryDeleteRequest{Entry: entry.Id, User: user.Id})
+		So(err, ShouldBeNil)
+		blob, _ = store.GetMediaBlob(srv.mdb, hash)
+		So(blob.Refs, ShouldEqual, 0)
+
+		// kept within grace period
+		n, err = srv.CollectMedia(mediaGrace)
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 0)
+
+		n, err = srv.CollectMedia(0)
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 1)
+		ok, _ := srv.fs.Exists(media.BlobPath(hash))
+		So(ok, ShouldBeFalse)
+		blob, _ = store.GetMediaBlob(srv.mdb, hash)
+		So(blob.Hash, ShouldEqual, "")
+		n, err = srv.CollectMedia(0)
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 0)
+	})
+}
+
+func pendingMirrors() map[string]*pb.MediaMirror {
+	pending := make(map[string]*pb.MediaMirror)
+	store.ForwardTableScan(srv.mdb, store.TableMediaMirror, func(i int, k, v []byte) error {
+		m := new(pb.MediaMirror)
+		if err := proto.Unmarshal(v, m); err != nil {
+			return err
+		}
+		pending[m.EntryId] = m
+		return nil
+	})
+	return pending
+}
+
+func TestMirrorQueue(t *testing.T) {
+	setup()
+	defer teardown()
+
+	Convey("Given entries queued, media mirrored into entries stored", t, func() {
+		root, err := ioutil.TempDir("", "media")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(root)
+
+		rec, err := cassette.New("../media/testdata/cassettes/fetch", cassette.Replay)
+		So(err, ShouldBeNil)
+		cs := media.NewContentStore(media.NewLocalStorage(&media.Config{LocalRoot: root}), &mediaIndex{srv})
+		cs.Fetcher = media.NewFetcher(rec.Client())
+		srv.fs = cs
+		thumbnailer := media.NewImageThumbnailer(cs)
+		thumbnailer.Fetcher = cs.Fetcher
+		srv.thumbs = thumbnailer
+
+		src := "http://m.friendfeed-media.com/46b97c2da4b7596dfb4f78613d65080cbdca2439"
+		missing := "http://m.friendfeed-media.com/0000000000000000000000000000000000000000"
+		newEntry := func(id string) *pb.Entry {
+			return &pb.Entry{
+				Id:          id,
+				Date:        "2015-04-09T07:40:22Z",
+				Body:        "picture",
+				From:        &pb.Feed{Id: "yinhm", Name: "yinhm", Type: "user"},
+				ProfileUuid: "c6f8dca854f011ddb489003048343a40",
+				Thumbnails:  []*pb.Thumbnail{{Url: src, Link: src}},
+			}
+		}
+		mirrored := newEntry("f4e9b1a6a83546c683fd989a40a68462")
+		failing := newEntry("0c7a2d52a83546c683fd989a40a68462")
+		failing.Files = []*pb.File{{Name: "a.pdf", Url: missing, Type: "application/pdf"}}
+		// media not of ff kept as is
+		external := "https://pbs.twimg.com/media/a.jpg"
+		mirrored.Files = []*pb.File{{Name: "a.jpg", Url: external, Type: "image/jpeg"}}
+		plain := newEntry("5d1e0f6ea83546c683fd989a40a68462")
+		plain.Thumbnails = nil
+		tweet := newEntry("9b3c7e10a83546c683fd989a40a68462")
+		tweet.Thumbnails = []*pb.Thumbnail{{Url: external, Link: external}}
+		for _, entry := range []*pb.Entry{mirrored, failing, plain, tweet} {
+			_, err := store.PutEntry(srv.rdb, entry, false)
+			So(err, ShouldBeNil)
+			So(srv.enqueMirror(entry), ShouldBeNil)
+		}
+		// entries without media of ff never queued
+		So(len(pendingMirrors()), ShouldEqual, 2)
+
+		n, err := srv.MirrorPendingMedia()
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 1)
+		entry, err := store.GetEntry(srv.rdb, mirrored.Id)
+		So(err, ShouldBeNil)
+		So(entry.Thumbnails[0].Url, ShouldStartWith, "/media/sha256/")
+		So(entry.Thumbnails[0].Link, ShouldStartWith, "/media/sha256/")
+		So(entry.Files[0].Url, ShouldEqual, external)
+
+		// failed mirroring retried later, entry untouched
+		pending := pendingMirrors()
+		So(len(pending), ShouldEqual, 1)
+		m := pending[failing.Id]
+		So(m.Attempts, ShouldEqual, 1)
+		So(m.Error, ShouldContainSubstring, "not recorded")
+		So(m.NextAttempt, ShouldBeGreaterThan, time.Now().Unix())
+		entry, _ = store.GetEntry(srv.rdb, failing.Id)
+		So(entry.Thumbnails[0].Url, ShouldEqual, src)
+
+		n, err = srv.MirrorPendingMedia()
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 0)
+		So(pendingMirrors()[failing.Id].Attempts, ShouldEqual, 1)
+
+		// given up, media mirrored partly kept
+		m.Attempts = hubMaxAttempts - 1
+		m.NextAttempt = time.Now().Unix()
+		So(store.PutMediaMirror(srv.mdb, m), ShouldBeNil)
+		n, err = srv.MirrorPendingMedia()
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 0)
+		So(len(pendingMirrors()), ShouldEqual, 0)
+		entry, _ = store.GetEntry(srv.rdb, failing.Id)
+		So(entry.Thumbnails[0].Url, ShouldStartWith, "/media/sha256/")
+		So(entry.Files[0].Url, ShouldEqual, missing)
+	})
+}
@@ -0,0 +1,452 @@
+package server
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/yinhm/friendfeed/importer"
+	pb "github.com/yinhm/friendfeed/proto"
+	store "github.com/yinhm/friendfeed/storage"
+	"github.com/yinhm/friendfeed/sup"
+	"github.com/yinhm/friendfeed/websub"
+	"golang.org/x/net/context"
+)
+
+// friendfeed sup feed, sup ids of friendfeed profiles are imported.
+const friendfeedSupUrl = "http://friendfeed.com/api/sup.json"
+
+// Subscribe subscribes to SUP or WebSub hub of the imported feed, a copy of
+// sub.Job is enqueued whenever the feed updated.
+func (s *ApiServer) Subscribe(ctx context.Context, sub *pb.Subscription) (*pb.Subscription, error) {
+	if sub.Topic == "" || sub.Job == nil {
+		return nil, fmt.Errorf("bad request")
+	}
+
+	old, err := store.GetPushSubscription(s.mdb, sub.Topic)
+	if err != nil {
+		return nil, err
+	}
+	// keep the latest update seen
+	sub.Updated = old.Updated
+	sub.Digest = old.Digest
+
+	if sub.Hub == "" {
+		// sup, nothing to verify
+		if _, supId := sup.SplitUrl(sub.Topic); supId == "" {
+			return nil, fmt.Errorf("bad request: sup id required")
+		}
+		sub.Verified = true
+		return sub, store.PutPushSubscription(s.mdb, sub)
+	}
+
+	if sub.Callback == "" {
+		return nil, fmt.Errorf("bad request: callback required")
+	}
+	if sub.Secret == "" {
+		sub.Secret = websub.NewSecret()
+	}
+	// renewed subscription kept verified until hub answers
+	sub.Verified = old.Verified && old.Callback == sub.Callback && old.Secret == sub.Secret
+	sub.PendingMode = websub.ModeSubscribe
+	sub.PendingLease = int64(websub.DefaultLease.Seconds())
+	if err := store.PutPushSubscription(s.mdb, sub); err != nil {
+		return nil, err
+	}
+	subscriber := websub.NewSubscriber()
+	err = subscriber.Subscribe(ctx, sub.Hub, sub.Topic, sub.Callback, sub.Secret, websub.DefaultLease)
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// unsubscribe unsubscribes from hub of topic, subscription deleted once hub
+// verified. Sup subscription deleted at once.
+func (s *ApiServer) unsubscribe(ctx context.Context, topic string) error {
+	sub, err := store.GetPushSubscription(s.mdb, topic)
+	if err != nil {
+		return err
+	}
+	if sub.Topic == "" {
+		return nil
+	}
+	if sub.Hub == "" {
+		return store.DeletePushSubscription(s.mdb, topic)
+	}
+	sub.PendingMode = websub.ModeUnsubscribe
+	sub.PendingLease = 0
+	if err := store.PutPushSubscription(s.mdb, sub); err != nil {
+		return err
+	}
+	return websub.NewSubscriber().Unsubscribe(ctx, sub.Hub, sub.Topic, sub.Callback)
+}
+
+// PushNotify handles requests from WebSub hubs: intent verification, or
+// content distribution which enqueues job if the content changed.
+func (s *ApiServer) PushNotify(ctx context.Context, req *pb.PushRequest) (*pb.PushResponse, error) {
+	sub, err := store.GetPushSubscription(s.mdb, req.Topic)
+	if err != nil {
+		return nil, err
+	}
+	if sub.Topic == "" || sub.Hub == "" {
+		return nil, fmt.Errorf("404")
+	}
+
+	switch req.Mode {
+	case websub.ModeSubscribe, websub.ModeUnsubscribe, websub.ModeDenied:
+		return s.verifyPush(sub, req)
+	case "":
+	default:
+		return nil, fmt.Errorf("bad request: unknown mode")
+	}
+
+	if !sub.Verified {
+		return nil, fmt.Errorf("403: subscription not verified")
+	}
+	if !websub.VerifySignature(sub.Secret, req.Signature, req.Body) {
+		return nil, fmt.Errorf("403: bad signature")
+	}
+	if sub.PendingMode == websub.ModeUnsubscribe {
+		// unsubscribing, content ignored
+		return new(pb.PushResponse), nil
+	}
+
+	sum := sha1.Sum(req.Body)
+	digest := hex.EncodeToString(sum[:])
+	if digest == sub.Digest {
+		// hubs may deliver the same content more than once
+		return new(pb.PushResponse), nil
+	}
+
+	sub.Digest = digest
+	sub.Updated = time.Now().Unix()
+	job, err := s.enqueSubscription(ctx, sub)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PushResponse{Job: job}, nil
+}
+
+// verifyPush confirms intent verification of hub, only the request pending
+// at the callback is confirmed, subscription left unchanged otherwise.
+func (s *ApiServer) verifyPush(sub *pb.Subscription, req *pb.PushRequest) (*pb.PushResponse, error) {
+	pending := req.Mode
+	if pending == websub.ModeDenied {
+		// hub denied subscribe request
+		pending = websub.ModeSubscribe
+	}
+	if sub.PendingMode == "" || sub.PendingMode != pending || req.Callback != sub.Callback {
+		return nil, fmt.Errorf("404")
+	}
+
+	if req.Mode != websub.ModeSubscribe {
+		if err := store.DeletePushSubscription(s.mdb, sub.Topic); err != nil {
+			return nil, err
+		}
+		return &pb.PushResponse{Challenge: req.Challenge}, nil
+	}
+
+	lease := req.LeaseSeconds
+	if lease <= 0 {
+		lease = sub.PendingLease
+	}
+	if lease > 0 {
+		sub.LeaseExpires = time.Now().Unix() + lease
+	}
+	sub.Verified = true
+	sub.PendingMode = ""
+	sub.PendingLease = 0
+	if err := store.PutPushSubscription(s.mdb, sub); err != nil {
+		return nil, err
+	}
+	return &pb.PushResponse{Challenge: req.Challenge}, nil
+}
+
+// enqueSubscription saves sub and enqueues a copy of its job.
+func (s *ApiServer) enqueSubscription(ctx context.Context, sub *pb.Subscription) (*pb.FeedJob, error) {
+	if err := store.PutPushSubscription(s.mdb, sub); err != nil {
+		return nil, err
+	}
+	job := proto.Clone(sub.Job).(*pb.FeedJob)
+	return s.EnqueJob(ctx, job)
+}
+
+// pushKey returns key of job in subscriptions pushed, see pushedJobs.
+func pushKey(job *pb.FeedJob) string {
+	if job.Service != nil {
+		return job.Uuid + "/" + serviceKey(job.Service)
+	}
+	return job.Uuid + "/" + job.Id
+}
+
+// pushedJobs returns keys of jobs with verified subscriptions not expired,
+// these feeds are updated by push instead of polling.
+func (s *ApiServer) pushedJobs() (map[string]bool, error) {
+	pushed := make(map[string]bool)
+	now := time.Now().Unix()
+	_, err := store.ForwardTableScan(s.mdb, store.TablePushSubscription, func(i int, k, v []byte) error {
+		sub := new(pb.Subscription)
+		if err := proto.Unmarshal(v, sub); err != nil {
+			return err
+		}
+		if sub.Job == nil || !sub.Verified || sub.PendingMode == websub.ModeUnsubscribe {
+			return nil
+		}
+		if sub.LeaseExpires > 0 && sub.LeaseExpires < now {
+			return nil
+		}
+		pushed[pushKey(sub.Job)] = true
+		return nil
+	})
+	return pushed, err
+}
+
+// PollSup polls sup feeds subscribed, enqueues jobs for feeds updated since
+// the last job enqueued.
+func (s *ApiServer) PollSup() (int, error) {
+	subs := make(map[string]map[string]*pb.Subscription) // sup url -> sup id
+	_, err := store.ForwardTableScan(s.mdb, store.TablePushSubscription, func(i int, k, v []byte) error {
+		sub := new(pb.Subscription)
+		if err := proto.Unmarshal(v, sub); err != nil {
+			return err
+		}
+		if sub.Hub != "" {
+			return nil
+		}
+		supUrl, supId := sup.SplitUrl(sub.Topic)
+		if subs[supUrl] == nil {
+			subs[supUrl] = make(map[string]*pb.Subscription)
+		}
+		subs[supUrl][supId] = sub
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	ctx := context.Background()
+	n := 0
+	for supUrl, ids := range subs {
+		updates, err := s.supPoller(supUrl).Poll(ctx)
+		if err != nil {
+			log.Printf("poll sup %s failed: %s", supUrl, err)
+			continue
+		}
+		for _, u := range updates {
+			sub, ok := ids[u.SupId]
+			if !ok || u.Updated.Unix() <= sub.Updated {
+				continue
+			}
+			sub.Updated = u.Updated.Unix()
+			if _, err := s.enqueSubscription(ctx, sub); err != nil {
+				return n, err
+			}
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *ApiServer) supPoller(supUrl string) *sup.Poller {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.pollers == nil {
+		s.pollers = make(map[string]*sup.Poller)
+	}
+	if _, ok := s.pollers[supUrl]; !ok {
+		s.pollers[supUrl] = sup.NewPoller(supUrl)
+	}
+	return s.pollers[supUrl]
+}
+
+// RenewSubscriptions resubscribes to hubs before leases expired.
+func (s *ApiServer) RenewSubscriptions() error {
+	var subs []*pb.Subscription
+	deadline := time.Now().Add(24 * time.Hour).Unix()
+	_, err := store.ForwardTableScan(s.mdb, store.TablePushSubscription, func(i int, k, v []byte) error {
+		sub := new(pb.Subscription)
+		if err := proto.Unmarshal(v, sub); err != nil {
+			return err
+		}
+		if sub.Hub != "" && sub.LeaseExpires > 0 && sub.LeaseExpires < deadline {
+			subs = append(subs, sub)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subs {
+		if _, err := s.Subscribe(context.Background(), sub); err != nil {
+			log.Printf("renew subscription %s failed: %s", sub.Topic, err)
+		}
+	}
+	return nil
+}
+
+// PushJobTicker polls sup feeds slightly faster than sup period.
+func (s *ApiServer) PushJobTicker() {
+	t := time.Tick(sup.DefaultPeriod * time.Second * 3 / 4)
+	renew := time.Tick(time.Hour)
+	for {
+		select {
+		case <-t:
+			n, err := s.PollSup()
+			if err != nil {
+				log.Println("poll sup failed:", err)
+			}
+			if n > 0 {
+				log.Printf("sup: %d updated feeds scheduled.", n)
+			}
+		case <-renew:
+			s.RenewSubscriptions()
+		}
+	}
+}
+
+// SubscribeFriendFeed subscribes to friendfeed sup of archived feeds,
+// instead of refetching every feed.
+func (s *ApiServer) SubscribeFriendFeed() error {
+	j := 0
+	var subs []*pb.Subscription
+	n, err := store.ForwardTableScan(s.mdb, store.TableProfile, func(i int, k, v []byte) error {
+		profile := &pb.Profile{}
+		if err := proto.Unmarshal(v, profile); err != nil {
+			return err
+		}
+		if profile.SupId == "" {
+			// not imported from friendfeed
+			return nil
+		}
+
+		job, err := s.friendfeedJob(profile)
+		if err != nil || job == nil {
+			return err
+		}
+		subs = append(subs, &pb.Subscription{
+			Topic: sup.Url(friendfeedSupUrl, profile.SupId),
+			Job:   job,
+		})
+		return nil
+	})
+	if err != nil {
+		log.Println("Error on scanning user profiles:", err)
+		return err
+	}
+
+	for _, sub := range subs {
+		if _, err := s.Subscribe(context.Background(), sub); err != nil {
+			return err
+		}
+		j++
+	}
+	log.Printf("Sup subscribed: %d scanned, %d friendfeed feeds subscribed.", n, j)
+	return nil
+}
+
+// subscribeService subscribes to websub hub advertised by feed of
+// job.Service at callback of base, or to sup feed advertised otherwise.
+// Feeds advertising neither are polled by jobs scheduled.
+func (s *ApiServer) subscribeService(ctx context.Context, job *pb.FeedJob, base string) error {
+	service := job.Service
+	if service == nil || service.Id != importer.FeedServiceId || service.Url == "" {
+		return nil
+	}
+	feed, err := importer.NewFeedImporter().Fetch(ctx, service.Url)
+	if err != nil {
+		return err
+	}
+	if feed.Hub != "" && base != "" {
+		topic := feed.Self
+		if topic == "" {
+			topic = service.Url
+		}
+		_, err = s.Subscribe(ctx, &pb.Subscription{
+			Topic:    topic,
+			Hub:      feed.Hub,
+			Callback: websub.CallbackURL(base, topic),
+			Job:      job,
+		})
+		return err
+	}
+	if _, supId := sup.SplitUrl(feed.Sup); supId != "" {
+		_, err = s.Subscribe(ctx, &pb.Subscription{
+			Topic: feed.Sup,
+			Job:   job,
+		})
+		return err
+	}
+	return nil
+}
+
+// unsubscribeService unsubscribes from hubs of service of user.
+func (s *ApiServer) unsubscribeService(ctx context.Context, uuid string, service *pb.Service) error {
+	var topics []string
+	_, err := store.ForwardTableScan(s.mdb, store.TablePushSubscription, func(i int, k, v []byte) error {
+		sub := new(pb.Subscription)
+		if err := proto.Unmarshal(v, sub); err != nil {
+			return err
+		}
+		job := sub.Job
+		if job != nil && job.Uuid == uuid && job.Service != nil &&
+			job.Service.Id == service.Id && job.Service.Url == service.Url {
+			topics = append(topics, sub.Topic)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, topic := range topics {
+		if err := s.unsubscribe(ctx, topic); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SubscribeServiceHubs subscribes to hubs or sup feeds advertised by feed
+// services imported, hubs at callback of base.
+func (s *ApiServer) SubscribeServiceHubs(base string) error {
+	j := 0
+	var jobs []*pb.FeedJob
+	n, err := store.ForwardTableScan(s.mdb, store.TableProfile, func(i int, k, v []byte) error {
+		profile := &pb.Profile{}
+		if err := proto.Unmarshal(v, profile); err != nil {
+			return err
+		}
+		feedinfo, _ := store.GetFeedinfo(s.rdb, profile.Uuid)
+		for _, service := range feedinfo.GetServices() {
+			if service.Id != importer.FeedServiceId {
+				continue
+			}
+			jobs = append(jobs, &pb.FeedJob{
+				Uuid:    profile.Uuid,
+				Id:      profile.Id,
+				Profile: profile,
+				Service: service,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		log.Println("Error on scanning user profiles:", err)
+		return err
+	}
+
+	for _, job := range jobs {
+		if err := s.subscribeService(context.Background(), job, base); err != nil {
+			log.Printf("subscribe hub of %s failed: %s", job.Service.Url, err)
+			continue
+		}
+		j++
+	}
+	log.Printf("Hubs subscribed: %d scanned, %d feed services checked.", n, j)
+	return nil
+}
@@ -0,0 +1,259 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	pb "github.com/yinhm/friendfeed/proto"
+	store "github.com/yinhm/friendfeed/storage"
+	"github.com/yinhm/friendfeed/sup"
+	"github.com/yinhm/friendfeed/websub"
+	"golang.org/x/net/context"
+)
+
+func TestWebSubSubscription(t *testing.T) {
+	setup()
+	defer teardown()
+
+	Convey("Given hub, subscribe and enqueue job on content changed", t, func() {
+		ctx := context.Background()
+
+		// stand-in hub accepts subscriptions
+		var form url.Values
+		hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.ParseForm()
+			form = r.PostForm
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer hub.Close()
+
+		topic := "http://blog.example.com/feed.atom"
+		callback := websub.CallbackURL("http://ff.example.com/push/callback", topic)
+		So(callback, ShouldEqual, "http://ff.example.com/push/callback?topic="+url.QueryEscape(topic))
+		sub, err := srv.Subscribe(ctx, &pb.Subscription{
+			Topic:    topic,
+			Hub:      hub.URL,
+			Callback: callback,
+			Job:      &pb.FeedJob{Id: "yinhm", TargetId: "yinhm"},
+		})
+		So(err, ShouldBeNil)
+		So(sub.Verified, ShouldBeFalse)
+		So(sub.Secret, ShouldNotEqual, "")
+		So(form.Get("hub.topic"), ShouldEqual, topic)
+		So(form.Get("hub.secret"), ShouldEqual, sub.Secret)
+		So(sub.PendingMode, ShouldEqual, websub.ModeSubscribe)
+
+		// content before verified
+		body := []byte("<feed>1</feed>")
+		req := &pb.PushRequest{Topic: topic, Body: body, Signature: websub.Sign(sub.Secret, body)}
+		_, err = srv.PushNotify(ctx, req)
+		So(err, ShouldNotBeNil)
+
+		_, err = srv.PushNotify(ctx, &pb.PushRequest{Topic: "http://unknown", Mode: "subscribe", Challenge: "x"})
+		So(err, ShouldNotBeNil)
+
+		// verification of other callback, or of request never sent
+		_, err = srv.PushNotify(ctx, &pb.PushRequest{Topic: topic, Mode: websub.ModeSubscribe, Challenge: "x", Callback: "http://evil.example.com/"})
+		So(err, ShouldNotBeNil)
+		_, err = srv.PushNotify(ctx, &pb.PushRequest{Topic: topic, Mode: websub.ModeUnsubscribe, Challenge: "x", Callback: callback})
+		So(err, ShouldNotBeNil)
+		old, _ := store.GetPushSubscription(srv.mdb, topic)
+		So(old.Topic, ShouldEqual, topic)
+		So(old.Verified, ShouldBeFalse)
+
+		resp, err := srv.PushNotify(ctx, &pb.PushRequest{
+			Topic:        topic,
+			Mode:         websub.ModeSubscribe,
+			Challenge:    "challenge",
+			LeaseSeconds: 3600,
+			Callback:     callback,
+		})
+		So(err, ShouldBeNil)
+		So(resp.Challenge, ShouldEqual, "challenge")
+		old, _ = store.GetPushSubscription(srv.mdb, topic)
+		So(old.PendingMode, ShouldEqual, "")
+
+		// verified once
+		_, err = srv.PushNotify(ctx, &pb.PushRequest{Topic: topic, Mode: websub.ModeSubscribe, Challenge: "x", Callback: callback})
+		So(err, ShouldNotBeNil)
+		_, err = srv.PushNotify(ctx, &pb.PushRequest{Topic: topic, Mode: websub.ModeDenied, Callback: callback})
+		So(err, ShouldNotBeNil)
+
+		resp, err = srv.PushNotify(ctx, req)
+		So(err, ShouldBeNil)
+		So(resp.Job, ShouldNotBeNil)
+		So(resp.Job.Id, ShouldEqual, "yinhm")
+
+		// same content delivered again
+		resp, err = srv.PushNotify(ctx, req)
+		So(err, ShouldBeNil)
+		So(resp.Job, ShouldBeNil)
+
+		// bad signature
+		body = []byte("<feed>2</feed>")
+		_, err = srv.PushNotify(ctx, &pb.PushRequest{Topic: topic, Body: body, Signature: websub.Sign("other", body)})
+		So(err, ShouldNotBeNil)
+
+		resp, err = srv.PushNotify(ctx, &pb.PushRequest{Topic: topic, Body: body, Signature: websub.Sign(sub.Secret, body)})
+		So(err, ShouldBeNil)
+		So(resp.Job, ShouldNotBeNil)
+
+		jobs, err := srv.ListJobQueue(store.TableJobFeed)
+		So(err, ShouldBeNil)
+		So(len(jobs), ShouldEqual, 2)
+
+		So(srv.unsubscribe(ctx, topic), ShouldBeNil)
+		So(form.Get("hub.mode"), ShouldEqual, websub.ModeUnsubscribe)
+		So(form.Get("hub.callback"), ShouldEqual, callback)
+		_, err = srv.PushNotify(ctx, &pb.PushRequest{Topic: topic, Mode: websub.ModeUnsubscribe, Challenge: "bye", Callback: callback})
+		So(err, ShouldBeNil)
+		old, err = store.GetPushSubscription(srv.mdb, topic)
+		So(err, ShouldBeNil)
+		So(old.Topic, ShouldEqual, "")
+	})
+}
+
+func TestSupSubscription(t *testing.T) {
+	setup()
+	defer teardown()
+
+	Convey("Given sup publisher, enqueue jobs only for feeds updated", t, func() {
+		ctx := context.Background()
+
+		now := time.Now()
+		updates := []sup.Update{
+			{SupId: sup.NewId("ana"), Updated: now.Add(-30 * time.Second)},
+			{SupId: sup.NewId("bret"), Updated: now.Add(-20 * time.Second)},
+		}
+		publisher := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(sup.NewFeed(now, sup.DefaultPeriod, updates))
+		}))
+		defer publisher.Close()
+		supUrl := publisher.URL + "/sup.json"
+
+		_, err := srv.Subscribe(ctx, &pb.Subscription{Topic: supUrl})
+		So(err, ShouldNotBeNil)
+
+		// ana seen already
+		_, err = srv.Subscribe(ctx, &pb.Subscription{
+			Topic: sup.Url(supUrl, sup.NewId("ana")),
+			Job:   &pb.FeedJob{Id: "ana", TargetId: "ana"},
+		})
+		So(err, ShouldBeNil)
+		sub, _ := store.GetPushSubscription(srv.mdb, sup.Url(supUrl, sup.NewId("ana")))
+		sub.Updated = now.Unix()
+		So(store.PutPushSubscription(srv.mdb, sub), ShouldBeNil)
+
+		_, err = srv.Subscribe(ctx, &pb.Subscription{
+			Topic: sup.Url(supUrl, sup.NewId("casey")),
+			Job:   &pb.FeedJob{Id: "casey", TargetId: "casey"},
+		})
+		So(err, ShouldBeNil)
+
+		n, err := srv.PollSup()
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 0)
+
+		updates = append(updates, sup.Update{SupId: sup.NewId("casey"), Updated: now.Add(-10 * time.Second)})
+		n, err = srv.PollSup()
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 1)
+
+		n, err = srv.PollSup()
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 0)
+
+		jobs, err := srv.ListJobQueue(store.TableJobFeed)
+		So(err, ShouldBeNil)
+		So(len(jobs), ShouldEqual, 1)
+		So(jobs[0].Id, ShouldEqual, "casey")
+	})
+}
+
+func TestServiceHub(t *testing.T) {
+	setup()
+	defer teardown()
+
+	Convey("Given feed advertising hub or sup, feed service subscribed", t, func() {
+		ctx := context.Background()
+
+		var form url.Values
+		mux := http.NewServeMux()
+		ts := httptest.NewServer(mux)
+		defer ts.Close()
+		topic := ts.URL + "/feed.atom"
+		mux.HandleFunc("/feed.atom", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `<feed xmlns="http://www.w3.org/2005/Atom"><title>Blog</title>
+				<link rel="hub" href="/hub"/><link rel="self" href="%s"/></feed>`, topic)
+		})
+		mux.HandleFunc("/plain.atom", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `<feed xmlns="http://www.w3.org/2005/Atom"><title>Plain</title></feed>`)
+		})
+		mux.HandleFunc("/sup.atom", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(sup.Header, "/sup.json#4ceb94af")
+			fmt.Fprint(w, `<feed xmlns="http://www.w3.org/2005/Atom"><title>Sup</title></feed>`)
+		})
+		mux.HandleFunc("/hub", func(w http.ResponseWriter, r *http.Request) {
+			r.ParseForm()
+			form = r.PostForm
+			w.WriteHeader(http.StatusAccepted)
+		})
+
+		base := "http://ff.example.com/push/callback"
+		service := &pb.Service{Id: "feed", Url: ts.URL + "/feed.atom?utm=1"}
+		job := &pb.FeedJob{Uuid: "c6f8dca854f011ddb489003048343a40", Id: "yinhm", Service: service}
+		So(srv.subscribeService(ctx, job, base), ShouldBeNil)
+		So(form.Get("hub.mode"), ShouldEqual, websub.ModeSubscribe)
+		So(form.Get("hub.topic"), ShouldEqual, topic)
+
+		sub, err := store.GetPushSubscription(srv.mdb, topic)
+		So(err, ShouldBeNil)
+		So(sub.Hub, ShouldEqual, ts.URL+"/hub")
+		So(sub.Callback, ShouldEqual, websub.CallbackURL(base, topic))
+		So(sub.PendingMode, ShouldEqual, websub.ModeSubscribe)
+		So(sub.Job.Service.Url, ShouldEqual, service.Url)
+
+		// no hub advertised, polled by jobs
+		form = nil
+		plain := &pb.FeedJob{Uuid: job.Uuid, Id: job.Id, Service: &pb.Service{Id: "feed", Url: ts.URL + "/plain.atom"}}
+		So(srv.subscribeService(ctx, plain, base), ShouldBeNil)
+		So(form, ShouldBeNil)
+
+		// sup advertised by header, verified at once
+		supService := &pb.Service{Id: "feed", Url: ts.URL + "/sup.atom"}
+		supJob := &pb.FeedJob{Uuid: job.Uuid, Id: job.Id, Service: supService}
+		So(srv.subscribeService(ctx, supJob, base), ShouldBeNil)
+		So(form, ShouldBeNil)
+		sub, err = store.GetPushSubscription(srv.mdb, ts.URL+"/sup.json#4ceb94af")
+		So(err, ShouldBeNil)
+		So(sub.Verified, ShouldBeTrue)
+		So(sub.Job.Service.Url, ShouldEqual, supService.Url)
+
+		// services pushed left to subscriptions, hub not verified yet
+		pushed, err := srv.pushedJobs()
+		So(err, ShouldBeNil)
+		So(pushed, ShouldContainKey, pushKey(supJob))
+		So(pushed, ShouldNotContainKey, pushKey(job))
+		profile := &pb.Profile{Uuid: job.Uuid, Id: job.Id}
+		feedinfo := &pb.Feedinfo{Services: []*pb.Service{service, supService}}
+		n, err := srv.scheduleServices(profile, feedinfo, pushed, false)
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 1)
+		queued, err := srv.GetFeedJob(ctx, &pb.Worker{Id: "w1"})
+		So(err, ShouldBeNil)
+		So(queued.Service.Url, ShouldEqual, service.Url)
+
+		So(srv.unsubscribeService(ctx, "other", service), ShouldBeNil)
+		So(form, ShouldBeNil)
+		So(srv.unsubscribeService(ctx, job.Uuid, service), ShouldBeNil)
+		So(form.Get("hub.mode"), ShouldEqual, websub.ModeUnsubscribe)
+		sub, _ = store.GetPushSubscription(srv.mdb, topic)
+		So(sub.PendingMode, ShouldEqual, websub.ModeUnsubscribe)
+	})
+}
@@ -5,15 +5,17 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"os"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/golang/protobuf/proto"
 	uuid "github.com/satori/go.uuid"
+	"github.com/yinhm/friendfeed/activitypub"
 	"github.com/yinhm/friendfeed/media"
 	pb "github.com/yinhm/friendfeed/proto"
 	store "github.com/yinhm/friendfeed/storage"
+	"github.com/yinhm/friendfeed/sup"
 	"golang.org/x/net/context"
 )
 
@@ -25,11 +27,32 @@ type ApiServer struct {
 	mdb *store.Store
 	// block database
 	rdb *store.Store
-	// file system
+	// file system, media kept by content hash
 	fs media.Storage
+	// makes thumbnails of images mirrored
+	thumbs media.Thumbnailer
+	// serializes refs of media blobs
+	mediaMu sync.Mutex
+	// held by mirroring, locked by collecting orphaned media
+	mediaGC sync.RWMutex
+	// serializes generating activitypub keys
+	keyMu sync.Mutex
+	// hidden lists of users by uuid, dropped once changed
+	hiddenMu sync.Mutex
+	hidden   map[string]*store.HiddenList
 
 	// cached feed
 	cached map[string]*FeedIndex
+	// sup feeds subscribed, by sup url
+	pollers map[string]*sup.Poller
+	// wakes up hub delivery
+	hubCh chan struct{}
+	// wakes up activitypub delivery
+	apCh chan struct{}
+	// wakes up media mirroring
+	mediaCh chan struct{}
+	// last job scheduled of user services, by uuid and service key
+	scheduled map[string]time.Time
 }
 
 func NewApiServer(dbpath, mediaConfigFile string) *ApiServer {
@@ -41,21 +64,26 @@ func NewApiServer(dbpath, mediaConfigFile string) *ApiServer {
 	cached["public"].load(mdb)
 
 	srv := &ApiServer{
-		mdb:    mdb,
-		rdb:    rdb,
-		cached: cached,
+		mdb:     mdb,
+		rdb:     rdb,
+		cached:  cached,
+		hubCh:   make(chan struct{}, 1),
+		apCh:    make(chan struct{}, 1),
+		mediaCh: make(chan struct{}, 1),
 	}
 
 	config, err := media.NewConfigFromJSON(mediaConfigFile)
 	if err != nil {
 		log.Fatal("no config file")
 	}
-	// TODO: fix lazy hack for local dev.
-	// if no key file then go google storage.
-	if _, err := os.Stat(config.KeyFile); err == nil {
-		srv.fs = media.NewGoogleStorage(config)
-	} else {
-		srv.fs = media.NewLocalStorage(config)
+	backend, err := media.NewStorage(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv.fs = media.NewContentStore(backend, &mediaIndex{srv})
+	srv.thumbs, err = media.NewThumbnailer(config, srv.fs)
+	if err != nil {
+		log.Fatal(err)
 	}
 
 	return srv
@@ -77,6 +105,13 @@ func (s *ApiServer) Shutdown() {
 }
 
 func (s *ApiServer) FetchFeedinfo(ctx context.Context, req *pb.ProfileRequest) (*pb.Feedinfo, error) {
+	if req.Uuid == "" && req.Id != "" {
+		profile, err := store.GetProfile(s.mdb, req.Id)
+		if err != nil {
+			return nil, err
+		}
+		req.Uuid = profile.Uuid
+	}
 	if req.Uuid == "" {
 		return nil, fmt.Errorf("bad request")
 	}
@@ -165,11 +200,16 @@ func (s *ApiServer) ArchiveProfilePicture(id string) string {
 		return ""
 	}
 
+	s.mediaGC.RLock()
+	defer s.mediaGC.RUnlock()
 	newObj, err := s.fs.FromUrl("", picUrl, "")
 	if err != nil {
 		log.Println("Mirror media failed:", err)
 		return picUrl
 	}
+	if err := s.refMedia(pictureOwner(id), true, newObj.Hash); err != nil {
+		log.Println("ref media failed:", err)
+	}
 	return newObj.Url
 }
 
@@ -199,7 +239,11 @@ func (s *ApiServer) ArchiveFeed(stream pb.Api_ArchiveFeedServer) error {
 		key, err := store.PutEntry(s.rdb, entry, false) // always use false
 		if err == nil {
 			// no error or new key
-			s.spread(key)
+			s.spread(key, entry)
+			// entries stored keep mirrored urls, mirrored once
+			if err := s.enqueMirror(entry); err != nil {
+				log.Println("enque media mirroring failed:", err)
+			}
 		}
 		// Retuen if not force update and all entries are exists
 		// TODO: client dead lock???
@@ -216,7 +260,6 @@ func (s *ApiServer) ArchiveFeed(stream pb.Api_ArchiveFeedServer) error {
 			log.Println("db error:", err)
 		}
 
-		go s.mirrorMedia(s.fs, entry)
 		if lastEntry == nil {
 			dateEnd = entry.Date
 		}
@@ -252,7 +295,7 @@ func (s *ApiServer) ForceArchiveFeed(stream pb.Api_ForceArchiveFeedServer) error
 		key, err := store.PutEntry(s.rdb, entry, true)
 		if err != nil {
 			log.Println("db error:", err)
-		} else {
+		} else if activitypub.IsPublic(entry) {
 			s.cached["public"].Push(key.String())
 		}
 
@@ -265,31 +308,43 @@ func (s *ApiServer) ForceArchiveFeed(stream pb.Api_ForceArchiveFeedServer) error
 }
 
 func (s *ApiServer) mirrorMedia(client media.Storage, entry *pb.Entry) error {
+	s.mediaGC.RLock()
+	defer s.mediaGC.RUnlock()
+
+	// blobs referenced by entry
+	var hashes []string
 	// twitpic should be fine, see: http://blog.twitpic.com/2014/10/twitpics-future/
+	// the first failure, media mirrored are referenced anyway
+	var mirrorErr error
 	for _, thumb := range entry.Thumbnails {
-		newObj, err := client.FromUrl("", thumb.Url, "")
-		if err != nil {
-			// log.Println("Mirror media failed:", err)
-			continue
-		}
-		thumb.Url = newObj.Url // rewrote to mirrored
-
-		newObj, err = client.FromUrl("", thumb.Link, "")
-		if err != nil {
-			// log.Println("Mirror media failed:", err)
-			continue
+		mirrored, err := s.mirrorThumbnail(client, thumb)
+		if err != nil && mirrorErr == nil {
+			mirrorErr = err
 		}
+		hashes = append(hashes, mirrored...)
 	}
 
 	for _, file := range entry.Files {
+		if !media.IsMirrorable(file.Url) {
+			// kept as is, never retried
+			continue
+		}
 		newObj, err := client.FromUrl(file.Name, file.Url, file.Type)
 		if err != nil {
-			// log.Println("Mirror media failed:", err)
+			if mirrorErr == nil {
+				mirrorErr = err
+			}
 			continue
 		}
 		file.Url = newObj.Url // rewrote to mirrored
+		hashes = append(hashes, newObj.Hash)
 	}
-	return nil
+	if len(hashes) > 0 {
+		if err := s.refMedia(entry.Id, false, hashes...); err != nil {
+			return err
+		}
+	}
+	return mirrorErr
 }
 
 func (s *ApiServer) FetchFeed(ctx context.Context, req *pb.FeedRequest) (*pb.Feed, error) {
@@ -307,13 +362,19 @@ func (s *ApiServer) cachedFeed(req *pb.FeedRequest) (*pb.Feed, error) {
 		req.PageSize = 50
 	}
 
+	// direct messages never pushed into cached feeds
+	filter, err := s.newEntryFilter(req, false)
+	if err != nil {
+		return nil, err
+	}
+
 	start := req.Start
 	index := s.cached[req.Id]
 
 	var entries []*pb.Entry
 	found := 0
 	for i := 0; i < len(index.bufq); i++ {
-		if start > 0 {
+		if start > 0 && filter == nil {
 			start--
 			continue
 		}
@@ -332,6 +393,13 @@ func (s *ApiServer) cachedFeed(req *pb.FeedRequest) (*pb.Feed, error) {
 		if err := proto.Unmarshal(rawdata, entry); err != nil {
 			return nil, err
 		}
+		if filter.skip(entry) {
+			continue
+		}
+		if start > 0 {
+			start--
+			continue
+		}
 		FormatFeedEntry(s.mdb, req, entry)
 		entries = append(entries, entry)
 		found++
@@ -346,7 +414,7 @@ func (s *ApiServer) cachedFeed(req *pb.FeedRequest) (*pb.Feed, error) {
 		Name:    "Everyone's feed",
 		Type:    "group",
 		Private: false,
-		SupId:   "0000-00",
+		SupId:   sup.NewId("public"),
 		Entries: entries[:],
 	}
 	return feed, nil
@@ -365,11 +433,22 @@ func (s *ApiServer) ForwardFetchFeed(ctx context.Context, req *pb.FeedRequest) (
 	preKey := store.NewUUIDKey(store.TableReverseEntryIndex, uuid1)
 	log.Println("forward seeking:", preKey.String())
 
+	restricted, err := store.IsRestrictedFeed(s.rdb, uuid1)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := s.newEntryFilter(req, restricted)
+	if err != nil {
+		return nil, err
+	}
+
 	start := req.Start
+	n := 0 // visible entries seen
 	var entries []*pb.Entry
 	_, err = store.ForwardTableScan(s.rdb, preKey, func(i int, k, v []byte) error {
-		if start > 0 {
+		if start > 0 && filter == nil {
 			start--
+			n++
 			return nil // continue
 		}
 
@@ -381,12 +460,20 @@ func (s *ApiServer) ForwardFetchFeed(ctx context.Context, req *pb.FeedRequest) (
 		if err := proto.Unmarshal(rawdata, entry); err != nil {
 			return err
 		}
+		if filter.skip(entry) {
+			return nil
+		}
+		n++
+		if start > 0 {
+			start--
+			return nil
+		}
 		if err = FormatFeedEntry(s.mdb, req, entry); err != nil {
 			return err
 		}
 
 		entries = append(entries, entry)
-		if i > int(req.PageSize+req.Start) {
+		if n > int(req.PageSize+req.Start)+1 {
 			return &store.Error{"ok", store.StopIteration}
 		}
 		return nil
@@ -403,19 +490,136 @@ func (s *ApiServer) ForwardFetchFeed(ctx context.Context, req *pb.FeedRequest) (
 		Picture:     profile.Picture,
 		Type:        profile.Type,
 		Private:     profile.Private,
-		SupId:       profile.SupId,
+		SupId:       supId(profile),
 		Description: profile.Description,
 		Entries:     entries[:],
 	}
 	return feed, nil
 }
 
+// entryFilter excludes entries hidden by the requesting user, not matching
+// search query, or not visible to the requesting user.
+type entryFilter struct {
+	req    *pb.FeedRequest
+	hidden *store.HiddenList
+	terms  []string
+	// feed indexes entries visible to recipients only
+	restricted bool
+}
+
+// newEntryFilter returns nil if there is nothing to filter.
+func (s *ApiServer) newEntryFilter(req *pb.FeedRequest, restricted bool) (*entryFilter, error) {
+	f := &entryFilter{
+		req:        req,
+		terms:      strings.Fields(strings.ToLower(req.Query)),
+		restricted: restricted,
+	}
+	if req.User != "" {
+		uuid1, err := uuid.FromString(req.User)
+		if err != nil {
+			return nil, err
+		}
+		hidden, err := s.hiddenList(uuid1)
+		if err != nil {
+			return nil, err
+		}
+		if !hidden.Empty() {
+			f.hidden = hidden
+		}
+	}
+	if f.hidden == nil && len(f.terms) == 0 && !restricted {
+		return nil, nil
+	}
+	return f, nil
+}
+
+// hiddenList returns hidden list of user, loaded once until changed.
+func (s *ApiServer) hiddenList(uuid1 uuid.UUID) (*store.HiddenList, error) {
+	s.hiddenMu.Lock()
+	defer s.hiddenMu.Unlock()
+
+	if h, ok := s.hidden[uuid1.String()]; ok {
+		return h, nil
+	}
+	h, err := store.GetHiddenList(s.mdb, uuid1)
+	if err != nil {
+		return nil, err
+	}
+	if s.hidden == nil {
+		s.hidden = make(map[string]*store.HiddenList)
+	}
+	s.hidden[uuid1.String()] = h
+	return h, nil
+}
+
+// dropHidden drops hidden list of user cached.
+func (s *ApiServer) dropHidden(uuid1 uuid.UUID) {
+	s.hiddenMu.Lock()
+	defer s.hiddenMu.Unlock()
+	delete(s.hidden, uuid1.String())
+}
+
+// skip marks hidden entry, returns true if it should be excluded from the
+// response.
+func (f *entryFilter) skip(entry *pb.Entry) bool {
+	if f == nil {
+		return false
+	}
+	if f.restricted && !visible(entry, f.req.Id, f.req.User) {
+		return true
+	}
+	if len(f.terms) > 0 {
+		body := strings.ToLower(entry.RawBody)
+		if body == "" {
+			body = strings.ToLower(entry.Body)
+		}
+		for _, term := range f.terms {
+			if !strings.Contains(body, term) {
+				return true
+			}
+		}
+	}
+	if f.hidden == nil || !f.hidden.Contains(entry) {
+		return false
+	}
+	entry.Hidden = true
+	return !f.req.Hidden
+}
+
+// visible reports whether entry read from feed of feedId is visible to user
+// of uuid. Direct messages and entries to private groups are visible to the
+// author and recipients only, or readers of the group feed.
+func visible(entry *pb.Entry, feedId, user string) bool {
+	if activitypub.IsPublic(entry) {
+		return true
+	}
+	if user != "" && user == entry.ProfileUuid {
+		return true
+	}
+	for _, to := range entry.To {
+		if to == nil {
+			continue
+		}
+		if user != "" && to.Uuid == user {
+			return true
+		}
+		// private groups readable checked by feed
+		if to.Type == "group" && to.Id == feedId {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *ApiServer) FetchEntry(ctx context.Context, req *pb.EntryRequest) (*pb.Feed, error) {
 	entry, err := store.GetEntry(s.rdb, req.Uuid)
 	if err != nil {
 		return nil, err
 	}
-	err = fmtEntryProfile(s.mdb, entry)
+	if entry.Id == "" {
+		return nil, fmt.Errorf("404") // deleted
+	}
+	err = FormatEntry(s.mdb, req.MaxComments, req.MaxLikes, entry)
 	if err != nil {
 		return nil, err
 	}
@@ -434,7 +638,7 @@ func (s *ApiServer) FetchEntry(ctx context.Context, req *pb.EntryRequest) (*pb.F
 		Name:        profile.Name,
 		Type:        profile.Type,
 		Private:     profile.Private,
-		SupId:       profile.SupId,
+		SupId:       supId(profile),
 		Description: profile.Description,
 		Entries:     []*pb.Entry{entry},
 	}
@@ -442,14 +646,50 @@ func (s *ApiServer) FetchEntry(ctx context.Context, req *pb.EntryRequest) (*pb.F
 }
 
 func (s *ApiServer) PostEntry(ctx context.Context, entry *pb.Entry) (*pb.Entry, error) {
+	to, err := s.resolveTargets(entry)
+	if err != nil {
+		return nil, err
+	}
+	entry.To = to
+
 	key, err := store.PutEntry(s.rdb, entry, false) // always use false
 	if err != nil {
 		return nil, err
 	}
-	s.spread(key)
+	s.spread(key, entry)
+	s.federate("Create", entry)
 	return entry, nil
 }
 
+// resolveTargets fills target feeds of a cross posted entry from profiles,
+// duplicated targets and the author's own feed are dropped.
+func (s *ApiServer) resolveTargets(entry *pb.Entry) ([]*pb.Feed, error) {
+	var feeds []*pb.Feed
+	seen := map[string]bool{entry.ProfileUuid: true}
+	for _, to := range entry.To {
+		if to == nil || to.Id == "" {
+			continue
+		}
+		profile, err := store.GetProfile(s.mdb, to.Id)
+		if err != nil {
+			return nil, err
+		}
+		if seen[profile.Uuid] {
+			continue
+		}
+		seen[profile.Uuid] = true
+
+		feeds = append(feeds, &pb.Feed{
+			Uuid:    profile.Uuid,
+			Id:      profile.Id,
+			Name:    profile.Name,
+			Type:    profile.Type,
+			Private: profile.Private,
+		})
+	}
+	return feeds, nil
+}
+
 func (s *ApiServer) LikeEntry(ctx context.Context, req *pb.LikeRequest) (*pb.Entry, error) {
 	entry, err := store.GetEntry(s.rdb, req.Entry)
 	if err != nil {
@@ -469,7 +709,7 @@ func (s *ApiServer) LikeEntry(ctx context.Context, req *pb.LikeRequest) (*pb.Ent
 		var key *store.UUIDKey
 		key, entry, err = store.Like(s.rdb, profile, entry)
 		if err == nil {
-			s.spread(key)
+			s.spread(key, entry)
 		}
 	} else {
 		entry, err = store.DeleteLike(s.rdb, profile, entry)
@@ -482,6 +722,9 @@ func (s *ApiServer) CommentEntry(ctx context.Context, req *pb.CommentRequest) (*
 	if err != nil {
 		return nil, err
 	}
+	if entry.Id == "" {
+		return nil, fmt.Errorf("404")
+	}
 
 	profile, err := store.GetProfile(s.mdb, req.Comment.From.Id)
 	if err != nil || profile == nil {
@@ -492,7 +735,7 @@ func (s *ApiServer) CommentEntry(ctx context.Context, req *pb.CommentRequest) (*
 	if err != nil {
 		return nil, err
 	}
-	s.spread(key)
+	s.spread(key, entry)
 	return entry, nil
 }
 
@@ -510,9 +753,198 @@ func (s *ApiServer) DeleteComment(ctx context.Context, req *pb.CommentDeleteRequ
 	return store.DeleteComment(s.rdb, profile, entry, req.Comment)
 }
 
-func (s *ApiServer) spread(key *store.UUIDKey) {
-	if key != nil {
+// EditEntry edits entry of author, admins of groups only delete entries.
+func (s *ApiServer) EditEntry(ctx context.Context, req *pb.EntryEditRequest) (*pb.Entry, error) {
+	entry, err := s.writableEntry(req.Entry, req.User, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// keep previous version
+	if err := store.PutEntryHistory(s.rdb, entry); err != nil {
+		return nil, err
+	}
+	entry.Body = req.Body
+	entry.RawBody = req.RawBody
+	if _, err := store.PutEntry(s.rdb, entry, true); err != nil {
+		return nil, err
+	}
+	s.publishHub(entryFeedIds(entry)...)
+	s.federate("Update", entry)
+	return entry, nil
+}
+
+func (s *ApiServer) DeleteEntry(ctx context.Context, req *pb.EntryDeleteRequest) (*pb.Entry, error) {
+	entry, err := s.writableEntry(req.Entry, req.User, true)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := store.DeleteEntry(s.rdb, entry)
+	if err != nil {
+		return nil, err
+	}
+	// media of entry collected once unreferenced
+	if err := store.DeleteMediaMirror(s.mdb, entry.Id); err != nil {
+		log.Println("unqueue media mirroring failed:", err)
+	}
+	if err := s.refMedia(entry.Id, true); err != nil {
+		log.Println("unref media failed:", err)
+	}
+	s.cached["public"].Remove(key.String())
+	s.publishHub(entryFeedIds(entry)...)
+	s.federate("Delete", entry)
+	return entry, nil
+}
+
+func (s *ApiServer) HideEntry(ctx context.Context, req *pb.HideRequest) (*pb.Entry, error) {
+	uuid1, err := uuid.FromString(req.User)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := store.GetEntry(s.rdb, req.Target)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Id == "" {
+		return nil, fmt.Errorf("404")
+	}
+
+	if err := store.HideEntry(s.mdb, uuid1, entry.Id, req.Hide); err != nil {
+		return nil, err
+	}
+	s.dropHidden(uuid1)
+	entry.Hidden = req.Hide
+	return entry, nil
+}
+
+func (s *ApiServer) HideAuthor(ctx context.Context, req *pb.HideRequest) (*pb.Profile, error) {
+	uuid1, err := uuid.FromString(req.User)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := store.GetProfile(s.mdb, req.Target)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, fmt.Errorf("404")
+	}
+
+	if err := store.HideAuthor(s.mdb, uuid1, profile.Id, req.Hide); err != nil {
+		return nil, err
+	}
+	s.dropHidden(uuid1)
+	return profile, nil
+}
+
+// writableEntry returns entry if user is the author, or if admins allowed,
+// admin of any group the entry posted to.
+func (s *ApiServer) writableEntry(entryId, userId string, admins bool) (*pb.Entry, error) {
+	entry, err := store.GetEntry(s.rdb, entryId)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Id == "" {
+		return nil, fmt.Errorf("404")
+	}
+
+	profile, err := store.GetProfile(s.mdb, userId)
+	if err != nil {
+		return nil, err
+	}
+	if profile.Uuid == entry.ProfileUuid {
+		return entry, nil
+	}
+	if !admins {
+		return nil, fmt.Errorf("403: perm error")
+	}
+
+	for _, to := range entry.To {
+		if to.Type != "group" || to.Uuid == "" {
+			continue
+		}
+		feedinfo, err := store.GetFeedinfo(s.rdb, to.Uuid)
+		if err != nil {
+			return nil, err
+		}
+		for _, admin := range feedinfo.Admins {
+			if admin.Id == profile.Id {
+				return entry, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("403: perm error")
+}
+
+// spread publishes entry updated to the public feed, sup and hub, direct
+// messages and entries to private groups never published.
+func (s *ApiServer) spread(key *store.UUIDKey, entry *pb.Entry) {
+	if key != nil && activitypub.IsPublic(entry) {
 		s.cached["public"].Push(key.String())
+		// public feed updated, uuid.Nil stands for public
+		if err := store.PutSupUpdate(s.rdb, uuid.Nil); err != nil {
+			log.Println("sup update failed:", err)
+		}
+		s.publishHub(entryFeedIds(entry)...)
 	}
 	// TODO: spread to friends?
 }
+
+// entryFeedIds returns ids of feeds entry appears in publicly, none of
+// direct messages and entries to private groups.
+func entryFeedIds(entry *pb.Entry) []string {
+	if !activitypub.IsPublic(entry) {
+		return nil
+	}
+	ids := []string{"public"}
+	if entry.From != nil && entry.From.Id != "" {
+		ids = append(ids, entry.From.Id)
+	}
+	for _, to := range entry.To {
+		if to.Id != "" && (entry.From == nil || to.Id != entry.From.Id) {
+			ids = append(ids, to.Id)
+		}
+	}
+	return ids
+}
+
+// supId returns sup id of profile, generated if not imported from friendfeed
+// nor assigned by AssignSupIds yet.
+func supId(profile *pb.Profile) string {
+	if profile.SupId != "" {
+		return profile.SupId
+	}
+	return sup.NewId(profile.Id)
+}
+
+// FetchSupUpdates returns sup ids of feeds updated since req.Since.
+func (s *ApiServer) FetchSupUpdates(ctx context.Context, req *pb.SupRequest) (*pb.SupResponse, error) {
+	updates, err := store.GetSupUpdates(s.rdb, time.Unix(req.Since, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[uuid.UUID]string)
+	resp := new(pb.SupResponse)
+	for _, u := range updates {
+		id, ok := ids[u.Uuid]
+		if !ok {
+			if uuid.Equal(u.Uuid, uuid.Nil) {
+				id = sup.NewId("public")
+			} else if profile, err := store.GetProfileFromUuid(s.mdb, u.Uuid); err == nil && profile.Id != "" && !profile.Private {
+				// activity of private feeds never published
+				id = supId(profile)
+			}
+			ids[u.Uuid] = id
+		}
+		if id == "" {
+			continue
+		}
+		resp.Updates = append(resp.Updates, &pb.SupUpdate{
+			SupId:   id,
+			Updated: u.Updated.Unix(),
+		})
+	}
+	return resp, nil
+}
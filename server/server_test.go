@@ -1,6 +1,7 @@
 package server
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 	pb "github.com/yinhm/friendfeed/proto"
 	store "github.com/yinhm/friendfeed/storage"
+	"github.com/yinhm/friendfeed/sup"
 	"golang.org/x/net/context"
 )
 
@@ -256,6 +258,55 @@ func TestFinishJobQueue(t *testing.T) {
 	})
 }
 
+func TestCheckpointJob(t *testing.T) {
+	setup()
+	defer teardown()
+
+	Convey("Given running job checkpointed, redo resumes from checkpoint", t, func() {
+		ctx := context.Background()
+		queued := &pb.FeedJob{Id: "yinhm", RemoteKey: "key", TargetId: "yinhm", PageSize: 100}
+		srv.EnqueJob(ctx, queued)
+		running, err := srv.GetFeedJob(ctx, &pb.Worker{Id: "123456"})
+		So(err, ShouldBeNil)
+
+		running.Start = 200
+		_, err = srv.UpdateJob(ctx, running)
+		So(err, ShouldBeNil)
+		_, err = srv.UpdateJob(ctx, queued)
+		So(err, ShouldNotBeNil)
+
+		// still checkpointed by its worker
+		So(srv.RedoFailedJob(), ShouldBeNil)
+		jobs, err := srv.ListJobQueue(store.TableJobRunning)
+		So(err, ShouldBeNil)
+		So(len(jobs), ShouldEqual, 1)
+
+		So(srv.requeueJobs(time.Now().Add(time.Second)), ShouldBeNil)
+		jobs, err = srv.ListJobQueue(store.TableJobRunning)
+		So(err, ShouldBeNil)
+		So(len(jobs), ShouldEqual, 0)
+		_, err = srv.UpdateJob(ctx, running)
+		So(err, ShouldNotBeNil)
+
+		resumed, err := srv.GetFeedJob(ctx, &pb.Worker{Id: "654321"})
+		So(err, ShouldBeNil)
+		So(resumed.Start, ShouldEqual, 200)
+		So(resumed.RemoteKey, ShouldEqual, "key")
+
+		// released by worker shutting down, requeued at once
+		resumed.Start = 300
+		_, err = srv.ReleaseJob(ctx, resumed)
+		So(err, ShouldBeNil)
+		jobs, _ = srv.ListJobQueue(store.TableJobRunning)
+		So(len(jobs), ShouldEqual, 0)
+		_, err = srv.ReleaseJob(ctx, resumed)
+		So(err, ShouldNotBeNil)
+		released, err := srv.GetFeedJob(ctx, &pb.Worker{Id: "123456"})
+		So(err, ShouldBeNil)
+		So(released.Start, ShouldEqual, 300)
+	})
+}
+
 func TestPostProfile(t *testing.T) {
 	setup()
 	defer teardown()
@@ -388,3 +439,575 @@ func TestFeedIndexLoadDump(t *testing.T) {
 		So(index.bufq[len(index.bufq)-1], ShouldEqual, "last")
 	})
 }
+
+func TestCrossPostEntry(t *testing.T) {
+	setup()
+	defer teardown()
+
+	Convey("Given user and group, post entry to both feeds", t, func() {
+		ctx := context.Background()
+
+		user := &pb.Profile{
+			Uuid: "c6f8dca854f011ddb489003048343a40",
+			Id:   "yinhm",
+			Name: "yinhm",
+			Type: "user",
+		}
+		group := &pb.Profile{
+			Uuid: "2f8c7d9ab1d311dd9d29003048343a40",
+			Id:   "golang",
+			Name: "Go",
+			Type: "group",
+		}
+		So(store.UpdateProfile(srv.mdb, user), ShouldBeNil)
+		So(store.UpdateProfile(srv.mdb, group), ShouldBeNil)
+
+		entry := &pb.Entry{
+			Id:   "ab439960a83546c683fd989a40a68462",
+			Date: "2015-04-09T07:40:22Z",
+			Body: "cross post",
+			From: &pb.Feed{Id: user.Id, Name: user.Name, Type: user.Type},
+			// duplicated targets should only be indexed once
+			To:          []*pb.Feed{{Id: "golang"}, {Id: "yinhm"}, {Id: "golang"}},
+			ProfileUuid: user.Uuid,
+		}
+		got, err := srv.PostEntry(ctx, entry)
+		So(err, ShouldBeNil)
+		So(len(got.To), ShouldEqual, 1)
+		So(got.To[0].Uuid, ShouldEqual, group.Uuid)
+
+		for _, id := range []string{"yinhm", "golang"} {
+			req := &pb.FeedRequest{Id: id, PageSize: 50}
+			feed, err := srv.FetchFeed(ctx, req)
+			So(err, ShouldBeNil)
+			So(len(feed.Entries), ShouldEqual, 1)
+			So(feed.Entries[0].Id, ShouldEqual, entry.Id)
+		}
+
+		Convey("unknown target feed should be rejected", func() {
+			entry.Id = "95a0d02fb680418ea1b7fb55baf1ee2d"
+			entry.To = []*pb.Feed{{Id: "nobody"}}
+			_, err := srv.PostEntry(ctx, entry)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestEditDeleteEntry(t *testing.T) {
+	setup()
+	defer teardown()
+
+	Convey("Given posted entry, edit then delete it", t, func() {
+		ctx := context.Background()
+
+		user := &pb.Profile{
+			Uuid: "c6f8dca854f011ddb489003048343a40",
+			Id:   "yinhm",
+			Name: "yinhm",
+			Type: "user",
+		}
+		other := &pb.Profile{
+			Uuid: "2f8c7d9ab1d311dd9d29003048343a40",
+			Id:   "bret",
+			Name: "Bret Taylor",
+			Type: "user",
+		}
+		So(store.UpdateProfile(srv.mdb, user), ShouldBeNil)
+		So(store.UpdateProfile(srv.mdb, other), ShouldBeNil)
+
+		entry := &pb.Entry{
+			Id:          "ab439960a83546c683fd989a40a68462",
+			Date:        "2015-04-09T07:40:22Z",
+			Body:        "first version",
+			RawBody:     "first version",
+			From:        &pb.Feed{Id: user.Id, Name: user.Name, Type: user.Type},
+			ProfileUuid: user.Uuid,
+		}
+		_, err := srv.PostEntry(ctx, entry)
+		So(err, ShouldBeNil)
+
+		editReq := &pb.EntryEditRequest{
+			Entry:   entry.Id,
+			User:    user.Id,
+			Body:    "second version",
+			RawBody: "second version",
+		}
+		got, err := srv.EditEntry(ctx, editReq)
+		So(err, ShouldBeNil)
+		So(got.Body, ShouldEqual, "second version")
+
+		history, err := store.GetEntryHistory(srv.rdb, entry.Id)
+		So(err, ShouldBeNil)
+		So(len(history), ShouldEqual, 1)
+		So(history[0].Body, ShouldEqual, "first version")
+
+		// only author allowed
+		editReq.User = other.Id
+		_, err = srv.EditEntry(ctx, editReq)
+		So(err, ShouldNotBeNil)
+		delReq := &pb.EntryDeleteRequest{Entry: entry.Id, User: other.Id}
+		_, err = srv.DeleteEntry(ctx, delReq)
+		So(err, ShouldNotBeNil)
+
+		delReq.User = user.Id
+		_, err = srv.DeleteEntry(ctx, delReq)
+		So(err, ShouldBeNil)
+
+		req := &pb.FeedRequest{Id: user.Id, PageSize: 50}
+		feed, err := srv.FetchFeed(ctx, req)
+		So(err, ShouldBeNil)
+		So(len(feed.Entries), ShouldEqual, 0)
+
+		history, err = store.GetEntryHistory(srv.rdb, entry.Id)
+		So(err, ShouldBeNil)
+		So(len(history), ShouldEqual, 0)
+
+		srv.cached["public"].rebuild()
+		for _, key := range srv.cached["public"].bufq {
+			So(key, ShouldNotEqual, store.NewUUIDKey(store.TableEntry, uuid.FromStringOrNil(entry.Id)).String())
+		}
+
+		_, err = srv.FetchEntry(ctx, &pb.EntryRequest{Uuid: entry.Id})
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Given entry posted to group, admin deletes but never edits it", t, func() {
+		ctx := context.Background()
+
+		admin := &pb.Profile{Uuid: "2f8c7d9ab1d311dd9d29003048343a40", Id: "bret", Name: "Bret Taylor", Type: "user"}
+		group := &pb.Feedinfo{
+			Uuid:   "e81a5ebe1a4a11ddbf81003048343a40",
+			Id:     "friendfeed-feedback",
+			Name:   "FriendFeed Feedback",
+			Type:   "group",
+			Admins: []*pb.Profile{admin},
+		}
+		So(store.UpdateProfile(srv.mdb, &pb.Profile{Uuid: group.Uuid, Id: group.Id, Name: group.Name, Type: group.Type}), ShouldBeNil)
+		So(store.SaveFeedinfo(srv.rdb, group.Uuid, group), ShouldBeNil)
+
+		entry := &pb.Entry{
+			Id:          "0c2a1f7e4b7d4c3f9a54c3e1c0a9f1d2",
+			Date:        "2015-04-10T07:40:22Z",
+			Body:        "feedback",
+			From:        &pb.Feed{Id: "yinhm", Name: "yinhm", Type: "user"},
+			To:          []*pb.Feed{{Id: group.Id, Uuid: group.Uuid, Name: group.Name, Type: "group"}},
+			ProfileUuid: "c6f8dca854f011ddb489003048343a40",
+		}
+		_, err := srv.PostEntry(ctx, entry)
+		So(err, ShouldBeNil)
+
+		_, err = srv.EditEntry(ctx, &pb.EntryEditRequest{Entry: entry.Id, User: admin.Id, Body: "edited"})
+		So(err, ShouldNotBeNil)
+		got, err := srv.FetchEntry(ctx, &pb.EntryRequest{Uuid: entry.Id})
+		So(err, ShouldBeNil)
+		So(got.Entries[0].Body, ShouldEqual, "feedback")
+
+		_, err = srv.DeleteEntry(ctx, &pb.EntryDeleteRequest{Entry: entry.Id, User: admin.Id})
+		So(err, ShouldBeNil)
+		_, err = srv.FetchEntry(ctx, &pb.EntryRequest{Uuid: entry.Id})
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestHideEntry(t *testing.T) {
+	setup()
+	defer teardown()
+
+	Convey("Given entries, hide entry and author from user's feed", t, func() {
+		ctx := context.Background()
+
+		user := &pb.Profile{
+			Uuid: "c6f8dca854f011ddb489003048343a40",
+			Id:   "yinhm",
+			Name: "yinhm",
+			Type: "user",
+		}
+		other := &pb.Profile{
+			Uuid: "2f8c7d9ab1d311dd9d29003048343a40",
+			Id:   "bret",
+			Name: "Bret Taylor",
+			Type: "user",
+		}
+		So(store.UpdateProfile(srv.mdb, user), ShouldBeNil)
+		So(store.UpdateProfile(srv.mdb, other), ShouldBeNil)
+
+		ids := []string{
+			"0f6c8a2ad0c04b3a9f1f3d5a6b7c8d90",
+			"1f6c8a2ad0c04b3a9f1f3d5a6b7c8d91",
+			"2f6c8a2ad0c04b3a9f1f3d5a6b7c8d92",
+		}
+		for i, id := range ids {
+			entry := &pb.Entry{
+				Id:          id,
+				Date:        fmt.Sprintf("2015-04-0%dT07:40:22Z", i+1),
+				Body:        "hello " + id,
+				From:        &pb.Feed{Id: other.Id, Name: other.Name, Type: other.Type},
+				ProfileUuid: other.Uuid,
+			}
+			_, err := srv.PostEntry(ctx, entry)
+			So(err, ShouldBeNil)
+		}
+
+		req := &pb.FeedRequest{Id: other.Id, PageSize: 50, User: user.Uuid}
+		feed, err := srv.FetchFeed(ctx, req)
+		So(err, ShouldBeNil)
+		So(len(feed.Entries), ShouldEqual, 3)
+		// nothing hidden, skipped by count
+		filter, err := srv.newEntryFilter(req, false)
+		So(err, ShouldBeNil)
+		So(filter, ShouldBeNil)
+
+		hideReq := &pb.HideRequest{User: user.Uuid, Target: ids[0], Hide: true}
+		entry, err := srv.HideEntry(ctx, hideReq)
+		So(err, ShouldBeNil)
+		So(entry.Hidden, ShouldBeTrue)
+
+		feed, err = srv.FetchFeed(ctx, req)
+		So(err, ShouldBeNil)
+		So(len(feed.Entries), ShouldEqual, 2)
+		for _, e := range feed.Entries {
+			So(e.Id, ShouldNotEqual, ids[0])
+		}
+
+		// hidden=1 includes hidden entries
+		req.Hidden = true
+		feed, err = srv.FetchFeed(ctx, req)
+		So(err, ShouldBeNil)
+		So(len(feed.Entries), ShouldEqual, 3)
+		hidden := 0
+		for _, e := range feed.Entries {
+			if e.Hidden {
+				So(e.Id, ShouldEqual, ids[0])
+				hidden++
+			}
+		}
+		So(hidden, ShouldEqual, 1)
+		req.Hidden = false
+
+		// anonymous and other users are not affected
+		feed, err = srv.FetchFeed(ctx, &pb.FeedRequest{Id: other.Id, PageSize: 50})
+		So(err, ShouldBeNil)
+		So(len(feed.Entries), ShouldEqual, 3)
+
+		authorReq := &pb.HideRequest{User: user.Uuid, Target: other.Id, Hide: true}
+		_, err = srv.HideAuthor(ctx, authorReq)
+		So(err, ShouldBeNil)
+		feed, err = srv.FetchFeed(ctx, req)
+		So(err, ShouldBeNil)
+		So(len(feed.Entries), ShouldEqual, 0)
+
+		publicReq := &pb.FeedRequest{Id: "public", PageSize: 50, User: user.Uuid}
+		feed, err = srv.FetchFeed(ctx, publicReq)
+		So(err, ShouldBeNil)
+		for _, e := range feed.Entries {
+			So(e.From.Id, ShouldNotEqual, other.Id)
+		}
+
+		authorReq.Hide = false
+		_, err = srv.HideAuthor(ctx, authorReq)
+		So(err, ShouldBeNil)
+		hideReq.Hide = false
+		_, err = srv.HideEntry(ctx, hideReq)
+		So(err, ShouldBeNil)
+		feed, err = srv.FetchFeed(ctx, req)
+		So(err, ShouldBeNil)
+		So(len(feed.Entries), ShouldEqual, 3)
+	})
+}
+
+func TestSearchFeed(t *testing.T) {
+	setup()
+	defer teardown()
+
+	Convey("Given entries, search by words and fetch feedinfo by id", t, func() {
+		ctx := context.Background()
+
+		user := &pb.Profile{
+			Uuid: "c6f8dca854f011ddb489003048343a40",
+			Id:   "yinhm",
+			Name: "yinhm",
+			Type: "user",
+		}
+		So(store.UpdateProfile(srv.mdb, user), ShouldBeNil)
+		info := &pb.Feedinfo{Uuid: user.Uuid, Id: user.Id, Name: user.Name, Type: user.Type}
+		So(store.SaveFeedinfo(srv.rdb, user.Uuid, info), ShouldBeNil)
+
+		bodies := []string{
+			"Golang is fun",
+			"rocksdb and golang",
+			"nothing here",
+		}
+		for i, body := range bodies {
+			entry := &pb.Entry{
+				Id:          fmt.Sprintf("%d6c8a2ad0c04b3a9f1f3d5a6b7c8d9e%d", i+3, i),
+				Date:        fmt.Sprintf("2015-04-0%dT07:40:22Z", i+1),
+				Body:        body,
+				RawBody:     body,
+				From:        &pb.Feed{Id: user.Id, Name: user.Name, Type: user.Type},
+				ProfileUuid: user.Uuid,
+			}
+			_, err := srv.PostEntry(ctx, entry)
+			So(err, ShouldBeNil)
+		}
+
+		req := &pb.FeedRequest{Id: user.Id, PageSize: 50, Query: "golang"}
+		feed, err := srv.FetchFeed(ctx, req)
+		So(err, ShouldBeNil)
+		So(len(feed.Entries), ShouldEqual, 2)
+
+		req.Query = "GOLANG rocksdb"
+		feed, err = srv.FetchFeed(ctx, req)
+		So(err, ShouldBeNil)
+		So(len(feed.Entries), ShouldEqual, 1)
+		So(feed.Entries[0].RawBody, ShouldEqual, bodies[1])
+
+		req.Query = "golang"
+		req.Start = 1
+		feed, err = srv.FetchFeed(ctx, req)
+		So(err, ShouldBeNil)
+		So(len(feed.Entries), ShouldEqual, 1)
+
+		got, err := srv.FetchFeedinfo(ctx, &pb.ProfileRequest{Id: user.Id})
+		So(err, ShouldBeNil)
+		So(got.Uuid, ShouldEqual, user.Uuid)
+
+		profile, err := srv.FetchProfile(ctx, &pb.ProfileRequest{Id: user.Id})
+		So(err, ShouldBeNil)
+		So(profile.Uuid, ShouldEqual, user.Uuid)
+	})
+}
+
+func TestSupUpdates(t *testing.T) {
+	setup()
+	defer teardown()
+
+	Convey("Given posted entry, feeds updated should be published", t, func() {
+		ctx := context.Background()
+
+		user := &pb.Profile{
+			Uuid:  "c6f8dca854f011ddb489003048343a40",
+			Id:    "yinhm",
+			Name:  "yinhm",
+			Type:  "user",
+			SupId: "4ceb94af",
+		}
+		other := &pb.Profile{
+			Uuid: "2f8c7d9ab1d311dd9d29003048343a40",
+			Id:   "bret",
+			Name: "Bret Taylor",
+			// direct messages never announced, see TestDirectMessage
+			Type: "group",
+		}
+		So(store.UpdateProfile(srv.mdb, user), ShouldBeNil)
+		So(store.UpdateProfile(srv.mdb, other), ShouldBeNil)
+
+		since := time.Now().Add(-time.Minute).Unix()
+		resp, err := srv.FetchSupUpdates(ctx, &pb.SupRequest{Since: since})
+		So(err, ShouldBeNil)
+		So(len(resp.Updates), ShouldEqual, 0)
+
+		entry := &pb.Entry{
+			Id:          "3f6c8a2ad0c04b3a9f1f3d5a6b7c8d93",
+			Date:        "2015-04-09T07:40:22Z",
+			Body:        "hello",
+			From:        &pb.Feed{Id: user.Id, Name: user.Name, Type: user.Type},
+			ProfileUuid: user.Uuid,
+			To:          []*pb.Feed{{Id: other.Id}},
+		}
+		_, err = srv.PostEntry(ctx, entry)
+		So(err, ShouldBeNil)
+
+		resp, err = srv.FetchSupUpdates(ctx, &pb.SupRequest{Since: since})
+		So(err, ShouldBeNil)
+		supIds := make(map[string]bool)
+		for _, u := range resp.Updates {
+			supIds[u.SupId] = true
+			So(u.Updated, ShouldBeGreaterThanOrEqualTo, since)
+		}
+		So(supIds, ShouldResemble, map[string]bool{
+			"4ceb94af":          true,
+			sup.NewId("bret"):   true,
+			sup.NewId("public"): true,
+		})
+
+		feed, err := srv.FetchFeed(ctx, &pb.FeedRequest{Id: other.Id, PageSize: 10})
+		So(err, ShouldBeNil)
+		So(feed.SupId, ShouldEqual, sup.NewId("bret"))
+
+		// nothing updated in future
+		later := time.Now().Add(time.Minute).Unix()
+		resp, err = srv.FetchSupUpdates(ctx, &pb.SupRequest{Since: later})
+		So(err, ShouldBeNil)
+		So(len(resp.Updates), ShouldEqual, 0)
+	})
+
+	Convey("Given profiles without sup id, assign sup ids kept on renamed", t, func() {
+		ctx := context.Background()
+
+		archived := &pb.Profile{Uuid: "e81a5ebe1a4a11ddbf81003048343a40", Id: "ana", Name: "Ana", Type: "user"}
+		So(store.UpdateProfile(srv.mdb, archived), ShouldBeNil)
+		_, err := srv.FinishJob(ctx, &pb.FeedJob{Id: "ana", RemoteKey: "key", TargetId: "ana"})
+		So(err, ShouldBeNil)
+
+		So(srv.AssignSupIds(), ShouldBeNil)
+		bret, err := store.GetProfile(srv.mdb, "bret")
+		So(err, ShouldBeNil)
+		So(bret.SupId, ShouldEqual, sup.NewId("bret"))
+		yinhm, err := store.GetProfile(srv.mdb, "yinhm")
+		So(err, ShouldBeNil)
+		So(yinhm.SupId, ShouldEqual, "4ceb94af")
+		// left to sup id imported from friendfeed
+		ana, err := store.GetProfile(srv.mdb, "ana")
+		So(err, ShouldBeNil)
+		So(ana.SupId, ShouldEqual, "")
+
+		bret.Id = "bret2"
+		bret.SupId = ""
+		So(store.UpdateProfile(srv.mdb, bret), ShouldBeNil)
+		feed, err := srv.FetchFeed(ctx, &pb.FeedRequest{Id: "bret2", PageSize: 10})
+		So(err, ShouldBeNil)
+		So(feed.SupId, ShouldEqual, sup.NewId("bret"))
+	})
+}
+
+func TestFeedService(t *testing.T) {
+	setup()
+	defer teardown()
+
+	Convey("Given user, add and delete rss/atom services", t, func() {
+		ctx := context.Background()
+
+		feedinfo := &pb.Feedinfo{
+			Uuid: "c6f8dca854f011ddb489003048343a40",
+			Id:   "yinhm",
+			Name: "yinhm",
+			Type: "user",
+		}
+		_, err := srv.PostFeedinfo(ctx, feedinfo)
+		So(err, ShouldBeNil)
+
+		req := &pb.ServiceRequest{User: feedinfo.Uuid, Service: "feed", Url: "ftp://example.com/feed"}
+		_, err = srv.AddService(ctx, req)
+		So(err, ShouldNotBeNil)
+
+		blog := "http://blog.example.com/feed.xml"
+		photos := "https://photos.example.com/photos.atom"
+		_, err = srv.AddService(ctx, &pb.ServiceRequest{User: feedinfo.Uuid, Service: "feed", Url: blog, Name: "Blog"})
+		So(err, ShouldBeNil)
+		_, err = srv.AddService(ctx, &pb.ServiceRequest{User: feedinfo.Uuid, Service: "feed", Url: photos})
+		So(err, ShouldBeNil)
+		// added already
+		info, err := srv.AddService(ctx, &pb.ServiceRequest{User: feedinfo.Uuid, Service: "feed", Url: blog})
+		So(err, ShouldBeNil)
+		So(len(info.Services), ShouldEqual, 2)
+		So(info.Services[0].Name, ShouldEqual, "Blog")
+		So(info.Services[1].Name, ShouldEqual, "photos.example.com")
+
+		_, err = srv.AddService(ctx, &pb.ServiceRequest{User: feedinfo.Uuid, Service: "twitter", Url: blog})
+		So(err, ShouldNotBeNil)
+
+		graph := BuildGraph(info)
+		So(graph.Services, ShouldContainKey, "feed:"+blog)
+		So(graph.Services, ShouldContainKey, "feed:"+photos)
+
+		// initial jobs import all items
+		job1, err := srv.GetFeedJob(ctx, &pb.Worker{Id: "w1"})
+		So(err, ShouldBeNil)
+		So(job1.Service.Url, ShouldEqual, blog)
+		So(job1.Service.Updated, ShouldEqual, 0)
+		So(job1.Profile.Id, ShouldEqual, "yinhm")
+		job2, err := srv.GetFeedJob(ctx, &pb.Worker{Id: "w1"})
+		So(err, ShouldBeNil)
+		So(job2.Service.Url, ShouldEqual, photos)
+
+		// scheduled already within interval
+		So(srv.RefetchUserFeed(), ShouldBeNil)
+		_, err = srv.GetFeedJob(ctx, &pb.Worker{Id: "w1"})
+		So(err, ShouldNotBeNil)
+
+		// later jobs import items since added
+		profile, err := store.GetProfile(srv.mdb, feedinfo.Id)
+		So(err, ShouldBeNil)
+		n, err := srv.scheduleServices(profile, info, nil, true)
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 2)
+		job3, err := srv.GetFeedJob(ctx, &pb.Worker{Id: "w1"})
+		So(err, ShouldBeNil)
+		So(job3.Service.Url, ShouldEqual, blog)
+		So(job3.Service.Updated, ShouldBeGreaterThan, 0)
+
+		info, err = srv.DeleteService(ctx, &pb.ServiceRequest{User: feedinfo.Uuid, Service: "feed", Url: blog})
+		So(err, ShouldBeNil)
+		So(len(info.Services), ShouldEqual, 1)
+		So(info.Services[0].Url, ShouldEqual, photos)
+
+		info, err = srv.AddService(ctx, &pb.ServiceRequest{User: feedinfo.Uuid, Service: "mastodon", Url: "https://social.example.com/users/yinhm/outbox"})
+		So(err, ShouldBeNil)
+		So(len(info.Services), ShouldEqual, 2)
+		So(info.Services[1].Profile, ShouldEqual, "https://social.example.com/users/yinhm")
+
+		info, err = srv.DeleteService(ctx, &pb.ServiceRequest{User: feedinfo.Uuid, Service: "feed"})
+		So(err, ShouldBeNil)
+		So(len(info.Services), ShouldEqual, 1)
+		So(info.Services[0].Id, ShouldEqual, "mastodon")
+	})
+}
+
+func TestDirectMessage(t *testing.T) {
+	setup()
+	defer teardown()
+
+	Convey("Given direct message, visible to the author and recipients only", t, func() {
+		ctx := context.Background()
+		since := time.Now().Add(-time.Minute)
+
+		var users []*pb.Profile
+		for _, id := range []string{"alice", "bob", "carol"} {
+			user := &pb.Profile{Uuid: uuid.NewV5(uuid.NamespaceURL, id).String(), Id: id, Name: id, Type: "user"}
+			So(store.UpdateProfile(srv.mdb, user), ShouldBeNil)
+			users = append(users, user)
+		}
+		alice, bob, carol := users[0], users[1], users[2]
+
+		public := &pb.Entry{
+			Id:          "0d3c1a0ea83546c683fd989a40a68462",
+			Date:        "2015-04-08T07:40:22Z",
+			Body:        "hello",
+			From:        &pb.Feed{Id: alice.Id, Name: alice.Name, Type: alice.Type},
+			ProfileUuid: alice.Uuid,
+		}
+		_, err := srv.PostEntry(ctx, public)
+		So(err, ShouldBeNil)
+		dm := &pb.Entry{
+			Id:          "6a4f2e9ba83546c683fd989a40a68462",
+			Date:        "2015-04-09T07:40:22Z",
+			Body:        "secret",
+			From:        &pb.Feed{Id: alice.Id, Name: alice.Name, Type: alice.Type},
+			To:          []*pb.Feed{{Id: bob.Id}},
+			ProfileUuid: alice.Uuid,
+		}
+		_, err = srv.PostEntry(ctx, dm)
+		So(err, ShouldBeNil)
+
+		count := func(feedId, user string, start int32) int {
+			feed, err := srv.FetchFeed(ctx, &pb.FeedRequest{Id: feedId, User: user, Start: start, PageSize: 50})
+			So(err, ShouldBeNil)
+			return len(feed.Entries)
+		}
+		So(count(bob.Id, bob.Uuid, 0), ShouldEqual, 1)
+		So(count(bob.Id, carol.Uuid, 0), ShouldEqual, 0)
+		So(count(bob.Id, "", 0), ShouldEqual, 0)
+		So(count(alice.Id, alice.Uuid, 0), ShouldEqual, 2)
+		So(count(alice.Id, bob.Uuid, 0), ShouldEqual, 2)
+		So(count(alice.Id, carol.Uuid, 0), ShouldEqual, 1)
+		// skipped by visible entries
+		So(count(alice.Id, carol.Uuid, 1), ShouldEqual, 0)
+
+		// never announced by sup
+		updates, err := store.GetSupUpdates(srv.rdb, since)
+		So(err, ShouldBeNil)
+		for _, u := range updates {
+			So(u.Uuid.String(), ShouldNotEqual, bob.Uuid)
+		}
+	})
+}
@@ -28,6 +28,10 @@ const (
 	// duplicate a reverse index
 	TableReverseEntryIndex PrefixTable = 5
 	TableIndexCache        PrefixTable = 6
+	// entry edit history, | table | entry uuid | flake |
+	TableEntryHistory PrefixTable = 7
+	// feeds updated for sup, | table | flake | -> feed uuid
+	TableSupUpdate PrefixTable = 8
 
 	TableProfile      PrefixTable = 100
 	TableService      PrefixTable = 101
@@ -35,10 +39,35 @@ const (
 	TableSubscriber   PrefixTable = 103
 	TableOAuthTwitter PrefixTable = 104
 	TableOAuthGoogle  PrefixTable = 105
+	// per user hidden lists, | table | user uuid | / | entry uuid or author id |
+	TableHiddenEntry  PrefixTable = 106
+	TableHiddenAuthor PrefixTable = 107
+	// sup and websub subscriptions to imported feeds, | table | topic |
+	TablePushSubscription PrefixTable = 108
+	// websub subscribers of our feeds, | table | feed id | / | topic | callback |
+	TableHubSubscription PrefixTable = 109
+	// activitypub key pairs of our feeds, | table | feed id |
+	TableActorKey PrefixTable = 110
+	// activitypub followers of our feeds, | table | feed id | / | actor |
+	TableFollower PrefixTable = 111
+	// media content hash of source url, | table | url |
+	TableMediaURL PrefixTable = 112
+	// media blobs with refs counted, | table | hash |
+	TableMediaBlob PrefixTable = 113
+	// media blobs referenced by owner, | table | owner |
+	TableMediaRefs PrefixTable = 114
+	// feeds indexing entries visible to recipients only, | table | feed uuid |
+	TableRestrictedFeed PrefixTable = 115
 
 	TableJobFeed    PrefixTable = 200
 	TableJobRunning PrefixTable = 201
 	TableJobHistory PrefixTable = 202
+	// pending websub content distribution, | table | subscription key |
+	TableHubDelivery PrefixTable = 203
+	// pending activitypub deliveries, | table | hex flake id |
+	TableActivityDelivery PrefixTable = 204
+	// pending media mirroring, | table | entry id |
+	TableMediaMirror PrefixTable = 205
 
 	TableMax PrefixTable = 1e8
 
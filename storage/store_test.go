@@ -607,3 +607,78 @@ func TestTimeTravelId(t *testing.T) {
 		}
 	})
 }
+
+func TestSupUpdates(t *testing.T) {
+	setup()
+	defer teardown()
+
+	Convey("Given updated feeds, get and purge sup updates", t, func() {
+		since := time.Now().Add(-time.Minute)
+		uuid1 := uuid.NewV4()
+		uuid2 := uuid.NewV4()
+		So(PutSupUpdate(rdb, uuid1, uuid2), ShouldBeNil)
+
+		updates, err := GetSupUpdates(rdb, since)
+		So(err, ShouldBeNil)
+		So(len(updates), ShouldEqual, 2)
+		So(uuid.Equal(updates[0].Uuid, uuid1), ShouldBeTrue)
+		So(uuid.Equal(updates[1].Uuid, uuid2), ShouldBeTrue)
+		So(updates[0].Updated.After(since), ShouldBeTrue)
+
+		updates, err = GetSupUpdates(rdb, time.Now().Add(time.Minute))
+		So(err, ShouldBeNil)
+		So(len(updates), ShouldEqual, 0)
+
+		So(DeleteSupUpdates(rdb, time.Now().Add(time.Minute)), ShouldBeNil)
+		updates, err = GetSupUpdates(rdb, since)
+		So(err, ShouldBeNil)
+		So(len(updates), ShouldEqual, 0)
+	})
+}
+
+func TestProfileSupId(t *testing.T) {
+	setup()
+	defer teardown()
+
+	Convey("Sup id never assigned on saved, kept on renamed", t, func() {
+		uuid1 := uuid.NewV4()
+		p := &pb.Profile{Uuid: uuid1.String(), Id: "foo", Name: "foo", Type: "user"}
+		So(UpdateProfile(mdb, p), ShouldBeNil)
+		So(p.SupId, ShouldEqual, "")
+
+		supId := "4ceb94af"
+		p.SupId = supId
+		So(UpdateProfile(mdb, p), ShouldBeNil)
+
+		p2 := &pb.Profile{Uuid: uuid1.String(), Id: "bar", Name: "bar", Type: "user"}
+		So(UpdateProfile(mdb, p2), ShouldBeNil)
+		got, err := GetProfileFromUuid(mdb, uuid1)
+		So(err, ShouldBeNil)
+		So(got.Id, ShouldEqual, "bar")
+		So(got.SupId, ShouldEqual, supId)
+	})
+
+	Convey("Private feeds never published by sup", t, func() {
+		author := uuid.NewV4()
+		group := uuid.NewV4()
+		private := uuid.NewV4()
+		e := &pb.Entry{
+			ProfileUuid: author.String(),
+			To: []*pb.Feed{
+				{Uuid: group.String(), Type: "group"},
+				{Uuid: private.String(), Type: "group", Private: true},
+			},
+		}
+		So(len(entryFeeds(e)), ShouldEqual, 0)
+
+		e.To = e.To[:1]
+		feeds := entryFeeds(e)
+		So(len(feeds), ShouldEqual, 2)
+		So(uuid.Equal(feeds[0], author), ShouldBeTrue)
+		So(uuid.Equal(feeds[1], group), ShouldBeTrue)
+
+		// direct messages never published
+		e.To = []*pb.Feed{{Uuid: private.String(), Type: "user"}}
+		So(len(entryFeeds(e)), ShouldEqual, 0)
+	})
+}
@@ -1,13 +1,17 @@
 package store
 
 import (
+	"bytes"
+	"encoding/hex"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/golang/protobuf/proto"
 	uuid "github.com/satori/go.uuid"
+	"github.com/yinhm/friendfeed/activitypub"
 	pb "github.com/yinhm/friendfeed/proto"
+	"github.com/yinhm/friendfeed/storage/flake"
 )
 
 // TODO: refactor, introduce another interface above proto.Message?
@@ -55,7 +59,7 @@ func PutEntry(rdb *Store, entry *pb.Entry, update bool) (*UUIDKey, error) {
 			if err := rdb.Put(kb1, bytes); err != nil {
 				return nil, err
 			}
-			return key, nil
+			return key, PutSupUpdate(rdb, entryFeeds(entry)...)
 		}
 		return key, &Error{"ok", ExistItem}
 	}
@@ -89,15 +93,250 @@ func PutEntry(rdb *Store, entry *pb.Entry, update bool) (*UUIDKey, error) {
 		return nil, err
 	}
 
-	return key, nil
+	// cross posted: index entry to every target feed exactly once
+	indexed := map[uuid.UUID]bool{uuid1: true}
+	restricted := !activitypub.IsPublic(entry)
+	if restricted {
+		if err := markRestricted(rdb, uuid1); err != nil {
+			return nil, err
+		}
+	}
+	for _, to := range entry.To {
+		if to == nil || to.Uuid == "" {
+			continue // target feed not mirrored
+		}
+		uuid3, err := uuid.FromString(to.Uuid)
+		if err != nil {
+			return nil, err
+		}
+		if indexed[uuid3] {
+			continue
+		}
+		indexed[uuid3] = true
+
+		key4 := NewUUIDFlakeKey(TableReverseEntryIndex, uuid3, flakeid)
+		if err := rdb.Put(key4.Bytes(), kb1); err != nil {
+			return nil, err
+		}
+		if restricted {
+			if err := markRestricted(rdb, uuid3); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return key, PutSupUpdate(rdb, entryFeeds(entry)...)
 }
 
-func UpdateProfile(mdb *Store, profile *pb.Profile) error {
-	bytes, err := proto.Marshal(profile)
+// markRestricted marks feed of uuid1 indexing entries visible to recipients
+// only, entries of the feed filtered on read.
+func markRestricted(rdb *Store, uuid1 uuid.UUID) error {
+	return rdb.Put(NewUUIDKey(TableRestrictedFeed, uuid1).Bytes(), []byte{1})
+}
+
+// IsRestrictedFeed reports whether feed of uuid1 ever indexed direct messages
+// or entries to private groups.
+func IsRestrictedFeed(rdb *Store, uuid1 uuid.UUID) (bool, error) {
+	value, err := rdb.Get(NewUUIDKey(TableRestrictedFeed, uuid1).Bytes())
+	if err != nil {
+		return false, err
+	}
+	return len(value) > 0, nil
+}
+
+// entryFeeds returns uuids of author and public target feeds of entry, none
+// of direct messages and entries to private groups.
+func entryFeeds(entry *pb.Entry) []uuid.UUID {
+	if !activitypub.IsPublic(entry) {
+		return nil
+	}
+	var feeds []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range append([]*pb.Feed{{Uuid: entry.ProfileUuid}}, entry.To...) {
+		// private feeds never published
+		if id == nil || id.Uuid == "" || id.Private {
+			continue
+		}
+		uuid1, err := uuid.FromString(id.Uuid)
+		if err != nil || seen[uuid1] {
+			continue
+		}
+		seen[uuid1] = true
+		feeds = append(feeds, uuid1)
+	}
+	return feeds
+}
+
+// PutSupUpdate records feeds updated now.
+// K-> | table | flake |
+// V-> |   feed uuid   |
+func PutSupUpdate(rdb *Store, feeds ...uuid.UUID) error {
+	for _, uuid1 := range feeds {
+		key := NewFlakeKey(TableSupUpdate, rdb.NextId())
+		if err := rdb.Put(key.Bytes(), uuid1.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SupUpdate is a feed updated at time.
+type SupUpdate struct {
+	Uuid    uuid.UUID
+	Updated time.Time
+}
+
+// GetSupUpdates returns feeds updated since, oldest first.
+func GetSupUpdates(rdb *Store, since time.Time) ([]*SupUpdate, error) {
+	prefix := TableSupUpdate.Bytes()
+	iter := rdb.Iterator()
+	defer iter.Close()
+
+	var updates []*SupUpdate
+	iter.Seek(NewFlakeKey(TableSupUpdate, rdb.TimeTravelId(since)).Bytes())
+	for ; iter.ValidForPrefix(prefix); iter.Next() {
+		kSlice := iter.Key()
+		vSlice := iter.Value()
+
+		var id flake.Id
+		copy(id[:], kSlice.Data()[len(prefix):])
+		uuid1, err := uuid.FromBytes(vSlice.Data())
+		kSlice.Free()
+		vSlice.Free()
+		if err != nil {
+			return nil, err
+		}
+		updates = append(updates, &SupUpdate{uuid1, flake.ParseTimestamp(id)})
+	}
+	return updates, nil
+}
+
+// DeleteSupUpdates deletes records older than before.
+func DeleteSupUpdates(rdb *Store, before time.Time) error {
+	end := NewFlakeKey(TableSupUpdate, rdb.TimeTravelId(before)).Bytes()
+	var keys [][]byte
+	_, err := ForwardTableScan(rdb, TableSupUpdate, func(i int, k, v []byte) error {
+		if bytes.Compare(k, end) >= 0 {
+			return &Error{"ok", StopIteration}
+		}
+		keys = append(keys, append([]byte{}, k...))
+		return nil
+	})
 	if err != nil {
 		return err
 	}
+	for _, k := range keys {
+		if err := rdb.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteEntry removes entry, its reverse index rows and edit history.
+func DeleteEntry(rdb *Store, entry *pb.Entry) (*UUIDKey, error) {
+	uuid2, err := uuid.FromString(entry.Id)
+	if err != nil {
+		return nil, err
+	}
+	key := NewUUIDKey(TableEntry, uuid2)
+	kb1 := key.Bytes()
+
+	oldtime, err := time.Parse(time.RFC3339, entry.Date)
+	if err != nil {
+		return nil, err
+	}
+	flakeid := rdb.TimeTravelReverseId(oldtime)
+
+	feeds := []string{entry.ProfileUuid}
+	for _, to := range entry.To {
+		if to != nil && to.Uuid != "" {
+			feeds = append(feeds, to.Uuid)
+		}
+	}
+	for _, feed := range feeds {
+		uuid1, err := uuid.FromString(feed)
+		if err != nil {
+			return nil, err
+		}
+		// only timestamp part of the reverse flake id is deterministic
+		prefix := NewUUIDKey(TableReverseEntryIndex, uuid1).Bytes()
+		prefix = append(prefix, flakeid[:8]...)
+		if err := deleteIndexValue(rdb, prefix, kb1); err != nil {
+			return nil, err
+		}
+	}
+
+	history := NewUUIDKey(TableEntryHistory, uuid2)
+	_, err = ForwardTableScan(rdb, history, func(i int, k, v []byte) error {
+		return rdb.Delete(k)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return key, rdb.Delete(kb1)
+}
 
+// deleteIndexValue deletes index rows under prefix which point to value.
+func deleteIndexValue(db *Store, prefix, value []byte) error {
+	iter := db.Iterator()
+	defer iter.Close()
+
+	var keys [][]byte
+	iter.Seek(prefix)
+	for ; iter.ValidForPrefix(prefix); iter.Next() {
+		kSlice := iter.Key()
+		vSlice := iter.Value()
+		if bytes.Equal(vSlice.Data(), value) {
+			keys = append(keys, append([]byte{}, kSlice.Data()...))
+		}
+		kSlice.Free()
+		vSlice.Free()
+	}
+	for _, k := range keys {
+		if err := db.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PutEntryHistory saves a snapshot of entry before it get edited.
+func PutEntryHistory(rdb *Store, entry *pb.Entry) error {
+	uuid1, err := uuid.FromString(entry.Id)
+	if err != nil {
+		return err
+	}
+	bytes, err := proto.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	key := NewUUIDFlakeKey(TableEntryHistory, uuid1, rdb.NextId())
+	return rdb.Put(key.Bytes(), bytes)
+}
+
+// GetEntryHistory returns previous versions of entry, oldest first.
+func GetEntryHistory(rdb *Store, uuidStr string) ([]*pb.Entry, error) {
+	uuid1, err := uuid.FromString(uuidStr)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*pb.Entry
+	key := NewUUIDKey(TableEntryHistory, uuid1)
+	_, err = ForwardTableScan(rdb, key, func(i int, k, v []byte) error {
+		entry := new(pb.Entry)
+		if err := proto.Unmarshal(v, entry); err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	return entries, err
+}
+
+func UpdateProfile(mdb *Store, profile *pb.Profile) error {
 	uuid1, err := uuid.FromString(profile.Uuid)
 	if err != nil {
 		return err
@@ -111,26 +350,24 @@ func UpdateProfile(mdb *Store, profile *pb.Profile) error {
 
 	// uuid map to user basic profile info
 	key := NewUUIDKey(TableProfile, uuid1)
-	if profile.RemoteKey != "" {
-		return mdb.Put(key.Bytes(), bytes)
-	}
-
-	// retrieve remote key
 	rawdata, err := mdb.Get(key.Bytes())
 	if err != nil {
 		return err
 	}
-
-	if len(rawdata) != 0 {
-		old := new(pb.Profile)
-		err = proto.Unmarshal(rawdata, old)
-		if err != nil {
-			return err
-		}
+	old := new(pb.Profile)
+	if err := proto.Unmarshal(rawdata, old); err != nil {
+		return err
+	}
+	// retrieve remote key
+	if profile.RemoteKey == "" {
 		profile.RemoteKey = old.RemoteKey
 	}
+	// sup id kept on renamed
+	if profile.SupId == "" {
+		profile.SupId = old.SupId
+	}
 
-	bytes, err = proto.Marshal(profile)
+	bytes, err := proto.Marshal(profile)
 	if err != nil {
 		return err
 	}
@@ -202,6 +439,205 @@ func GetArchiveHistory(mdb *Store, id string) (*pb.FeedJob, error) {
 	return job, nil
 }
 
+func PutPushSubscription(mdb *Store, sub *pb.Subscription) error {
+	bytes, err := proto.Marshal(sub)
+	if err != nil {
+		return err
+	}
+	key := NewMetaKey(TablePushSubscription, sub.Topic)
+	return mdb.Put(key.Bytes(), bytes)
+}
+
+// GetPushSubscription returns empty subscription if topic not subscribed.
+func GetPushSubscription(mdb *Store, topic string) (*pb.Subscription, error) {
+	key := NewMetaKey(TablePushSubscription, topic)
+	rawdata, err := mdb.Get(key.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	sub := new(pb.Subscription)
+	if err := proto.Unmarshal(rawdata, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func DeletePushSubscription(mdb *Store, topic string) error {
+	key := NewMetaKey(TablePushSubscription, topic)
+	return mdb.Delete(key.Bytes())
+}
+
+// HubSubscriptionMeta returns meta of subscription key, subscriptions of a
+// feed share the prefix "feed id/".
+func HubSubscriptionMeta(feedId, topic, callback string) string {
+	return feedId + "/" + topic + " " + callback
+}
+
+func PutHubSubscription(mdb *Store, sub *pb.HubSubscription) error {
+	bytes, err := proto.Marshal(sub)
+	if err != nil {
+		return err
+	}
+	key := NewMetaKey(TableHubSubscription, HubSubscriptionMeta(sub.FeedId, sub.Topic, sub.Callback))
+	return mdb.Put(key.Bytes(), bytes)
+}
+
+// GetHubSubscription returns empty subscription if not found.
+func GetHubSubscription(mdb *Store, meta string) (*pb.HubSubscription, error) {
+	rawdata, err := mdb.Get(NewMetaKey(TableHubSubscription, meta).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	sub := new(pb.HubSubscription)
+	if err := proto.Unmarshal(rawdata, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func DeleteHubSubscription(mdb *Store, meta string) error {
+	return mdb.Delete(NewMetaKey(TableHubSubscription, meta).Bytes())
+}
+
+// GetHubSubscriptions returns subscriptions of feed, expired included.
+func GetHubSubscriptions(mdb *Store, feedId string) ([]*pb.HubSubscription, error) {
+	var subs []*pb.HubSubscription
+	prefix := NewMetaKey(TableHubSubscription, feedId+"/")
+	_, err := ForwardTableScan(mdb, prefix, func(i int, k, v []byte) error {
+		sub := new(pb.HubSubscription)
+		if err := proto.Unmarshal(v, sub); err != nil {
+			return err
+		}
+		subs = append(subs, sub)
+		return nil
+	})
+	return subs, err
+}
+
+// PutHubDelivery keeps at most one pending delivery per subscription.
+func PutHubDelivery(mdb *Store, d *pb.HubDelivery) error {
+	bytes, err := proto.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return mdb.Put(NewMetaKey(TableHubDelivery, d.Key).Bytes(), bytes)
+}
+
+// GetHubDelivery returns empty delivery if not pending.
+func GetHubDelivery(mdb *Store, meta string) (*pb.HubDelivery, error) {
+	rawdata, err := mdb.Get(NewMetaKey(TableHubDelivery, meta).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	d := new(pb.HubDelivery)
+	if err := proto.Unmarshal(rawdata, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func DeleteHubDelivery(mdb *Store, meta string) error {
+	return mdb.Delete(NewMetaKey(TableHubDelivery, meta).Bytes())
+}
+
+func PutActorKey(mdb *Store, key *pb.ActorKey) error {
+	bytes, err := proto.Marshal(key)
+	if err != nil {
+		return err
+	}
+	return mdb.Put(NewMetaKey(TableActorKey, key.FeedId).Bytes(), bytes)
+}
+
+// GetActorKey returns empty key if not generated yet.
+func GetActorKey(mdb *Store, feedId string) (*pb.ActorKey, error) {
+	rawdata, err := mdb.Get(NewMetaKey(TableActorKey, feedId).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	key := new(pb.ActorKey)
+	if err := proto.Unmarshal(rawdata, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// FollowerMeta returns meta of follower key, followers of a feed share the
+// prefix "feed id/".
+func FollowerMeta(feedId, actor string) string {
+	return feedId + "/" + actor
+}
+
+func PutFollower(mdb *Store, f *pb.Follower) error {
+	bytes, err := proto.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return mdb.Put(NewMetaKey(TableFollower, FollowerMeta(f.FeedId, f.Actor)).Bytes(), bytes)
+}
+
+// GetFollower returns empty follower if not following.
+func GetFollower(mdb *Store, feedId, actor string) (*pb.Follower, error) {
+	rawdata, err := mdb.Get(NewMetaKey(TableFollower, FollowerMeta(feedId, actor)).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	f := new(pb.Follower)
+	if err := proto.Unmarshal(rawdata, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func DeleteFollower(mdb *Store, feedId, actor string) error {
+	return mdb.Delete(NewMetaKey(TableFollower, FollowerMeta(feedId, actor)).Bytes())
+}
+
+func GetFollowers(mdb *Store, feedId string) ([]*pb.Follower, error) {
+	var followers []*pb.Follower
+	prefix := NewMetaKey(TableFollower, feedId+"/")
+	_, err := ForwardTableScan(mdb, prefix, func(i int, k, v []byte) error {
+		f := new(pb.Follower)
+		if err := proto.Unmarshal(v, f); err != nil {
+			return err
+		}
+		followers = append(followers, f)
+		return nil
+	})
+	return followers, err
+}
+
+// PutActivityDelivery saves pending delivery, key assigned if empty so that
+// deliveries scanned in order queued.
+func PutActivityDelivery(mdb *Store, d *pb.ActivityDelivery) error {
+	if d.Key == "" {
+		id := mdb.NextId()
+		d.Key = hex.EncodeToString(id[:])
+	}
+	bytes, err := proto.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return mdb.Put(NewMetaKey(TableActivityDelivery, d.Key).Bytes(), bytes)
+}
+
+func DeleteActivityDelivery(mdb *Store, key string) error {
+	return mdb.Delete(NewMetaKey(TableActivityDelivery, key).Bytes())
+}
+
+// PutMediaMirror keeps at most one pending mirroring per entry.
+func PutMediaMirror(mdb *Store, m *pb.MediaMirror) error {
+	bytes, err := proto.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return mdb.Put(NewMetaKey(TableMediaMirror, m.EntryId).Bytes(), bytes)
+}
+
+func DeleteMediaMirror(mdb *Store, entryId string) error {
+	return mdb.Delete(NewMetaKey(TableMediaMirror, entryId).Bytes())
+}
+
 // uuid -> services
 // func SaveFeedServices(rdb *Store, uuidStr string, services []*pb.Service) error {
 // 	uuid1, err := uuid.FromString(uuidStr)
@@ -491,3 +927,245 @@ func DeleteComment(rdb *Store, profile *pb.Profile, entry *pb.Entry, commentId s
 	}
 	return entry, err
 }
+
+// HiddenList holds entries and authors a user has hidden.
+type HiddenList struct {
+	Entries map[string]bool
+	Authors map[string]bool
+}
+
+// Empty reports whether nothing hidden.
+func (h *HiddenList) Empty() bool {
+	return len(h.Entries) == 0 && len(h.Authors) == 0
+}
+
+func (h *HiddenList) Contains(entry *pb.Entry) bool {
+	if h.Entries[entry.Id] {
+		return true
+	}
+	return entry.From != nil && h.Authors[entry.From.Id]
+}
+
+func hiddenKey(pt PrefixTable, userUuid uuid.UUID, id string) *MetaKey {
+	return NewMetaKey(pt, userUuid.String()+"/"+id)
+}
+
+func setHidden(mdb *Store, pt PrefixTable, userUuid uuid.UUID, id string, hide bool) error {
+	key := hiddenKey(pt, userUuid, id)
+	if !hide {
+		return mdb.Delete(key.Bytes())
+	}
+	return mdb.Put(key.Bytes(), []byte(time.Now().Format(time.RFC3339)))
+}
+
+func HideEntry(mdb *Store, userUuid uuid.UUID, entryId string, hide bool) error {
+	return setHidden(mdb, TableHiddenEntry, userUuid, entryId, hide)
+}
+
+func HideAuthor(mdb *Store, userUuid uuid.UUID, authorId string, hide bool) error {
+	return setHidden(mdb, TableHiddenAuthor, userUuid, authorId, hide)
+}
+
+func GetHiddenList(mdb *Store, userUuid uuid.UUID) (*HiddenList, error) {
+	h := &HiddenList{
+		Entries: make(map[string]bool),
+		Authors: make(map[string]bool),
+	}
+	tables := map[PrefixTable]map[string]bool{
+		TableHiddenEntry:  h.Entries,
+		TableHiddenAuthor: h.Authors,
+	}
+	for pt, ids := range tables {
+		prefix := hiddenKey(pt, userUuid, "")
+		n := prefix.Len()
+		_, err := ForwardTableScan(mdb, prefix, func(i int, k, v []byte) error {
+			ids[string(k[n:])] = true
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// GetMediaHash returns hash of content mirrored from url, empty if not
+// mirrored.
+func GetMediaHash(mdb *Store, url string) (string, error) {
+	rawdata, err := mdb.Get(NewMetaKey(TableMediaURL, url).Bytes())
+	if err != nil {
+		return "", err
+	}
+	return string(rawdata), nil
+}
+
+// PutMediaHash indexes url to content of hash, blob of hash recorded
+// orphaned until referenced.
+func PutMediaHash(mdb *Store, url, hash, mimeType string) error {
+	if err := mdb.Put(NewMetaKey(TableMediaURL, url).Bytes(), []byte(hash)); err != nil {
+		return err
+	}
+	blob, err := GetMediaBlob(mdb, hash)
+	if err != nil {
+		return err
+	}
+	if blob.Hash != "" {
+		return nil
+	}
+	now := time.Now().Unix()
+	return putMediaBlob(mdb, &pb.MediaBlob{
+		Hash:     hash,
+		MimeType: mimeType,
+		Orphaned: now,
+		Created:  now,
+	})
+}
+
+// GetMediaBlob returns empty blob if not recorded.
+func GetMediaBlob(mdb *Store, hash string) (*pb.MediaBlob, error) {
+	rawdata, err := mdb.Get(NewMetaKey(TableMediaBlob, hash).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	blob := new(pb.MediaBlob)
+	if err := proto.Unmarshal(rawdata, blob); err != nil {
+		return nil, err
+	}
+	return blob, nil
+}
+
+// PutMediaThumbs records thumbnails of blob of hash, image of width and
+// height.
+func PutMediaThumbs(mdb *Store, hash string, width, height int32, thumbs []*pb.MediaThumb) error {
+	blob, err := GetMediaBlob(mdb, hash)
+	if err != nil {
+		return err
+	}
+	if blob.Hash == "" {
+		now := time.Now().Unix()
+		blob.Hash, blob.Orphaned, blob.Created = hash, now, now
+	}
+	blob.Width, blob.Height, blob.Thumbs = width, height, thumbs
+	return putMediaBlob(mdb, blob)
+}
+
+func putMediaBlob(mdb *Store, blob *pb.MediaBlob) error {
+	bytes, err := proto.Marshal(blob)
+	if err != nil {
+		return err
+	}
+	return mdb.Put(NewMetaKey(TableMediaBlob, blob.Hash).Bytes(), bytes)
+}
+
+func DeleteMediaBlob(mdb *Store, hash string) error {
+	return mdb.Delete(NewMetaKey(TableMediaBlob, hash).Bytes())
+}
+
+// GetMediaRefs returns hashes of blobs referenced by owner.
+func GetMediaRefs(mdb *Store, owner string) ([]string, error) {
+	rawdata, err := mdb.Get(NewMetaKey(TableMediaRefs, owner).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	refs := new(pb.MediaRefs)
+	if err := proto.Unmarshal(rawdata, refs); err != nil {
+		return nil, err
+	}
+	return refs.Hashes, nil
+}
+
+// AddMediaRefs references blobs of hashes from owner, blobs referenced
+// already counted once.
+func AddMediaRefs(mdb *Store, owner string, hashes ...string) error {
+	old, err := GetMediaRefs(mdb, owner)
+	if err != nil {
+		return err
+	}
+	return updateMediaRefs(mdb, owner, old, append(old, hashes...))
+}
+
+// SetMediaRefs replaces blobs referenced by owner with hashes, refs of owner
+// deleted if hashes empty.
+func SetMediaRefs(mdb *Store, owner string, hashes ...string) error {
+	old, err := GetMediaRefs(mdb, owner)
+	if err != nil {
+		return err
+	}
+	return updateMediaRefs(mdb, owner, old, hashes)
+}
+
+// updateMediaRefs counts refs of blobs from old hashes of owner to new.
+// Callers serialize updates, refs are read and written back.
+func updateMediaRefs(mdb *Store, owner string, old, hashes []string) error {
+	before := make(map[string]bool)
+	for _, hash := range old {
+		before[hash] = true
+	}
+	after := make(map[string]bool)
+	var refs []string
+	for _, hash := range hashes {
+		if hash == "" || after[hash] {
+			continue
+		}
+		after[hash] = true
+		refs = append(refs, hash)
+	}
+
+	now := time.Now().Unix()
+	count := func(hash string, delta int32) error {
+		blob, err := GetMediaBlob(mdb, hash)
+		if err != nil {
+			return err
+		}
+		if blob.Hash == "" {
+			blob.Hash, blob.Created = hash, now
+		}
+		blob.Refs += delta
+		if blob.Refs <= 0 {
+			blob.Refs, blob.Orphaned = 0, now
+		} else {
+			blob.Orphaned = 0
+		}
+		return putMediaBlob(mdb, blob)
+	}
+	for _, hash := range refs {
+		if !before[hash] {
+			if err := count(hash, 1); err != nil {
+				return err
+			}
+		}
+	}
+	for hash := range before {
+		if !after[hash] {
+			if err := count(hash, -1); err != nil {
+				return err
+			}
+		}
+	}
+
+	key := NewMetaKey(TableMediaRefs, owner).Bytes()
+	if len(refs) == 0 {
+		return mdb.Delete(key)
+	}
+	bytes, err := proto.Marshal(&pb.MediaRefs{Owner: owner, Hashes: refs})
+	if err != nil {
+		return err
+	}
+	return mdb.Put(key, bytes)
+}
+
+// GetOrphanedMedia returns blobs unreferenced since before.
+func GetOrphanedMedia(mdb *Store, before time.Time) ([]*pb.MediaBlob, error) {
+	var blobs []*pb.MediaBlob
+	_, err := ForwardTableScan(mdb, TableMediaBlob, func(i int, k, v []byte) error {
+		blob := new(pb.MediaBlob)
+		if err := proto.Unmarshal(v, blob); err != nil {
+			return err
+		}
+		if blob.Refs <= 0 && blob.Orphaned <= before.Unix() {
+			blobs = append(blobs, blob)
+		}
+		return nil
+	})
+	return blobs, err
+}
@@ -107,3 +107,57 @@ func TestPutEntry(t *testing.T) {
 		So(n, ShouldEqual, 2)
 	})
 }
+
+func TestMediaRefs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	Convey("Refs of media blobs counted by owners", t, func() {
+		hash, err := GetMediaHash(mdb, "http://m.friendfeed-media.com/a")
+		So(err, ShouldBeNil)
+		So(hash, ShouldEqual, "")
+
+		// indexed blob orphaned until referenced
+		So(PutMediaHash(mdb, "http://m.friendfeed-media.com/a", "h1", "image/png"), ShouldBeNil)
+		So(PutMediaHash(mdb, "http://m.friendfeed-media.com/b", "h1", "image/png"), ShouldBeNil)
+		hash, _ = GetMediaHash(mdb, "http://m.friendfeed-media.com/b")
+		So(hash, ShouldEqual, "h1")
+		blobs, err := GetOrphanedMedia(mdb, time.Now())
+		So(err, ShouldBeNil)
+		So(len(blobs), ShouldEqual, 1)
+		So(blobs[0].MimeType, ShouldEqual, "image/png")
+
+		So(AddMediaRefs(mdb, "e1", "h1", "h2"), ShouldBeNil)
+		So(AddMediaRefs(mdb, "e1", "h1"), ShouldBeNil)
+		So(AddMediaRefs(mdb, "e2", "h1"), ShouldBeNil)
+		blob, _ := GetMediaBlob(mdb, "h1")
+		So(blob.Refs, ShouldEqual, 2)
+		So(blob.MimeType, ShouldEqual, "image/png")
+		refs, _ := GetMediaRefs(mdb, "e1")
+		So(refs, ShouldResemble, []string{"h1", "h2"})
+		blobs, _ = GetOrphanedMedia(mdb, time.Now())
+		So(len(blobs), ShouldEqual, 0)
+
+		// owners deleted, blobs orphaned
+		So(SetMediaRefs(mdb, "e1"), ShouldBeNil)
+		refs, _ = GetMediaRefs(mdb, "e1")
+		So(len(refs), ShouldEqual, 0)
+		blob, _ = GetMediaBlob(mdb, "h1")
+		So(blob.Refs, ShouldEqual, 1)
+		blobs, _ = GetOrphanedMedia(mdb, time.Now())
+		So(len(blobs), ShouldEqual, 1)
+		So(blobs[0].Hash, ShouldEqual, "h2")
+		blobs, _ = GetOrphanedMedia(mdb, time.Now().Add(-time.Hour))
+		So(len(blobs), ShouldEqual, 0)
+
+		// picture replaced
+		So(SetMediaRefs(mdb, "e2", "h2"), ShouldBeNil)
+		blobs, _ = GetOrphanedMedia(mdb, time.Now())
+		So(len(blobs), ShouldEqual, 1)
+		So(blobs[0].Hash, ShouldEqual, "h1")
+
+		So(DeleteMediaBlob(mdb, "h1"), ShouldBeNil)
+		blob, _ = GetMediaBlob(mdb, "h1")
+		So(blob.Hash, ShouldEqual, "")
+	})
+}
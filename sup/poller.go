@@ -0,0 +1,110 @@
+package sup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Poller consumes sup feed of a publisher, reports feeds updated since the
+// last poll. Updates overlapped between polls are reported only once.
+type Poller struct {
+	Url    string
+	Client *http.Client
+
+	// sup id -> latest update seen
+	seen map[string]int64
+}
+
+func NewPoller(url string) *Poller {
+	return &Poller{
+		Url:    url,
+		Client: http.DefaultClient,
+		seen:   make(map[string]int64),
+	}
+}
+
+// SplitUrl splits sup url of a feed into sup feed url and sup id, eg:
+// http://friendfeed.com/api/sup.json#4ceb94af
+func SplitUrl(feedSupUrl string) (supUrl, supId string) {
+	i := strings.LastIndex(feedSupUrl, "#")
+	if i < 0 {
+		return feedSupUrl, ""
+	}
+	return feedSupUrl[:i], feedSupUrl[i+1:]
+}
+
+// Fetch fetches sup feed.
+func (p *Poller) Fetch(ctx context.Context) (*Feed, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.Url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("sup: %s returned %s", p.Url, resp.Status)
+	}
+
+	feed := new(Feed)
+	if err := json.NewDecoder(resp.Body).Decode(feed); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// Poll returns feeds updated since the last poll, the latest update of
+// each feed. Every feed in the sup feed is reported on the first poll.
+func (p *Poller) Poll(ctx context.Context) ([]Update, error) {
+	feed, err := p.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]int64)
+	var ids []string
+	for _, u := range feed.Updates {
+		updated, err := strconv.ParseInt(u[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		supId := u[0]
+		if last, ok := p.seen[supId]; ok && updated <= last {
+			continue
+		}
+		if _, ok := latest[supId]; !ok {
+			ids = append(ids, supId)
+		}
+		if updated > latest[supId] {
+			latest[supId] = updated
+		}
+	}
+
+	changed := make([]Update, 0, len(ids))
+	for _, supId := range ids {
+		p.seen[supId] = latest[supId]
+		changed = append(changed, Update{SupId: supId, Updated: time.Unix(latest[supId], 0)})
+	}
+	p.expire(feed)
+	return changed, nil
+}
+
+// expire forgets updates out of the sup feed window.
+func (p *Poller) expire(feed *Feed) {
+	since, err := time.Parse(time.RFC3339, feed.SinceTime)
+	if err != nil {
+		return
+	}
+	for supId, updated := range p.seen {
+		if updated < since.Unix() {
+			delete(p.seen, supId)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Package sup implements SUP (Simple Update Protocol) publisher, a compact
+// "ping feed" alerts consumers when feeds updated.
+//
+// See ff/python/sup.py for the reference implementation.
+package sup
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultPeriod in seconds, consumers should poll slightly faster.
+	DefaultPeriod = 60
+	// Overlap included in every sup feed in case consumers are late.
+	Overlap = 10 * time.Second
+
+	// Rel of sup link tag.
+	Rel = "http://api.friendfeed.com/2008/03#sup"
+	// Header announces sup id of feed.
+	Header = "X-SUP-ID"
+)
+
+// Periods available to consumers.
+var Periods = []int{DefaultPeriod, 300, 900}
+
+// NewId returns sup id of feed: a short prefix of md5 hash of feed id.
+// Sup ids should never change, a feed keeps the id assigned once.
+func NewId(feedId string) string {
+	sum := md5.Sum([]byte(feedId))
+	return hex.EncodeToString(sum[:])[:8]
+}
+
+// Url returns value for Header and link tag, eg:
+// http://example.com/sup.json#4ceb94af
+func Url(supUrl, supId string) string {
+	return supUrl + "#" + supId
+}
+
+// Update is a feed updated at time.
+type Update struct {
+	SupId   string
+	Updated time.Time
+}
+
+// Feed is the sup json document.
+type Feed struct {
+	UpdatedTime      string            `json:"updated_time"`
+	SinceTime        string            `json:"since_time"`
+	Period           int               `json:"period"`
+	AvailablePeriods map[string]string `json:"available_periods,omitempty"`
+	Updates          [][2]string       `json:"updates"`
+
+	updated time.Time
+}
+
+// Since returns the beginning of period covered by sup feed generated at
+// updated.
+func Since(updated time.Time, period int) time.Time {
+	return updated.Add(-time.Duration(period)*time.Second - Overlap)
+}
+
+// NewFeed generates sup feed of updates in the period, duplicated pings of
+// the same feed are merged into the latest one.
+func NewFeed(updated time.Time, period int, updates []Update) *Feed {
+	since := Since(updated, period)
+	latest := make(map[string]time.Time)
+	for _, u := range updates {
+		if u.Updated.Before(since) || u.Updated.After(updated) {
+			continue
+		}
+		if u.Updated.After(latest[u.SupId]) {
+			latest[u.SupId] = u.Updated
+		}
+	}
+
+	f := &Feed{
+		UpdatedTime: updated.UTC().Format(time.RFC3339),
+		SinceTime:   since.UTC().Format(time.RFC3339),
+		Period:      period,
+		Updates:     [][2]string{},
+		updated:     updated,
+	}
+	for supId, t := range latest {
+		f.Updates = append(f.Updates, [2]string{supId, strconv.FormatInt(t.Unix(), 10)})
+	}
+	sort.Slice(f.Updates, func(i, j int) bool {
+		if f.Updates[i][1] != f.Updates[j][1] {
+			return f.Updates[i][1] < f.Updates[j][1]
+		}
+		return f.Updates[i][0] < f.Updates[j][0]
+	})
+	return f
+}
+
+// SetAvailablePeriods lists sup feeds of all periods, url is formatted with
+// period, eg: http://example.com/sup.json?period=%d
+func (f *Feed) SetAvailablePeriods(url string) {
+	f.AvailablePeriods = make(map[string]string)
+	for _, p := range Periods {
+		f.AvailablePeriods[strconv.Itoa(p)] = fmt.Sprintf(url, p)
+	}
+}
+
+// Expires returns time sup feed should be cached until.
+func (f *Feed) Expires() time.Time {
+	return f.updated.Add(time.Duration(f.Period) * time.Second)
+}
+
+// ValidPeriod returns period if available, DefaultPeriod otherwise.
+func ValidPeriod(period int) int {
+	for _, p := range Periods {
+		if p == period {
+			return p
+		}
+	}
+	return DefaultPeriod
+}
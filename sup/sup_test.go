@@ -0,0 +1,90 @@
+package sup
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewId(t *testing.T) {
+	Convey("Sup id should be the same as reference implementation", t, func() {
+		So(NewId("ana"), ShouldEqual, "276b6c46")
+		So(NewId("bret"), ShouldEqual, "264400b7")
+		So(Url("http://mysite.com/sup.json", NewId("ana")), ShouldEqual, "http://mysite.com/sup.json#276b6c46")
+	})
+}
+
+func TestNewFeed(t *testing.T) {
+	Convey("Given updates, generate sup feed", t, func() {
+		updated := time.Date(2008, 12, 17, 20, 40, 45, 0, time.UTC)
+		since := Since(updated, DefaultPeriod)
+		So(since.Format(time.RFC3339), ShouldEqual, "2008-12-17T20:39:35Z")
+
+		updates := []Update{
+			{NewId("ana"), since},
+			{NewId("bret"), since.Add(1 * time.Second)},
+			{NewId("ana"), since.Add(2 * time.Second)},
+			{NewId("casey"), since.Add(-1 * time.Second)}, // too old
+			{NewId("dan"), updated.Add(1 * time.Second)},  // in future
+		}
+		f := NewFeed(updated, DefaultPeriod, updates)
+		f.SetAvailablePeriods("http://mysite.com/sup.json?period=%d")
+
+		data, err := json.Marshal(f)
+		So(err, ShouldBeNil)
+		So(string(data), ShouldEqual, `{"updated_time":"2008-12-17T20:40:45Z","since_time":"2008-12-17T20:39:35Z","period":60,`+
+			`"available_periods":{"300":"http://mysite.com/sup.json?period=300","60":"http://mysite.com/sup.json?period=60","900":"http://mysite.com/sup.json?period=900"},`+
+			`"updates":[["264400b7","1229546376"],["276b6c46","1229546377"]]}`)
+		So(f.Expires(), ShouldEqual, updated.Add(60*time.Second))
+	})
+
+	Convey("Empty sup feed should have empty updates", t, func() {
+		f := NewFeed(time.Now(), ValidPeriod(42), nil)
+		So(f.Period, ShouldEqual, DefaultPeriod)
+		data, _ := json.Marshal(f)
+		var v map[string]interface{}
+		So(json.Unmarshal(data, &v), ShouldBeNil)
+		So(v["updates"], ShouldResemble, []interface{}{})
+	})
+}
+
+func TestPoller(t *testing.T) {
+	Convey("Given sup publisher, poll feeds updated", t, func() {
+		now := time.Now()
+		updates := []Update{
+			{NewId("ana"), now.Add(-30 * time.Second)},
+			{NewId("bret"), now.Add(-20 * time.Second)},
+		}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(NewFeed(now, DefaultPeriod, updates))
+		}))
+		defer ts.Close()
+
+		p := NewPoller(ts.URL + "/sup.json")
+		changed, err := p.Poll(context.Background())
+		So(err, ShouldBeNil)
+		So(len(changed), ShouldEqual, 2)
+		So(changed[0].SupId, ShouldEqual, NewId("ana"))
+		So(changed[0].Updated.Unix(), ShouldEqual, updates[0].Updated.Unix())
+
+		// overlapped updates reported once
+		changed, err = p.Poll(context.Background())
+		So(err, ShouldBeNil)
+		So(len(changed), ShouldEqual, 0)
+
+		updates = append(updates, Update{NewId("ana"), now.Add(-10 * time.Second)})
+		changed, err = p.Poll(context.Background())
+		So(err, ShouldBeNil)
+		So(len(changed), ShouldEqual, 1)
+		So(changed[0].SupId, ShouldEqual, NewId("ana"))
+
+		supUrl, supId := SplitUrl(Url(p.Url, NewId("ana")))
+		So(supUrl, ShouldEqual, p.Url)
+		So(supId, ShouldEqual, NewId("ana"))
+	})
+}
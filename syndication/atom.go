@@ -0,0 +1,109 @@
+package syndication
+
+import (
+	"encoding/xml"
+	"strconv"
+	"time"
+
+	pb "github.com/yinhm/friendfeed/proto"
+	"github.com/yinhm/friendfeed/sup"
+)
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"feed"`
+	Xmlns    string      `xml:"xmlns,attr"`
+	XmlnsM   string      `xml:"xmlns:media,attr"`
+	Id       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel    string `xml:"rel,attr,omitempty"`
+	Href   string `xml:"href,attr"`
+	Type   string `xml:"type,attr,omitempty"`
+	Title  string `xml:"title,attr,omitempty"`
+	Length string `xml:"length,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	Uri  string `xml:"uri,omitempty"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	Id         string           `xml:"id"`
+	Title      atomText         `xml:"title"`
+	Updated    string           `xml:"updated"`
+	Published  string           `xml:"published"`
+	Author     atomAuthor       `xml:"author"`
+	Links      []atomLink       `xml:"link"`
+	Content    atomText         `xml:"content"`
+	Thumbnails []mediaThumbnail `xml:"media:thumbnail"`
+}
+
+// Atom renders feed as Atom 1.0 document.
+func Atom(feed *pb.Feed, opt *Options) ([]byte, error) {
+	link := opt.Link
+	if link == "" {
+		link = opt.feedURL(feed.Id)
+	}
+	doc := &atomFeed{
+		Xmlns:    nsAtom,
+		XmlnsM:   nsMedia,
+		Id:       link,
+		Title:    feed.Name,
+		Subtitle: feed.Description,
+		Updated:  updated(feed).Format(time.RFC3339),
+		Links: []atomLink{
+			{Rel: "alternate", Href: link, Type: "text/html"},
+		},
+	}
+	if opt.Self != "" {
+		doc.Links = append(doc.Links, atomLink{Rel: "self", Href: opt.Self, Type: "application/atom+xml"})
+	}
+	if opt.Sup != "" {
+		doc.Links = append(doc.Links, atomLink{Rel: sup.Rel, Href: opt.Sup, Type: contentTypeSup})
+	}
+	if opt.Hub != "" {
+		doc.Links = append(doc.Links, atomLink{Rel: "hub", Href: opt.Hub})
+	}
+
+	for _, e := range feed.Entries {
+		date := parseDate(e.Date).Format(time.RFC3339)
+		entry := atomEntry{
+			Id:        entryId(opt, e),
+			Title:     atomText{Type: "text", Body: title(e)},
+			Updated:   date,
+			Published: date,
+			Links: []atomLink{
+				{Rel: "alternate", Href: opt.entryURL(e.Id), Type: "text/html"},
+			},
+			Content:    atomText{Type: "html", Body: e.Body},
+			Thumbnails: thumbnails(e),
+		}
+		if e.From != nil {
+			entry.Author = atomAuthor{Name: e.From.Name, Uri: opt.feedURL(e.From.Id)}
+		}
+		if e.RawLink != "" {
+			entry.Links = append(entry.Links, atomLink{Rel: "related", Href: e.RawLink})
+		}
+		for _, f := range e.Files {
+			enclosure := atomLink{Rel: "enclosure", Href: f.Url, Type: f.Type, Title: f.Name}
+			if f.Size > 0 {
+				enclosure.Length = strconv.Itoa(int(f.Size))
+			}
+			entry.Links = append(entry.Links, enclosure)
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return marshal(doc)
+}
@@ -0,0 +1,119 @@
+package syndication
+
+import (
+	"encoding/xml"
+	"time"
+
+	pb "github.com/yinhm/friendfeed/proto"
+	"github.com/yinhm/friendfeed/sup"
+)
+
+type rssDoc struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	XmlnsA  string     `xml:"xmlns:atom,attr"`
+	XmlnsM  string     `xml:"xmlns:media,attr"`
+	XmlnsDC string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string        `xml:"title"`
+	AtomLinks     []rssAtomLink `xml:"atom:link"`
+	Link          string        `xml:"link"`
+	Description   string        `xml:"description"`
+	LastBuildDate string        `xml:"lastBuildDate"`
+	Items         []rssItem     `xml:"item"`
+}
+
+type rssAtomLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type rssGuid struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Id          string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	Url    string `xml:"url,attr"`
+	Length int32  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type mediaContent struct {
+	XMLName  xml.Name `xml:"media:content"`
+	Url      string   `xml:"url,attr"`
+	Type     string   `xml:"type,attr,omitempty"`
+	FileSize int32    `xml:"fileSize,attr,omitempty"`
+}
+
+type rssItem struct {
+	Title       string           `xml:"title"`
+	Link        string           `xml:"link"`
+	Guid        rssGuid          `xml:"guid"`
+	Description string           `xml:"description"`
+	PubDate     string           `xml:"pubDate"`
+	Creator     string           `xml:"dc:creator,omitempty"`
+	Enclosure   *rssEnclosure    `xml:"enclosure,omitempty"`
+	Contents    []mediaContent   `xml:"media:content"`
+	Thumbnails  []mediaThumbnail `xml:"media:thumbnail"`
+}
+
+// RSS renders feed as RSS 2.0 document. RSS allows one enclosure per item,
+// the first file is the enclosure, all files are listed as media content.
+func RSS(feed *pb.Feed, opt *Options) ([]byte, error) {
+	link := opt.Link
+	if link == "" {
+		link = opt.feedURL(feed.Id)
+	}
+	description := feed.Description
+	if description == "" {
+		description = feed.Name
+	}
+	doc := &rssDoc{
+		Version: "2.0",
+		XmlnsA:  nsAtom,
+		XmlnsM:  nsMedia,
+		XmlnsDC: nsDC,
+		Channel: rssChannel{
+			Title:         feed.Name,
+			Link:          link,
+			Description:   description,
+			LastBuildDate: updated(feed).Format(time.RFC1123Z),
+		},
+	}
+	if opt.Self != "" {
+		doc.Channel.AtomLinks = append(doc.Channel.AtomLinks, rssAtomLink{Rel: "self", Href: opt.Self, Type: "application/rss+xml"})
+	}
+	if opt.Sup != "" {
+		doc.Channel.AtomLinks = append(doc.Channel.AtomLinks, rssAtomLink{Rel: sup.Rel, Href: opt.Sup, Type: contentTypeSup})
+	}
+	if opt.Hub != "" {
+		doc.Channel.AtomLinks = append(doc.Channel.AtomLinks, rssAtomLink{Rel: "hub", Href: opt.Hub})
+	}
+
+	for _, e := range feed.Entries {
+		item := rssItem{
+			Title:       title(e),
+			Link:        opt.entryURL(e.Id),
+			Guid:        rssGuid{Id: entryId(opt, e)},
+			Description: e.Body,
+			PubDate:     parseDate(e.Date).Format(time.RFC1123Z),
+			Thumbnails:  thumbnails(e),
+		}
+		if e.From != nil {
+			item.Creator = e.From.Name
+		}
+		for i, f := range e.Files {
+			if i == 0 {
+				item.Enclosure = &rssEnclosure{Url: f.Url, Length: f.Size, Type: f.Type}
+			}
+			item.Contents = append(item.Contents, mediaContent{Url: f.Url, Type: f.Type, FileSize: f.Size})
+		}
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+	return marshal(doc)
+}
@@ -0,0 +1,127 @@
+// Package syndication renders feeds as Atom 1.0 and RSS 2.0 documents.
+//
+// Files are rendered as enclosures, thumbnails as Media RSS thumbnails.
+package syndication
+
+import (
+	"bytes"
+	"encoding/xml"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	uuid "github.com/satori/go.uuid"
+	pb "github.com/yinhm/friendfeed/proto"
+)
+
+const (
+	nsAtom  = "http://www.w3.org/2005/Atom"
+	nsMedia = "http://search.yahoo.com/mrss/"
+	nsDC    = "http://purl.org/dc/elements/1.1/"
+
+	contentTypeSup = "application/json"
+
+	maxTitleLen = 80
+
+	ContentTypeAtom = "application/atom+xml; charset=utf-8"
+	ContentTypeRSS  = "application/rss+xml; charset=utf-8"
+)
+
+// Options for urls in the rendered document.
+type Options struct {
+	// site url without trailing slash, eg: http://example.com
+	BaseURL string
+	// url of the html page of the feed
+	Link string
+	// url of the document itself
+	Self string
+	// sup url of the feed, eg: http://example.com/sup.json#4ceb94af
+	Sup string
+	// websub hub url
+	Hub string
+}
+
+func (o *Options) feedURL(id string) string {
+	return o.BaseURL + "/feed/" + id
+}
+
+func (o *Options) entryURL(id string) string {
+	return o.BaseURL + "/e/" + id
+}
+
+type mediaThumbnail struct {
+	XMLName xml.Name `xml:"media:thumbnail"`
+	Url     string   `xml:"url,attr"`
+	Width   int32    `xml:"width,attr,omitempty"`
+	Height  int32    `xml:"height,attr,omitempty"`
+}
+
+func thumbnails(e *pb.Entry) []mediaThumbnail {
+	var thumbs []mediaThumbnail
+	for _, t := range e.Thumbnails {
+		if t.Url == "" {
+			continue
+		}
+		thumbs = append(thumbs, mediaThumbnail{Url: t.Url, Width: t.Width, Height: t.Height})
+	}
+	return thumbs
+}
+
+var tagRe = regexp.MustCompile(`<[^>]*>`)
+
+// title returns plain text of entry body, truncated.
+func title(e *pb.Entry) string {
+	text := e.RawBody
+	if text == "" {
+		text = html.UnescapeString(tagRe.ReplaceAllString(e.Body, ""))
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) > maxTitleLen {
+		runes := []rune(text)
+		text = string(runes[:maxTitleLen-3]) + "..."
+	}
+	return text
+}
+
+// entryId returns uuid urn if possible, otherwise the entry url.
+func entryId(opt *Options, e *pb.Entry) string {
+	if u, err := uuid.FromString(e.Id); err == nil {
+		return "urn:uuid:" + u.String()
+	}
+	return opt.entryURL(e.Id)
+}
+
+func parseDate(date string) time.Time {
+	t, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return time.Time{}
+	}
+	return t.UTC()
+}
+
+// updated returns date of the latest entry, now if feed is empty.
+func updated(feed *pb.Feed) time.Time {
+	var latest time.Time
+	for _, e := range feed.Entries {
+		if t := parseDate(e.Date); t.After(latest) {
+			latest = t
+		}
+	}
+	if latest.IsZero() {
+		latest = time.Now().UTC()
+	}
+	return latest
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,158 @@
+package syndication
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	pb "github.com/yinhm/friendfeed/proto"
+)
+
+func testFeed() *pb.Feed {
+	return &pb.Feed{
+		Id:          "yinhm",
+		Name:        "yinhm",
+		Description: "Golang/Python/Linux",
+		Entries: []*pb.Entry{
+			{
+				Id:      "95a0d02fb680418ea1b7fb55baf1ee2d",
+				Date:    "2015-04-09T07:40:22Z",
+				Body:    `Hello <a href="http://golang.org/">golang</a> &amp; friends`,
+				RawBody: "Hello golang & friends",
+				From:    &pb.Feed{Id: "yinhm", Name: "yinhm"},
+				Thumbnails: []*pb.Thumbnail{
+					{Url: "http://m.example.com/t.png", Link: "http://m.example.com/o.png", Width: 120, Height: 80},
+				},
+				Files: []*pb.File{
+					{Url: "http://m.example.com/a.pdf", Type: "application/pdf", Name: "a.pdf", Size: 1024},
+					{Url: "http://m.example.com/b.zip", Type: "application/zip", Name: "b.zip"},
+				},
+			},
+			{
+				Id:   "2b43a9066074d120ed2e45494eea1797",
+				Date: "2015-04-10T08:00:00Z",
+				Body: "Second <b>entry</b>",
+				From: &pb.Feed{Id: "bret", Name: "Bret Taylor"},
+			},
+		},
+	}
+}
+
+var opt = &Options{
+	BaseURL: "http://example.com",
+	Self:    "http://example.com/feed/yinhm?format=atom",
+	Sup:     "http://example.com/sup.json#4ceb94af",
+	Hub:     "http://example.com/push/hub",
+}
+
+func TestAtom(t *testing.T) {
+	Convey("Given feed, render atom", t, func() {
+		data, err := Atom(testFeed(), opt)
+		So(err, ShouldBeNil)
+
+		var doc struct {
+			Id      string `xml:"id"`
+			Title   string `xml:"title"`
+			Updated string `xml:"updated"`
+			Entries []struct {
+				Id      string `xml:"id"`
+				Title   string `xml:"title"`
+				Updated string `xml:"updated"`
+				Content string `xml:"content"`
+				Author  string `xml:"author>name"`
+				Links   []struct {
+					Rel    string `xml:"rel,attr"`
+					Href   string `xml:"href,attr"`
+					Length string `xml:"length,attr"`
+				} `xml:"link"`
+				Thumbnails []struct {
+					Url   string `xml:"url,attr"`
+					Width string `xml:"width,attr"`
+				} `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+			} `xml:"entry"`
+		}
+		So(xml.Unmarshal(data, &doc), ShouldBeNil)
+		So(doc.Id, ShouldEqual, "http://example.com/feed/yinhm")
+		So(doc.Title, ShouldEqual, "yinhm")
+		So(doc.Updated, ShouldEqual, "2015-04-10T08:00:00Z")
+		So(strings.Contains(string(data), `rel="self" href="http://example.com/feed/yinhm?format=atom"`), ShouldBeTrue)
+		So(strings.Contains(string(data), `rel="http://api.friendfeed.com/2008/03#sup" href="http://example.com/sup.json#4ceb94af"`), ShouldBeTrue)
+		So(strings.Contains(string(data), `<link rel="hub" href="http://example.com/push/hub"></link>`), ShouldBeTrue)
+
+		So(len(doc.Entries), ShouldEqual, 2)
+		entry := doc.Entries[0]
+		So(entry.Id, ShouldEqual, "urn:uuid:95a0d02f-b680-418e-a1b7-fb55baf1ee2d")
+		So(entry.Title, ShouldEqual, "Hello golang & friends")
+		So(entry.Updated, ShouldEqual, "2015-04-09T07:40:22Z")
+		So(entry.Content, ShouldEqual, testFeed().Entries[0].Body)
+		So(entry.Author, ShouldEqual, "yinhm")
+		So(len(entry.Thumbnails), ShouldEqual, 1)
+		So(entry.Thumbnails[0].Url, ShouldEqual, "http://m.example.com/t.png")
+		So(entry.Thumbnails[0].Width, ShouldEqual, "120")
+
+		var enclosures []string
+		for _, link := range entry.Links {
+			if link.Rel == "enclosure" {
+				enclosures = append(enclosures, link.Href)
+			}
+		}
+		So(enclosures, ShouldResemble, []string{"http://m.example.com/a.pdf", "http://m.example.com/b.zip"})
+		So(entry.Links[0].Href, ShouldEqual, "http://example.com/e/95a0d02fb680418ea1b7fb55baf1ee2d")
+
+		// title from html body
+		So(doc.Entries[1].Title, ShouldEqual, "Second entry")
+	})
+}
+
+func TestRSS(t *testing.T) {
+	Convey("Given feed, render rss", t, func() {
+		data, err := RSS(testFeed(), opt)
+		So(err, ShouldBeNil)
+
+		var doc struct {
+			Version string `xml:"version,attr"`
+			Channel struct {
+				Title         string `xml:"title"`
+				Link          string `xml:"link"`
+				LastBuildDate string `xml:"lastBuildDate"`
+				Items         []struct {
+					Title     string `xml:"title"`
+					Link      string `xml:"link"`
+					Guid      string `xml:"guid"`
+					PubDate   string `xml:"pubDate"`
+					Enclosure struct {
+						Url    string `xml:"url,attr"`
+						Length string `xml:"length,attr"`
+						Type   string `xml:"type,attr"`
+					} `xml:"enclosure"`
+					Contents []struct {
+						Url string `xml:"url,attr"`
+					} `xml:"http://search.yahoo.com/mrss/ content"`
+					Thumbnails []struct {
+						Url string `xml:"url,attr"`
+					} `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+				} `xml:"item"`
+			} `xml:"channel"`
+		}
+		So(xml.Unmarshal(data, &doc), ShouldBeNil)
+		So(doc.Version, ShouldEqual, "2.0")
+		So(doc.Channel.Link, ShouldEqual, "http://example.com/feed/yinhm")
+		So(doc.Channel.LastBuildDate, ShouldEqual, "Fri, 10 Apr 2015 08:00:00 +0000")
+		So(strings.Contains(string(data), `<atom:link rel="http://api.friendfeed.com/2008/03#sup"`), ShouldBeTrue)
+
+		So(len(doc.Channel.Items), ShouldEqual, 2)
+		item := doc.Channel.Items[0]
+		So(item.Title, ShouldEqual, "Hello golang & friends")
+		So(item.Link, ShouldEqual, "http://example.com/e/95a0d02fb680418ea1b7fb55baf1ee2d")
+		So(item.Guid, ShouldEqual, "urn:uuid:95a0d02f-b680-418e-a1b7-fb55baf1ee2d")
+		So(item.PubDate, ShouldEqual, "Thu, 09 Apr 2015 07:40:22 +0000")
+		So(item.Enclosure.Url, ShouldEqual, "http://m.example.com/a.pdf")
+		So(item.Enclosure.Length, ShouldEqual, "1024")
+		So(item.Enclosure.Type, ShouldEqual, "application/pdf")
+		So(len(item.Contents), ShouldEqual, 2)
+		So(len(item.Thumbnails), ShouldEqual, 1)
+
+		So(doc.Channel.Items[1].Enclosure.Url, ShouldEqual, "")
+	})
+}
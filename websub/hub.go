@@ -0,0 +1,125 @@
+package websub
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MinLease and MaxLease bound leases asked by subscribers.
+	MinLease = time.Hour
+	MaxLease = 30 * 24 * time.Hour
+)
+
+// ErrGone means subscriber no longer wants content, subscription should be
+// deleted.
+var ErrGone = fmt.Errorf("websub: subscriber gone")
+
+// Lease returns lease granted for lease asked, DefaultLease if not asked.
+func Lease(asked time.Duration) time.Duration {
+	switch {
+	case asked <= 0:
+		return DefaultLease
+	case asked < MinLease:
+		return MinLease
+	case asked > MaxLease:
+		return MaxLease
+	}
+	return asked
+}
+
+// VerifyIntent asks callback to confirm subscribe or unsubscribe request by
+// echoing a challenge.
+func VerifyIntent(ctx context.Context, client *http.Client, mode, topic, callback string, lease time.Duration) error {
+	u, err := url.Parse(callback)
+	if err != nil {
+		return err
+	}
+	challenge := NewSecret()
+	query := u.Query()
+	query.Set("hub.mode", mode)
+	query.Set("hub.topic", topic)
+	query.Set("hub.challenge", challenge)
+	if mode == ModeSubscribe {
+		query.Set("hub.lease_seconds", strconv.Itoa(int(lease.Seconds())))
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("websub: callback returned %s", resp.Status)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(string(body)) != challenge {
+		return fmt.Errorf("websub: challenge mismatch")
+	}
+	return nil
+}
+
+// Deny tells callback subscription denied, errors ignored.
+func Deny(ctx context.Context, client *http.Client, topic, callback, reason string) {
+	u, err := url.Parse(callback)
+	if err != nil {
+		return
+	}
+	query := u.Query()
+	query.Set("hub.mode", ModeDenied)
+	query.Set("hub.topic", topic)
+	query.Set("hub.reason", reason)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return
+	}
+	if resp, err := client.Do(req); err == nil {
+		resp.Body.Close()
+	}
+}
+
+// Distribute posts content of topic to callback, content signed if secret
+// is not empty. ErrGone returned if callback responded 410.
+func Distribute(ctx context.Context, client *http.Client, hub, topic, callback, secret, contentType string, content []byte) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", callback, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Add("Link", fmt.Sprintf(`<%s>; rel="hub"`, hub))
+	req.Header.Add("Link", fmt.Sprintf(`<%s>; rel="self"`, topic))
+	if secret != "" {
+		req.Header.Set(SignatureHeader, Sign(secret, content))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusGone:
+		return ErrGone
+	case resp.StatusCode/100 != 2:
+		return fmt.Errorf("websub: callback returned %s", resp.Status)
+	}
+	return nil
+}
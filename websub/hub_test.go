@@ -0,0 +1,83 @@
+package websub
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLease(t *testing.T) {
+	Convey("Lease should be bounded", t, func() {
+		So(Lease(0), ShouldEqual, DefaultLease)
+		So(Lease(time.Minute), ShouldEqual, MinLease)
+		So(Lease(2*time.Hour), ShouldEqual, 2*time.Hour)
+		So(Lease(365*24*time.Hour), ShouldEqual, MaxLease)
+	})
+}
+
+func TestVerifyIntent(t *testing.T) {
+	Convey("Given subscriber, verify intent", t, func() {
+		echo := true
+		var lease string
+		subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			v, err := ParseVerification(r.URL.Query())
+			if err != nil || !echo {
+				http.NotFound(w, r)
+				return
+			}
+			lease = r.URL.Query().Get("hub.lease_seconds")
+			w.Write([]byte(v.Challenge))
+		}))
+		defer subscriber.Close()
+
+		ctx := context.Background()
+		callback := subscriber.URL + "/callback?topic=x"
+		err := VerifyIntent(ctx, http.DefaultClient, ModeSubscribe, "http://example.com/feed", callback, time.Hour)
+		So(err, ShouldBeNil)
+		So(lease, ShouldEqual, "3600")
+
+		echo = false
+		err = VerifyIntent(ctx, http.DefaultClient, ModeUnsubscribe, "http://example.com/feed", callback, 0)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestDistribute(t *testing.T) {
+	Convey("Given subscriber, distribute signed content", t, func() {
+		status := http.StatusNoContent
+		var body []byte
+		var header http.Header
+		subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ = ioutil.ReadAll(r.Body)
+			header = r.Header
+			w.WriteHeader(status)
+		}))
+		defer subscriber.Close()
+
+		ctx := context.Background()
+		content := []byte("<feed/>")
+		err := Distribute(ctx, http.DefaultClient, "http://example.com/push/hub", "http://example.com/feed", subscriber.URL, "secret", "application/atom+xml", content)
+		So(err, ShouldBeNil)
+		So(string(body), ShouldEqual, "<feed/>")
+		So(header.Get("Content-Type"), ShouldEqual, "application/atom+xml")
+		So(VerifySignature("secret", header.Get(SignatureHeader), body), ShouldBeTrue)
+		So(header["Link"], ShouldResemble, []string{
+			`<http://example.com/push/hub>; rel="hub"`,
+			`<http://example.com/feed>; rel="self"`,
+		})
+
+		status = http.StatusGone
+		err = Distribute(ctx, http.DefaultClient, "", "", subscriber.URL, "", "text/xml", content)
+		So(err, ShouldEqual, ErrGone)
+
+		status = http.StatusInternalServerError
+		err = Distribute(ctx, http.DefaultClient, "", "", subscriber.URL, "", "text/xml", content)
+		So(err, ShouldNotBeNil)
+		So(err, ShouldNotEqual, ErrGone)
+	})
+}
@@ -0,0 +1,177 @@
+// Package websub implements WebSub (formerly PubSubHubbub) subscriber and
+// the hub side of intent verification and content distribution.
+//
+// See https://www.w3.org/TR/websub/
+package websub
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ModeSubscribe   = "subscribe"
+	ModeUnsubscribe = "unsubscribe"
+	ModeDenied      = "denied"
+
+	// DefaultLease asked for subscriptions, hubs may choose another one.
+	DefaultLease = 10 * 24 * time.Hour
+
+	// SignatureHeader of content distribution request.
+	SignatureHeader = "X-Hub-Signature"
+)
+
+// Subscriber sends subscription requests to hubs.
+type Subscriber struct {
+	Client *http.Client
+}
+
+func NewSubscriber() *Subscriber {
+	return &Subscriber{Client: http.DefaultClient}
+}
+
+// Subscribe asks hub to deliver updates of topic to callback. Hub verifies
+// intent of subscriber asynchronously, see ParseVerification.
+func (s *Subscriber) Subscribe(ctx context.Context, hub, topic, callback, secret string, lease time.Duration) error {
+	form := url.Values{}
+	form.Set("hub.mode", ModeSubscribe)
+	form.Set("hub.topic", topic)
+	form.Set("hub.callback", callback)
+	if lease > 0 {
+		form.Set("hub.lease_seconds", strconv.Itoa(int(lease.Seconds())))
+	}
+	if secret != "" {
+		form.Set("hub.secret", secret)
+	}
+	return s.post(ctx, hub, form)
+}
+
+// Unsubscribe asks hub to stop delivering updates of topic to callback.
+func (s *Subscriber) Unsubscribe(ctx context.Context, hub, topic, callback string) error {
+	form := url.Values{}
+	form.Set("hub.mode", ModeUnsubscribe)
+	form.Set("hub.topic", topic)
+	form.Set("hub.callback", callback)
+	return s.post(ctx, hub, form)
+}
+
+func (s *Subscriber) post(ctx context.Context, hub string, form url.Values) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", hub, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// 202 Accepted, some hubs verify synchronously and return 204.
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("websub: hub %s returned %s", hub, resp.Status)
+	}
+	return nil
+}
+
+// CallbackURL returns callback of topic at base, topic is kept in query
+// since hubs may not tell topic on content distribution.
+func CallbackURL(base, topic string) string {
+	return base + "?topic=" + url.QueryEscape(topic)
+}
+
+// Verification is the intent verification request sent by hub to callback.
+type Verification struct {
+	Mode      string
+	Topic     string
+	Challenge string
+	Lease     time.Duration
+	Reason    string
+}
+
+// ParseVerification parses query of intent verification request.
+func ParseVerification(query url.Values) (*Verification, error) {
+	v := &Verification{
+		Mode:      query.Get("hub.mode"),
+		Topic:     query.Get("hub.topic"),
+		Challenge: query.Get("hub.challenge"),
+		Reason:    query.Get("hub.reason"),
+	}
+	if v.Topic == "" {
+		return nil, fmt.Errorf("websub: missing hub.topic")
+	}
+	switch v.Mode {
+	case ModeSubscribe, ModeUnsubscribe:
+		if v.Challenge == "" {
+			return nil, fmt.Errorf("websub: missing hub.challenge")
+		}
+	case ModeDenied:
+	default:
+		return nil, fmt.Errorf("websub: bad hub.mode %q", v.Mode)
+	}
+	if lease := query.Get("hub.lease_seconds"); lease != "" {
+		n, err := strconv.Atoi(lease)
+		if err != nil {
+			return nil, fmt.Errorf("websub: bad hub.lease_seconds")
+		}
+		v.Lease = time.Duration(n) * time.Second
+	}
+	return v, nil
+}
+
+// NewSecret returns random secret for signing content distribution.
+func NewSecret() string {
+	b := make([]byte, 20)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Sign returns signature of body in header format, eg: sha256=hex.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether signature header matches body, body is
+// not authentic if secret used but signature missing.
+func VerifySignature(secret, signature string, body []byte) bool {
+	if secret == "" {
+		return true
+	}
+	i := strings.Index(signature, "=")
+	if i < 0 {
+		return false
+	}
+	var h func() hash.Hash
+	switch signature[:i] {
+	case "sha1":
+		h = sha1.New
+	case "sha256":
+		h = sha256.New
+	case "sha384":
+		h = sha512.New384
+	case "sha512":
+		h = sha512.New
+	default:
+		return false
+	}
+	expected, err := hex.DecodeString(signature[i+1:])
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(h, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,88 @@
+package websub
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSubscribe(t *testing.T) {
+	Convey("Given hub, subscribe to topic", t, func() {
+		var form url.Values
+		hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.ParseForm()
+			form = r.PostForm
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer hub.Close()
+
+		s := NewSubscriber()
+		err := s.Subscribe(context.Background(), hub.URL, "http://example.com/feed", "http://ff.com/push/callback", "secret", time.Hour)
+		So(err, ShouldBeNil)
+		So(form.Get("hub.mode"), ShouldEqual, ModeSubscribe)
+		So(form.Get("hub.topic"), ShouldEqual, "http://example.com/feed")
+		So(form.Get("hub.callback"), ShouldEqual, "http://ff.com/push/callback")
+		So(form.Get("hub.secret"), ShouldEqual, "secret")
+		So(form.Get("hub.lease_seconds"), ShouldEqual, "3600")
+
+		err = s.Unsubscribe(context.Background(), hub.URL, "http://example.com/feed", "http://ff.com/push/callback")
+		So(err, ShouldBeNil)
+		So(form.Get("hub.mode"), ShouldEqual, ModeUnsubscribe)
+
+		hub.Close()
+		err = s.Subscribe(context.Background(), hub.URL, "http://example.com/feed", "http://ff.com/push/callback", "", 0)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestParseVerification(t *testing.T) {
+	Convey("Parse intent verification", t, func() {
+		query := url.Values{}
+		query.Set("hub.mode", "subscribe")
+		query.Set("hub.topic", "http://example.com/feed")
+		query.Set("hub.challenge", "abc")
+		query.Set("hub.lease_seconds", "86400")
+		v, err := ParseVerification(query)
+		So(err, ShouldBeNil)
+		So(v.Mode, ShouldEqual, ModeSubscribe)
+		So(v.Challenge, ShouldEqual, "abc")
+		So(v.Lease, ShouldEqual, 24*time.Hour)
+
+		query.Del("hub.challenge")
+		_, err = ParseVerification(query)
+		So(err, ShouldNotBeNil)
+
+		query.Set("hub.mode", "denied")
+		_, err = ParseVerification(query)
+		So(err, ShouldBeNil)
+
+		query.Set("hub.mode", "publish")
+		_, err = ParseVerification(query)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestSignature(t *testing.T) {
+	Convey("Verify content signature", t, func() {
+		body := []byte("<feed/>")
+		sig := Sign("secret", body)
+		So(VerifySignature("secret", sig, body), ShouldBeTrue)
+		So(VerifySignature("other", sig, body), ShouldBeFalse)
+		So(VerifySignature("secret", sig, []byte("<feed></feed>")), ShouldBeFalse)
+		So(VerifySignature("secret", "", body), ShouldBeFalse)
+		So(VerifySignature("secret", "md5=00", body), ShouldBeFalse)
+		// sha1 still used by older hubs
+		mac := hmac.New(sha1.New, []byte("secret"))
+		mac.Write(body)
+		So(VerifySignature("secret", "sha1="+hex.EncodeToString(mac.Sum(nil)), body), ShouldBeTrue)
+		So(VerifySignature("", "", body), ShouldBeTrue)
+	})
+}